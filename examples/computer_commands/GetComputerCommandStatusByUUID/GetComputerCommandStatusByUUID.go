@@ -0,0 +1,36 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"log"
+
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/jamfpro"
+)
+
+func main() {
+	// Define the path to the JSON configuration file
+	configFilePath := "/Users/dafyddwatkins/localtesting/jamfpro/clientconfig.json"
+
+	// Initialize the Jamf Pro client with the HTTP client configuration
+	client, err := jamfpro.BuildClientWithConfigFile(configFilePath)
+	if err != nil {
+		log.Fatalf("Failed to initialize Jamf Pro client: %v", err)
+	}
+
+	// Define the fuction parameters
+	commandUUID := "1c9f2a4e-0000-0000-0000-000000000000"
+
+	// Call the GetComputerCommandStatusByUUID function
+	command, err := client.GetComputerCommandStatusByUUID(commandUUID)
+	if err != nil {
+		log.Fatalf("Error fetching computer command status: %v", err)
+	}
+
+	// Pretty print the response
+	prettyXML, err := xml.MarshalIndent(command, "", "    ")
+	if err != nil {
+		log.Fatalf("Failed to generate pretty XML: %v", err)
+	}
+	fmt.Printf("%s\n", prettyXML)
+}
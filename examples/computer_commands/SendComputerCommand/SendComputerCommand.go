@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/jamfpro"
+)
+
+func main() {
+	// Define the path to the JSON configuration file
+	configFilePath := "/Users/dafyddwatkins/localtesting/jamfpro/clientconfig.json"
+
+	// Initialize the Jamf Pro client with the HTTP client configuration
+	client, err := jamfpro.BuildClientWithConfigFile(configFilePath)
+	if err != nil {
+		log.Fatalf("Failed to initialize Jamf Pro client: %v", err)
+	}
+
+	// Define the command and the computers to send it to
+	command := jamfpro.NewComputerDeviceLockCommand("123456", "This Mac has been locked by IT. Please call the helpdesk.")
+	targets := jamfpro.ComputerCommandTargets{
+		IDs:           []int{21},
+		SerialNumbers: []string{"C02XXXXXXXXX"},
+	}
+
+	// Call the SendComputerCommand function
+	response, err := client.SendComputerCommand(command, targets)
+	if err != nil {
+		log.Fatalf("Error sending computer command: %v", err)
+	}
+
+	for _, issued := range response.Commands {
+		fmt.Printf("%s sent to computer %d, command UUID: %s\n", issued.Name, issued.ComputerID, issued.CommandUUID)
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/jamfpro"
+)
+
+func main() {
+	// Define the path to the JSON configuration file
+	configFilePath := "/Users/dafyddwatkins/localtesting/jamfpro/clientconfig.json"
+
+	// Initialize the Jamf Pro client with the HTTP client configuration
+	client, err := jamfpro.BuildClientWithConfigFile(configFilePath)
+	if err != nil {
+		log.Fatalf("Failed to initialize Jamf Pro client: %v", err)
+	}
+
+	// The ID of the computer prestage and the serial numbers to add to its scope
+	prestageID := "123" // Replace with the actual ID
+	serialNumbers := []string{"C02XXXXXXXX1", "C02XXXXXXXX2"}
+
+	// Check whether any serial number is already scoped to another prestage
+	for _, serialNumber := range serialNumbers {
+		prestage, err := client.GetComputerPrestageByScopedSerialNumber(serialNumber)
+		if err == nil && prestage.ID != prestageID {
+			log.Fatalf("Serial number %s is already scoped to prestage %s", serialNumber, prestage.DisplayName)
+		}
+	}
+
+	// Add the serial numbers to the device scope of the computer prestage
+	deviceScope, err := client.AddDeviceScopeForComputerPrestageByID(prestageID, serialNumbers)
+	if err != nil {
+		log.Fatalf("Error adding serial numbers to computer prestage scope: %v", err)
+	}
+
+	// Pretty print the device scope in JSON
+	response, err := json.MarshalIndent(deviceScope, "", "    ") // Indent with 4 spaces
+	if err != nil {
+		log.Fatalf("Error marshaling device scope data: %v", err)
+	}
+	fmt.Println("Updated computer prestage scope:\n", string(response))
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/jamfpro"
+)
+
+func main() {
+	// Define the path to the JSON configuration file
+	configFilePath := "/Users/dafyddwatkins/localtesting/jamfpro/clientconfig.json"
+
+	// Initialize the Jamf Pro client with the HTTP client configuration
+	client, err := jamfpro.BuildClientWithConfigFile(configFilePath)
+	if err != nil {
+		log.Fatalf("Failed to initialize Jamf Pro client: %v", err)
+	}
+
+	// The identifier of the declaration to check, as listed in the DDM status report of the devices
+	declarationIdentifier := "com.example.softwareupdate.enforcement" // Replace with the actual identifier
+
+	statuses, err := client.GetComputersDeclarationStatus(declarationIdentifier, "")
+	if err != nil {
+		log.Fatalf("Error fetching declaration status: %v", err)
+	}
+
+	// Print the computers which have not applied the declaration
+	for _, status := range statuses {
+		if !status.Applied {
+			fmt.Printf("%s (%s) has not applied the declaration: reported=%t valid=%s %s\n",
+				status.Name, status.SerialNumber, status.Reported, status.Declaration.Valid, status.Error)
+		}
+	}
+
+	// Pretty print the declaration status in JSON
+	statusJSON, err := json.MarshalIndent(statuses, "", "    ")
+	if err != nil {
+		log.Fatalf("Error marshaling declaration status data: %v", err)
+	}
+	fmt.Println("Declaration Status:\n", string(statusJSON))
+}
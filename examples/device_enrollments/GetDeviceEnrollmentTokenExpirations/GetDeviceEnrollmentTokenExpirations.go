@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/jamfpro"
+)
+
+func main() {
+	// Define the path to the JSON configuration file
+	configFilePath := "/Users/dafyddwatkins/localtesting/jamfpro/clientconfig.json"
+
+	// Initialize the Jamf Pro client with the HTTP client configuration
+	client, err := jamfpro.BuildClientWithConfigFile(configFilePath)
+	if err != nil {
+		log.Fatalf("Failed to initialize Jamf Pro client: %v", err)
+	}
+
+	// Fetch the ADE server tokens which expire within the next 30 days
+	expirations, err := client.GetDeviceEnrollmentTokenExpirations(30 * 24 * time.Hour)
+	if err != nil {
+		log.Fatalf("Error fetching device enrollment token expirations: %v", err)
+	}
+
+	if len(expirations) == 0 {
+		fmt.Println("No ADE server tokens expire within 30 days")
+		return
+	}
+
+	for _, expiration := range expirations {
+		if expiration.Error != "" {
+			fmt.Printf("%s (ID %s) has no readable token expiration date: %s\n", expiration.Name, expiration.ID, expiration.Error)
+			continue
+		}
+		fmt.Printf("%s (ID %s) expires %s, in %d day(s)\n", expiration.Name, expiration.ID, expiration.ExpiresAt.Format("2006-01-02"), int(expiration.ExpiresIn.Hours()/24))
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/jamfpro"
+)
+
+func main() {
+	// Define the path to the JSON configuration file
+	configFilePath := "/Users/dafyddwatkins/localtesting/jamfpro/clientconfig.json"
+
+	// Initialize the Jamf Pro client with the HTTP client configuration
+	client, err := jamfpro.BuildClientWithConfigFile(configFilePath)
+	if err != nil {
+		log.Fatalf("Failed to initialize Jamf Pro client: %v", err)
+	}
+
+	// The ID of the device enrollment instance and the renewed server token downloaded from Apple Business Manager
+	enrollmentID := "1" // Replace with the actual ID
+	tokenFilePath := "/path/to/server_token.p7m"
+
+	// Call UpdateDeviceEnrollmentTokenFileByID function
+	enrollment, err := client.UpdateDeviceEnrollmentTokenFileByID(enrollmentID, tokenFilePath)
+	if err != nil {
+		log.Fatalf("Error renewing device enrollment token: %v", err)
+	}
+
+	// Pretty print the renewed device enrollment in JSON
+	response, err := json.MarshalIndent(enrollment, "", "    ") // Indent with 4 spaces
+	if err != nil {
+		log.Fatalf("Error marshaling device enrollment data: %v", err)
+	}
+	fmt.Println("Renewed device enrollment:\n", string(response))
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/jamfpro"
+)
+
+func main() {
+	// Define the path to the JSON configuration file
+	configFilePath := "/Users/dafyddwatkins/localtesting/jamfpro/clientconfig.json"
+
+	// Initialize the Jamf Pro client with the HTTP client configuration
+	client, err := jamfpro.BuildClientWithConfigFile(configFilePath)
+	if err != nil {
+		log.Fatalf("Failed to initialize Jamf Pro client: %v", err)
+	}
+
+	// Define the dock item
+	dockItem := &jamfpro.ResourceDockItemV1{
+		Name: "Safari",
+		Type: jamfpro.DockItemTypeApp,
+		Path: "file://localhost/Applications/Safari.app/",
+	}
+
+	// Call CreateDockItemV1 function
+	created, err := client.CreateDockItemV1(dockItem)
+	if err != nil {
+		log.Fatalf("Error creating dock item: %v", err)
+	}
+
+	// Pretty print the created dock item in JSON
+	response, err := json.MarshalIndent(created, "", "    ") // Indent with 4 spaces
+	if err != nil {
+		log.Fatalf("Error marshaling dock item data: %v", err)
+	}
+	fmt.Println("Created dock item:\n", string(response))
+}
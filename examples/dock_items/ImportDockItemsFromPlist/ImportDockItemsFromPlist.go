@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/jamfpro"
+)
+
+func main() {
+	// Define the path to the JSON configuration file
+	configFilePath := "/Users/dafyddwatkins/localtesting/jamfpro/clientconfig.json"
+
+	// Dock preferences copied from the reference Mac: ~/Library/Preferences/com.apple.dock.plist
+	dockPlistPath := "/Users/dafyddwatkins/localtesting/dock/com.apple.dock.plist"
+
+	// Initialize the Jamf Pro client with the HTTP client configuration
+	client, err := jamfpro.BuildClientWithConfigFile(configFilePath)
+	if err != nil {
+		log.Fatalf("Failed to initialize Jamf Pro client: %v", err)
+	}
+
+	data, err := os.ReadFile(dockPlistPath)
+	if err != nil {
+		log.Fatalf("Failed to read dock plist: %v", err)
+	}
+
+	// Create the dock items which do not exist yet; set DryRun to preview
+	options := &jamfpro.DockItemImportOptions{
+		DryRun:         false,
+		UpdateExisting: true,
+	}
+
+	results, err := client.ImportDockItemsFromPlist(data, options)
+	for _, result := range results {
+		fmt.Printf("%-8s %-6s %s (%s)\n", result.Action, result.DockItem.Type, result.DockItem.Name, result.DockItem.Path)
+	}
+	if err != nil {
+		log.Fatalf("Error importing dock items: %v", err)
+	}
+}
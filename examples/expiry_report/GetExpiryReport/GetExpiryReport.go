@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/jamfpro"
+)
+
+func main() {
+	// Define the paths to the JSON configuration files, one per Jamf Pro tenant
+	configFilePaths := map[string]string{
+		"production": "/Users/dafyddwatkins/localtesting/jamfpro/clientconfig.json",
+		"staging":    "/Users/dafyddwatkins/localtesting/jamfpro/clientconfig-staging.json",
+	}
+
+	var reports []*jamfpro.ExpiryReport
+	for instance, configFilePath := range configFilePaths {
+		// Initialize the Jamf Pro client with the HTTP client configuration
+		client, err := jamfpro.BuildClientWithConfigFile(configFilePath)
+		if err != nil {
+			log.Fatalf("Failed to initialize Jamf Pro client for %s: %v", instance, err)
+		}
+
+		// Collect the expiring credentials of the tenant, warning 30 days and escalating 7 days ahead
+		report, err := client.GetExpiryReport(&jamfpro.ExpiryReportOptions{
+			Instance:          instance,
+			WarningThreshold:  jamfpro.DefaultExpiryWarningThreshold,
+			CriticalThreshold: jamfpro.DefaultExpiryCriticalThreshold,
+		})
+		if err != nil {
+			log.Fatalf("Error collecting expiry report for %s: %v", instance, err)
+		}
+		reports = append(reports, report)
+	}
+
+	// Merge the reports and write them as CSV
+	report := jamfpro.MergeExpiryReports(reports...)
+	if err := report.WriteCSV(os.Stdout); err != nil {
+		log.Fatalf("Error writing expiry report: %v", err)
+	}
+
+	for _, reportErr := range report.Errors {
+		fmt.Fprintf(os.Stderr, "%s: could not collect %s: %s\n", reportErr.Instance, reportErr.Kind, reportErr.Error)
+	}
+
+	// Exit non-zero when anything needs attention, for use in a scheduled job
+	if alerts := report.Alerts(); len(alerts) > 0 {
+		fmt.Fprintf(os.Stderr, "%d credential(s) expired or expiring soon\n", len(alerts))
+		os.Exit(1)
+	}
+}
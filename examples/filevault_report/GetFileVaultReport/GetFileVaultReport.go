@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/jamfpro"
+)
+
+func main() {
+	// Define the path to the JSON configuration file
+	configFilePath := "/Users/dafyddwatkins/localtesting/jamfpro/clientconfig.json"
+
+	// Initialize the Jamf Pro client with the HTTP client configuration
+	client, err := jamfpro.BuildClientWithConfigFile(configFilePath)
+	if err != nil {
+		log.Fatalf("Failed to initialize Jamf Pro client: %v", err)
+	}
+
+	// Build the FileVault and recovery lock report of all computers
+	report, err := client.GetFileVaultReport("")
+	if err != nil {
+		log.Fatalf("Error building FileVault report: %v", err)
+	}
+
+	// Write the report as CSV for the auditors
+	file, err := os.Create("filevault_report.csv")
+	if err != nil {
+		log.Fatalf("Error creating report file: %v", err)
+	}
+	defer file.Close()
+
+	if err := report.WriteCSV(file); err != nil {
+		log.Fatalf("Error writing FileVault report: %v", err)
+	}
+
+	// Escrowed keys which Jamf Pro has not validated yet are not an issue, but worth following up
+	for _, entry := range report.UnvalidatedPersonalRecoveryKeys() {
+		fmt.Printf("Personal recovery key of %s (ID %s) not validated yet\n", entry.Name, entry.ComputerID)
+	}
+
+	// Show what would be done for the non-compliant computers. Set DryRun to false to send the recovery lock
+	// commands and scope the personal recovery key rotation policy.
+	options := &jamfpro.FileVaultRemediationOptions{
+		SetRecoveryLock:            true,
+		RotatePersonalRecoveryKeys: true,
+		DryRun:                     true,
+	}
+
+	actions, err := client.RemediateFileVaultReport(report, options)
+	if err != nil {
+		log.Fatalf("Error remediating FileVault report: %v", err)
+	}
+
+	// Pretty print the remediation actions in JSON
+	actionsJSON, err := json.MarshalIndent(actions, "", "    ")
+	if err != nil {
+		log.Fatalf("Error marshaling remediation actions: %v", err)
+	}
+	fmt.Printf("%d of %d computers non-compliant, remediation actions:\n%s\n", len(report.NonCompliant()), len(report.Computers), string(actionsJSON))
+}
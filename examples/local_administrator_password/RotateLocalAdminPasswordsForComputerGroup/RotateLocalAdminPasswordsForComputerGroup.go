@@ -0,0 +1,42 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/jamfpro"
+)
+
+func main() {
+	// Define the path to the JSON configuration file
+	configFilePath := "/Users/dafyddwatkins/localtesting/jamfpro/clientconfig.json"
+
+	// Initialize the Jamf Pro client with the HTTP client configuration
+	client, err := jamfpro.BuildClientWithConfigFile(configFilePath)
+	if err != nil {
+		log.Fatalf("Failed to initialize Jamf Pro client: %v", err)
+	}
+
+	// The ID of the smart or static computer group whose LAPS passwords to rotate
+	groupID := 1 // Replace with the actual ID
+
+	options := &jamfpro.LAPSRotationOptions{
+		MaxConcurrency: 5,
+		OnResult: func(result jamfpro.LAPSRotationResult) {
+			fmt.Printf("%s %s rotated=%t %s\n", result.ComputerName, result.Username, result.Rotated, result.Error)
+		},
+	}
+
+	results, err := client.RotateLocalAdminPasswordsForComputerGroup(groupID, options)
+	if err != nil {
+		log.Fatalf("Error rotating LAPS passwords: %v", err)
+	}
+
+	// Pretty print the rotation results in JSON
+	resultsJSON, err := json.MarshalIndent(results, "", "    ")
+	if err != nil {
+		log.Fatalf("Error marshaling rotation results: %v", err)
+	}
+	fmt.Println("LAPS Rotation Results:\n", string(resultsJSON))
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"crypto/x509"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/jamfpro"
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/mobileconfig"
+)
+
+func main() {
+	// Define the path to the JSON configuration file
+	configFilePath := "/Users/dafyddwatkins/localtesting/jamfpro/clientconfig.json"
+
+	// Initialize the Jamf Pro client with the HTTP client configuration
+	client, err := jamfpro.BuildClientWithConfigFile(configFilePath)
+	if err != nil {
+		log.Fatalf("Failed to initialize Jamf Pro client: %v", err)
+	}
+
+	// Load the signing certificate and key
+	signer, err := mobileconfig.NewSignerFromFiles("/Users/dafyddwatkins/localtesting/signing/cert.pem", "/Users/dafyddwatkins/localtesting/signing/key.pem")
+	if err != nil {
+		log.Fatalf("Failed to load signer: %v", err)
+	}
+
+	// Load and sign the profile
+	payloads, err := mobileconfig.ParseFile("/Users/dafyddwatkins/GitHub/deploymenttheory/go-api-sdk-jamfpro/examples/support_files/accessibility-chara-nosub-test.mobileconfig")
+	if err != nil {
+		log.Fatalf("Failed to parse profile: %v", err)
+	}
+
+	signed, err := payloads.Sign(signer)
+	if err != nil {
+		log.Fatalf("Failed to sign profile: %v", err)
+	}
+
+	// The Classic API only accepts unsigned payloads, so the signature is verified against the trusted
+	// root and the signed content is uploaded
+	rootPEM, err := os.ReadFile("/Users/dafyddwatkins/localtesting/signing/root.pem")
+	if err != nil {
+		log.Fatalf("Failed to read root certificate: %v", err)
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(rootPEM) {
+		log.Fatalf("No certificate found in root certificate file")
+	}
+
+	content, signerDetails, err := mobileconfig.Verify(signed, roots)
+	if err != nil {
+		log.Fatalf("Failed to verify signed profile: %v", err)
+	}
+	fmt.Printf("Profile signed by %s\n", signerDetails.Subject)
+
+	profile := &jamfpro.ResourceMacOSConfigurationProfile{
+		General: jamfpro.MacOSConfigurationProfileSubsetGeneral{
+			Name:               "accessibility-signed-test",
+			Site:               jamfpro.SharedResourceSite{ID: -1, Name: "None"},
+			Category:           jamfpro.SharedResourceCategory{ID: -1, Name: "No category assigned"},
+			DistributionMethod: "Install Automatically",
+			Level:              "computer",
+			RedeployOnUpdate:   "Newly Assigned",
+			Payloads:           string(content),
+		},
+	}
+
+	created, err := client.CreateMacOSConfigurationProfile(profile)
+	if err != nil {
+		log.Fatalf("Error creating macOS Configuration Profile: %v", err)
+	}
+
+	fmt.Printf("Successfully created macOS Configuration Profile with ID: %d\n", created.ID)
+}
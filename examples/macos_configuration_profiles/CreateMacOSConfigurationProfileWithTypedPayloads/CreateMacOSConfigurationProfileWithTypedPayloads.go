@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/jamfpro"
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/mobileconfig"
+)
+
+func main() {
+	// Define the path to the JSON configuration file
+	configFilePath := "/Users/dafyddwatkins/localtesting/jamfpro/clientconfig.json"
+
+	// Initialize the Jamf Pro client with the HTTP client configuration
+	client, err := jamfpro.BuildClientWithConfigFile(configFilePath)
+	if err != nil {
+		log.Fatalf("Failed to initialize Jamf Pro client: %v", err)
+	}
+
+	// Build the configuration profile payloads. Payload identifiers and UUIDs are generated
+	// deterministically from the profile identifier when the profile is marshaled.
+	allowed := true
+	payloads := mobileconfig.New("com.example.security-agent", "Security Agent")
+	payloads.PayloadScope = mobileconfig.ProfileScopeSystem
+	payloads.AddPayload(
+		mobileconfig.NewPPPCPayload().AddRule(mobileconfig.PPPCServiceSystemPolicyAllFiles, mobileconfig.PPPCServiceRule{
+			Identifier:      "com.example.agent",
+			IdentifierType:  "bundleID",
+			CodeRequirement: `identifier "com.example.agent" and anchor apple generic`,
+			Allowed:         &allowed,
+		}),
+		mobileconfig.NewSystemExtensionsPayload().AllowExtension("ABCDE12345", "com.example.agent.extension"),
+		mobileconfig.NewLoginItemsPayload(mobileconfig.LoginItemRule{
+			RuleType:  mobileconfig.LoginItemRuleTeamIdentifier,
+			RuleValue: "ABCDE12345",
+		}),
+	)
+
+	profile := &jamfpro.ResourceMacOSConfigurationProfile{
+		General: jamfpro.MacOSConfigurationProfileSubsetGeneral{
+			Name:               "Security Agent",
+			Site:               jamfpro.SharedResourceSite{ID: -1, Name: "None"},
+			Category:           jamfpro.SharedResourceCategory{ID: -1, Name: "No category assigned"},
+			DistributionMethod: "Install Automatically",
+			Level:              "computer",
+			RedeployOnUpdate:   "Newly Assigned",
+			Profile:            payloads,
+		},
+	}
+
+	// Call the CreateMacOSConfigurationProfile function
+	created, err := client.CreateMacOSConfigurationProfile(profile)
+	if err != nil {
+		log.Fatalf("Error creating macOS Configuration Profile: %v", err)
+	}
+
+	fmt.Printf("Successfully created macOS Configuration Profile with ID: %d\n", created.ID)
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/jamfpro"
+)
+
+func main() {
+	// Define the path to the JSON configuration file
+	configFilePath := "/Users/dafyddwatkins/localtesting/jamfpro/clientconfig.json"
+
+	// Initialize the Jamf Pro client with the HTTP client configuration
+	client, err := jamfpro.BuildClientWithConfigFile(configFilePath)
+	if err != nil {
+		log.Fatalf("Failed to initialize Jamf Pro client: %v", err)
+	}
+
+	// Define groupId and groupType for the request
+	groupId := "55" // Example group ID
+	groupType := jamfpro.ManagedSoftwareUpdateGroupTypeComputer
+
+	// Track the progress of the group's managed software update plans
+	progress, err := client.TrackManagedSoftwareUpdatePlansByGroupID(groupId, groupType)
+	if err != nil {
+		log.Fatalf("Error tracking managed software update plans by group ID: %v", err)
+	}
+
+	// Print the devices whose update failed, with the reason
+	for _, device := range progress.Devices {
+		if device.Phase == jamfpro.ManagedSoftwareUpdatePhaseFailed {
+			fmt.Printf("Device %s failed: %v\n", device.DeviceId, device.FailureReasons)
+		}
+	}
+
+	// Pretty print the progress of the group in json
+	progressJSON, err := json.MarshalIndent(progress, "", "    ") // Indent with 4 spaces
+	if err != nil {
+		log.Fatalf("Error marshaling managed software update progress data: %v", err)
+	}
+	fmt.Println("Managed software update progress for group:\n", string(progressJSON))
+}
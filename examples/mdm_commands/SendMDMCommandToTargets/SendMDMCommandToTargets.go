@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/jamfpro"
+)
+
+func main() {
+	// Define the path to the JSON configuration file
+	configFilePath := "/Users/dafyddwatkins/localtesting/jamfpro/clientconfig.json"
+
+	// Initialize the Jamf Pro client with the HTTP client configuration
+	client, err := jamfpro.BuildClientWithConfigFile(configFilePath)
+	if err != nil {
+		log.Fatalf("Failed to initialize Jamf Pro client: %v", err)
+	}
+
+	// Wipe the devices while keeping their eSIM data plans
+	commandData := jamfpro.NewMDMEraseDeviceCommand(true, false)
+	targets := jamfpro.MDMCommandTargets{
+		MobileDeviceIDs: []string{"1", "2"},
+	}
+
+	// Call the SendMDMCommandToTargets function
+	response, err := client.SendMDMCommandToTargets(commandData, targets)
+	if err != nil {
+		log.Fatalf("Error sending MDM command: %v", err)
+	}
+
+	fmt.Printf("%s sent, command UUIDs: %v\n", commandData.CommandType, response.UUIDs())
+}
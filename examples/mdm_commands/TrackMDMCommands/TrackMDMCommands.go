@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/jamfpro"
+)
+
+func main() {
+	// Define the path to the JSON configuration file
+	configFilePath := "/Users/dafyddwatkins/localtesting/jamfpro/clientconfig.json"
+
+	// Initialize the Jamf Pro client with the HTTP client configuration
+	client, err := jamfpro.BuildClientWithConfigFile(configFilePath)
+	if err != nil {
+		log.Fatalf("Failed to initialize Jamf Pro client: %v", err)
+	}
+
+	// Lock the computers and remember when the command was sent
+	issuedAt := time.Now()
+	command := jamfpro.NewComputerDeviceLockCommand("123456", "This Mac has been locked by IT.")
+	response, err := client.SendComputerCommand(command, jamfpro.ComputerCommandTargets{IDs: []int{21, 22}})
+	if err != nil {
+		log.Fatalf("Error sending computer command: %v", err)
+	}
+
+	// Block until every computer has completed or failed the command, or 20 minutes have passed
+	options := &jamfpro.MDMCommandTrackerOptions{
+		PollInterval: 30 * time.Second,
+		Timeout:      20 * time.Minute,
+		OnUpdate: func(result jamfpro.MDMCommandResult) {
+			fmt.Printf("computer %d: %s\n", result.DeviceID, result.Status)
+		},
+	}
+
+	results, err := client.TrackMDMCommands(context.Background(), jamfpro.TrackedComputerCommands(response, issuedAt), options)
+	if err != nil {
+		log.Fatalf("Error tracking computer commands: %v", err)
+	}
+
+	for _, result := range results {
+		if result.Status != jamfpro.MDMCommandTrackerStatusCompleted {
+			fmt.Printf("computer %d did not complete %s: %s (%s)\n", result.DeviceID, result.CommandName, result.Status, result.FailureReason)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/jamfpro"
+)
+
+func main() {
+	// Define the path to the JSON configuration file
+	configFilePath := "/Users/dafyddwatkins/localtesting/jamfpro/clientconfig.json"
+
+	// Initialize the Jamf Pro client with the HTTP client configuration
+	client, err := jamfpro.BuildClientWithConfigFile(configFilePath)
+	if err != nil {
+		log.Fatalf("Failed to initialize Jamf Pro client: %v", err)
+	}
+
+	// Define the command and the mobile devices to send it to
+	command := jamfpro.NewMobileDeviceEnableLostModeCommand("This iPad has been lost.", "+44 0000 000000", "Reward if found")
+	targets := jamfpro.MobileDeviceCommandTargets{
+		IDs:   []int{1, 2},
+		Names: []string{"Classroom iPad 03"},
+	}
+
+	// Call the SendMobileDeviceCommand function
+	response, err := client.SendMobileDeviceCommand(command, targets)
+	if err != nil {
+		log.Fatalf("Error sending mobile device command: %v", err)
+	}
+
+	fmt.Printf("%s sent, command UUIDs: %v\n", command.General.Command, response.UUIDs())
+}
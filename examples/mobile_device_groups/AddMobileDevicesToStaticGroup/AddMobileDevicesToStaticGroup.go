@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/jamfpro"
+)
+
+func main() {
+	// Define the path to the JSON configuration file
+	configFilePath := "/Users/dafyddwatkins/localtesting/jamfpro/clientconfig.json"
+
+	// Initialize the Jamf Pro client with the HTTP client configuration
+	client, err := jamfpro.BuildClientWithConfigFile(configFilePath)
+	if err != nil {
+		log.Fatalf("Failed to initialize Jamf Pro client: %v", err)
+	}
+
+	// Look up the static group by name
+	group, err := client.GetMobileDeviceStaticGroupByName("Year 7 iPads")
+	if err != nil {
+		log.Fatalf("Error fetching static group: %v", err)
+	}
+
+	// Add and remove devices; other members of the group are left untouched
+	joining := []string{"101", "102", "103"}
+	leaving := []string{"87", "88"}
+
+	if err := client.AddMobileDevicesToStaticGroup(group.GroupID, joining); err != nil {
+		log.Fatalf("Error adding mobile devices: %v", err)
+	}
+	if err := client.RemoveMobileDevicesFromStaticGroup(group.GroupID, leaving); err != nil {
+		log.Fatalf("Error removing mobile devices: %v", err)
+	}
+
+	fmt.Printf("Added %d and removed %d mobile devices from %s\n", len(joining), len(leaving), group.GroupName)
+}
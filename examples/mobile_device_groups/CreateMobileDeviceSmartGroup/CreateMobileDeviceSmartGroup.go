@@ -0,0 +1,43 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/jamfpro"
+)
+
+func main() {
+	// Define the path to the JSON configuration file
+	configFilePath := "/Users/dafyddwatkins/localtesting/jamfpro/clientconfig.json"
+
+	// Initialize the Jamf Pro client with the HTTP client configuration
+	client, err := jamfpro.BuildClientWithConfigFile(configFilePath)
+	if err != nil {
+		log.Fatalf("Failed to initialize Jamf Pro client: %v", err)
+	}
+
+	// Define the smart group
+	group := &jamfpro.ResourceMobileDeviceSmartGroup{
+		GroupName:        "Student iPads on iPadOS 16",
+		GroupDescription: "Student iPads still on iPadOS 16",
+		Criteria: []jamfpro.MobileDeviceSmartGroupSubsetCriteria{
+			{Name: "Model", Priority: 0, AndOr: "and", SearchType: "like", Value: "iPad"},
+			{Name: "OS Version", Priority: 1, AndOr: "and", SearchType: "like", Value: "16."},
+		},
+	}
+
+	// Call CreateMobileDeviceSmartGroup function
+	created, err := client.CreateMobileDeviceSmartGroup(group)
+	if err != nil {
+		log.Fatalf("Error creating smart group: %v", err)
+	}
+
+	// Pretty print the created smart group in JSON
+	response, err := json.MarshalIndent(created, "", "    ") // Indent with 4 spaces
+	if err != nil {
+		log.Fatalf("Error marshaling smart group data: %v", err)
+	}
+	fmt.Println("Created smart group:\n", string(response))
+}
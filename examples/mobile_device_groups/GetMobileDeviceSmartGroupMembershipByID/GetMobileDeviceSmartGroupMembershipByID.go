@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/jamfpro"
+)
+
+func main() {
+	// Define the path to the JSON configuration file
+	configFilePath := "/Users/dafyddwatkins/localtesting/jamfpro/clientconfig.json"
+
+	// Initialize the Jamf Pro client with the HTTP client configuration
+	client, err := jamfpro.BuildClientWithConfigFile(configFilePath)
+	if err != nil {
+		log.Fatalf("Failed to initialize Jamf Pro client: %v", err)
+	}
+
+	groupID := "12"
+
+	// Call GetMobileDeviceSmartGroupMembershipByID function
+	members, err := client.GetMobileDeviceSmartGroupMembershipByID(groupID, "")
+	if err != nil {
+		log.Fatalf("Error fetching smart group membership: %v", err)
+	}
+
+	fmt.Printf("Smart group %s has %d members\n", groupID, members.TotalCount)
+	for _, member := range members.Results {
+		fmt.Printf("%s\t%s\t%s\n", member.MobileDeviceID, member.SerialNumber, member.DisplayName)
+	}
+}
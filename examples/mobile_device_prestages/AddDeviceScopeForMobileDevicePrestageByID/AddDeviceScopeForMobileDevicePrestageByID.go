@@ -0,0 +1,44 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/jamfpro"
+)
+
+func main() {
+	// Define the path to the JSON configuration file
+	configFilePath := "/Users/dafyddwatkins/localtesting/jamfpro/clientconfig.json"
+
+	// Initialize the Jamf Pro client with the HTTP client configuration
+	client, err := jamfpro.BuildClientWithConfigFile(configFilePath)
+	if err != nil {
+		log.Fatalf("Failed to initialize Jamf Pro client: %v", err)
+	}
+
+	// The ID of the mobile device prestage and the serial numbers to add to its scope
+	prestageID := "1" // Replace with the actual ID
+	serialNumbers := []string{"DMPXXXXXXXX1", "DMPXXXXXXXX2"}
+
+	// Make sure the prestage is in sync with Apple Business Manager
+	syncState, err := client.GetLatestMobileDevicePrestageSyncStateByID(prestageID)
+	if err != nil {
+		log.Fatalf("Error fetching mobile device prestage sync state: %v", err)
+	}
+	fmt.Printf("Latest sync: %s at %s\n", syncState.SyncState, syncState.Timestamp)
+
+	// Add the serial numbers to the device scope of the mobile device prestage
+	deviceScope, err := client.AddDeviceScopeForMobileDevicePrestageByID(prestageID, serialNumbers)
+	if err != nil {
+		log.Fatalf("Error adding serial numbers to mobile device prestage scope: %v", err)
+	}
+
+	// Pretty print the device scope in JSON
+	response, err := json.MarshalIndent(deviceScope, "", "    ") // Indent with 4 spaces
+	if err != nil {
+		log.Fatalf("Error marshaling device scope data: %v", err)
+	}
+	fmt.Println("Updated mobile device prestage scope:\n", string(response))
+}
@@ -1,2 +1,54 @@
-// TODO
 package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/jamfpro"
+)
+
+func main() {
+	// Define the path to the JSON configuration file
+	configFilePath := "/Users/dafyddwatkins/localtesting/jamfpro/clientconfig.json"
+
+	// Initialize the Jamf Pro client with the HTTP client configuration
+	client, err := jamfpro.BuildClientWithConfigFile(configFilePath)
+	if err != nil {
+		log.Fatalf("Failed to initialize Jamf Pro client: %v", err)
+	}
+
+	// The ID of the mobile device prestage to update
+	prestageID := "1" // Replace with the actual ID
+
+	// Fetch the current prestage and change the fields to update. The update carries the versionLocks
+	// of the fetched prestage and fails with 409 Conflict if it was changed in the meantime.
+	prestage, err := client.GetMobileDevicePrestageByID(prestageID)
+	if err != nil {
+		log.Fatalf("Error fetching mobile device prestage: %v", err)
+	}
+	prestage.Department = "Education"
+	prestage.SupportPhoneNumber = "555-0100"
+
+	// Call UpdateMobileDevicePrestageByID function
+	updated, err := client.UpdateMobileDevicePrestageByID(prestageID, prestage)
+	if err != nil {
+		log.Fatalf("Error updating mobile device prestage: %v", err)
+	}
+
+	// Alternatively, ModifyMobileDevicePrestageByID re-reads the prestage and applies the change again on conflicts
+	updated, err = client.ModifyMobileDevicePrestageByID(prestageID, func(prestage *jamfpro.ResourceMobileDevicePrestage) error {
+		prestage.SupportEmailAddress = "help@example.com"
+		return nil
+	})
+	if err != nil {
+		log.Fatalf("Error modifying mobile device prestage: %v", err)
+	}
+
+	// Pretty print the updated prestage in JSON
+	response, err := json.MarshalIndent(updated, "", "    ") // Indent with 4 spaces
+	if err != nil {
+		log.Fatalf("Error marshaling mobile device prestage data: %v", err)
+	}
+	fmt.Println("Updated mobile device prestage:\n", string(response))
+}
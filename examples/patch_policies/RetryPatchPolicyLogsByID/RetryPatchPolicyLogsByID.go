@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/jamfpro"
+)
+
+func main() {
+	// Define the path to the JSON configuration file
+	configFilePath := "/Users/dafyddwatkins/localtesting/jamfpro/clientconfig.json"
+
+	// Initialize the Jamf Pro client with the HTTP client configuration
+	client, err := jamfpro.BuildClientWithConfigFile(configFilePath)
+	if err != nil {
+		log.Fatalf("Failed to initialize Jamf Pro client: %v", err)
+	}
+
+	// The ID of the patch policy to retry failed installations for
+	patchPolicyID := "1" // Replace with the actual ID
+
+	// Fetch the devices on which the patch policy failed
+	logs, err := client.GetPatchPolicyLogsByID(patchPolicyID, "&filter=statusEnum=="+jamfpro.PatchPolicyLogStatusFailed)
+	if err != nil {
+		log.Fatalf("Error fetching patch policy logs: %v", err)
+	}
+
+	var deviceIDs []string
+	for _, entry := range logs.Results {
+		fmt.Printf("%s (ID %s) failed attempt %d\n", entry.DeviceName, entry.DeviceId, entry.AttemptNumber)
+		deviceIDs = append(deviceIDs, entry.DeviceId)
+	}
+
+	if len(deviceIDs) == 0 {
+		fmt.Println("No failed installations to retry")
+		return
+	}
+
+	// Retry the installation on those devices
+	if err := client.RetryPatchPolicyLogsByID(patchPolicyID, deviceIDs); err != nil {
+		log.Fatalf("Error retrying patch policy: %v", err)
+	}
+	fmt.Printf("Retried patch policy on %d device(s)\n", len(deviceIDs))
+}
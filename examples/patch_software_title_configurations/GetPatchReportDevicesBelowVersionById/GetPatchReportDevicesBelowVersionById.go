@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/jamfpro"
+)
+
+func main() {
+	// Define the path to the JSON configuration file
+	configFilePath := "/Users/dafyddwatkins/localtesting/jamfpro/clientconfig.json"
+
+	// Initialize the Jamf Pro client with the HTTP client configuration
+	client, err := jamfpro.BuildClientWithConfigFile(configFilePath)
+	if err != nil {
+		log.Fatalf("Failed to initialize Jamf Pro client: %v", err)
+	}
+
+	// The software title configuration and the first version which fixes the vulnerability
+	configuration, err := client.GetPatchSoftwareTitleConfigurationByName("Google Chrome")
+	if err != nil {
+		log.Fatalf("Error fetching patch software title configuration: %v", err)
+	}
+	fixedVersion := "120.0.6099.129"
+
+	// Make sure Jamf Pro can report the installed versions
+	if err := client.AcceptPatchSoftwareTitleConfigurationExtensionAttributesById(configuration.ID); err != nil {
+		log.Fatalf("Error accepting extension attributes: %v", err)
+	}
+
+	// Fetch the devices running a vulnerable version
+	devices, err := client.GetPatchReportDevicesBelowVersionById(configuration.ID, fixedVersion)
+	if err != nil {
+		log.Fatalf("Error fetching patch report: %v", err)
+	}
+
+	fmt.Printf("%d device(s) run a version of %s older than %s:\n", len(devices.Below), configuration.DisplayName, fixedVersion)
+	for _, device := range devices.Below {
+		fmt.Printf("  %s (ID %s): %s\n", device.ComputerName, device.DeviceId, device.Version)
+	}
+
+	// Devices on a version without patch definition may be vulnerable too and need checking by hand
+	fmt.Printf("%d device(s) run a version without patch definition:\n", len(devices.Unclassified))
+	for _, device := range devices.Unclassified {
+		fmt.Printf("  %s (ID %s): %s\n", device.ComputerName, device.DeviceId, device.Version)
+	}
+
+	// Export the full patch report as CSV
+	if err := client.ExportPatchReportById(configuration.ID, "&sort=version:desc", os.Stdout); err != nil {
+		log.Fatalf("Error exporting patch report: %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/jamfpro"
+)
+
+func main() {
+	// Define the path to the JSON configuration file
+	configFilePath := "/Users/dafyddwatkins/localtesting/jamfpro/clientconfig.json"
+
+	// Initialize the Jamf Pro client with the HTTP client configuration
+	client, err := jamfpro.BuildClientWithConfigFile(configFilePath)
+	if err != nil {
+		log.Fatalf("Failed to initialize Jamf Pro client: %v", err)
+	}
+
+	// Promote the newest packaged version of the software title to the pilot patch policies first, and to the
+	// broad ones once 90% of the pilot devices have installed it. Set DryRun to false to apply the changes.
+	options := &jamfpro.PatchPromotionOptions{
+		SoftwareTitleConfigurationID: "1", // Replace with the actual ID
+		PackageNamePrefix:            "GoogleChrome-",
+		Rings: []jamfpro.PatchPromotionRing{
+			{Name: "pilot", PatchPolicyIDs: []int{1}, MinimumCompletion: 0.9},
+			{Name: "broad", PatchPolicyIDs: []int{2, 3}},
+		},
+		DryRun: true,
+	}
+
+	result, err := client.PromotePatchTitle(options)
+	if err != nil {
+		log.Fatalf("Error promoting patch title: %v", err)
+	}
+
+	// Pretty print the promotion result in JSON
+	resultJSON, err := json.MarshalIndent(result, "", "    ")
+	if err != nil {
+		log.Fatalf("Error marshaling promotion result data: %v", err)
+	}
+	fmt.Println("Patch Promotion Result:\n", string(resultJSON))
+}
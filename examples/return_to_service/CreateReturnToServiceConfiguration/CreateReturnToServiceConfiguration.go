@@ -0,0 +1,39 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/jamfpro"
+)
+
+func main() {
+	// Define the path to the JSON configuration file
+	configFilePath := "/Users/dafyddwatkins/localtesting/jamfpro/clientconfig.json"
+
+	// Initialize the Jamf Pro client with the HTTP client configuration
+	client, err := jamfpro.BuildClientWithConfigFile(configFilePath)
+	if err != nil {
+		log.Fatalf("Failed to initialize Jamf Pro client: %v", err)
+	}
+
+	// Define the configuration, referencing the mobile device configuration profile holding the Wi-Fi payload
+	configuration := &jamfpro.ResourceReturnToServiceConfiguration{
+		DisplayName:   "Device Refresh - Corporate Wi-Fi",
+		WifiProfileID: "12",
+	}
+
+	// Call CreateReturnToServiceConfiguration function
+	created, err := client.CreateReturnToServiceConfiguration(configuration)
+	if err != nil {
+		log.Fatalf("Error creating return to service configuration: %v", err)
+	}
+
+	// Pretty print the created configuration in JSON
+	response, err := json.MarshalIndent(created, "", "    ") // Indent with 4 spaces
+	if err != nil {
+		log.Fatalf("Error marshaling return to service data: %v", err)
+	}
+	fmt.Println("Created return to service configuration:\n", string(response))
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/jamfpro"
+)
+
+func main() {
+	// Define the path to the JSON configuration file
+	configFilePath := "/Users/dafyddwatkins/localtesting/jamfpro/clientconfig.json"
+
+	// Initialize the Jamf Pro client with the HTTP client configuration
+	client, err := jamfpro.BuildClientWithConfigFile(configFilePath)
+	if err != nil {
+		log.Fatalf("Failed to initialize Jamf Pro client: %v", err)
+	}
+
+	configurationID := "1"
+
+	// Call DeleteReturnToServiceConfigurationByID function
+	if err := client.DeleteReturnToServiceConfigurationByID(configurationID); err != nil {
+		log.Fatalf("Error deleting return to service configuration: %v", err)
+	}
+
+	fmt.Printf("Deleted return to service configuration %s\n", configurationID)
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/jamfpro"
+)
+
+func main() {
+	// Define the path to the JSON configuration file
+	configFilePath := "/Users/dafyddwatkins/localtesting/jamfpro/clientconfig.json"
+
+	// Initialize the Jamf Pro client with the HTTP client configuration
+	client, err := jamfpro.BuildClientWithConfigFile(configFilePath)
+	if err != nil {
+		log.Fatalf("Failed to initialize Jamf Pro client: %v", err)
+	}
+
+	// Point the configuration at a different Wi-Fi configuration profile
+	configurationID := "1"
+	configurationUpdate := &jamfpro.ResourceReturnToServiceConfiguration{
+		DisplayName:   "Device Refresh - Corporate Wi-Fi",
+		WifiProfileID: "14",
+	}
+
+	// Call UpdateReturnToServiceConfigurationByID function
+	updated, err := client.UpdateReturnToServiceConfigurationByID(configurationID, configurationUpdate)
+	if err != nil {
+		log.Fatalf("Error updating return to service configuration: %v", err)
+	}
+
+	// Pretty print the updated configuration in JSON
+	response, err := json.MarshalIndent(updated, "", "    ") // Indent with 4 spaces
+	if err != nil {
+		log.Fatalf("Error marshaling return to service data: %v", err)
+	}
+	fmt.Println("Updated return to service configuration:\n", string(response))
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/jamfpro"
+)
+
+func main() {
+	// Define the path to the JSON configuration file
+	configFilePath := "/Users/dafyddwatkins/localtesting/jamfpro/clientconfig.json"
+
+	// Initialize the Jamf Pro client with the HTTP client configuration
+	client, err := jamfpro.BuildClientWithConfigFile(configFilePath)
+	if err != nil {
+		log.Fatalf("Failed to initialize Jamf Pro client: %v", err)
+	}
+
+	// Call GetSSOCertificateInfo function
+	info, err := client.GetSSOCertificateInfo()
+	if err != nil {
+		log.Fatalf("Error fetching SSO certificate: %v", err)
+	}
+
+	fmt.Printf("Subject:       %s\n", info.Subject)
+	fmt.Printf("Issuer:        %s\n", info.Issuer)
+	fmt.Printf("Serial number: %s\n", info.SerialNumber)
+	fmt.Printf("Valid until:   %s\n", info.NotAfter)
+
+	// Warn when the certificate expires within 30 days
+	if days := int(info.ExpiresIn.Hours() / 24); days < 30 {
+		fmt.Printf("Warning: SSO certificate expires in %d day(s)\n", days)
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/jamfpro"
+)
+
+func main() {
+	// Define the path to the JSON configuration file
+	configFilePath := "/Users/dafyddwatkins/localtesting/jamfpro/clientconfig.json"
+
+	// Initialize the Jamf Pro client with the HTTP client configuration
+	client, err := jamfpro.BuildClientWithConfigFile(configFilePath)
+	if err != nil {
+		log.Fatalf("Failed to initialize Jamf Pro client: %v", err)
+	}
+
+	// Define the Venafi CA configuration
+	venafiCA := &jamfpro.ResourceVenafiCA{
+		Name:              "Venafi TPP",
+		ProxyAddress:      "pki-proxy.example.com:9443",
+		RevocationEnabled: true,
+		ClientID:          "jamf-pro",
+		RefreshToken:      "refresh-token",
+	}
+
+	// Call CreateVenafiCA function
+	created, err := client.CreateVenafiCA(venafiCA)
+	if err != nil {
+		log.Fatalf("Error creating Venafi CA configuration: %v", err)
+	}
+
+	// Check the connection to the PKI Proxy Server
+	status, err := client.GetVenafiCAConnectionStatusByID(created.ID)
+	if err != nil {
+		log.Fatalf("Error fetching Venafi CA connection status: %v", err)
+	}
+
+	// Pretty print the created configuration in JSON
+	response, err := json.MarshalIndent(created, "", "    ") // Indent with 4 spaces
+	if err != nil {
+		log.Fatalf("Error marshaling Venafi CA data: %v", err)
+	}
+	fmt.Println("Created Venafi CA configuration:\n", string(response))
+	fmt.Println("Connection status:", status.Status)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/jamfpro"
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/webhooks"
+)
+
+func main() {
+	// Define the path to the JSON configuration file
+	configFilePath := "/Users/dafyddwatkins/localtesting/jamfpro/clientconfig.json"
+
+	// Initialize the Jamf Pro client with the HTTP client configuration
+	client, err := jamfpro.BuildClientWithConfigFile(configFilePath)
+	if err != nil {
+		log.Fatalf("Failed to initialize Jamf Pro client: %v", err)
+	}
+
+	// Desired webhooks. Webhooks are matched by name.
+	specs := []jamfpro.WebhookSpec{
+		{
+			Name:     "pipeline - computer added",
+			Event:    webhooks.EventComputerAdded,
+			URL:      "https://hooks.example.com/jamf",
+			Username: "jamf",
+			Password: "change-me",
+		},
+		{
+			Name:         "pipeline - remediation group",
+			Event:        webhooks.EventSmartGroupComputerMembershipChange,
+			URL:          "https://hooks.example.com/jamf",
+			ContentType:  "xml",
+			Username:     "jamf",
+			Password:     "change-me",
+			SmartGroupID: 12,
+		},
+	}
+
+	// Preview the changes first, disabling "pipeline - " webhooks which are no longer wanted
+	options := &jamfpro.WebhookReconcileOptions{
+		DryRun:          true,
+		DisableUnlisted: true,
+		NamePrefix:      "pipeline - ",
+	}
+
+	results, err := client.ReconcileWebhooks(specs, options)
+	for _, result := range results {
+		fmt.Printf("%-10s %s (ID %d)\n", result.Action, result.Name, result.ID)
+		for _, change := range result.Changes {
+			fmt.Printf("           %s\n", change)
+		}
+		if result.Error != nil {
+			fmt.Printf("           error: %v\n", result.Error)
+		}
+	}
+	if err != nil {
+		log.Fatalf("Error reconciling webhooks: %v", err)
+	}
+}
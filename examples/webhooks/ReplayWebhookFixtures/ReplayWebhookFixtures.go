@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/webhooks"
+)
+
+// ReplayWebhookFixtures posts the sample deliveries used by the webhooks package tests to a webhook
+// receiver running on a local test server, so that handlers can be exercised without a Jamf Pro instance.
+func main() {
+	fixturesDir := "../../../sdk/webhooks/testdata"
+
+	receiver := webhooks.NewReceiver(webhooks.ReceiverOptions{
+		HeaderName:  "Authorization",
+		HeaderValue: "Bearer example-token",
+		ErrorLog:    func(err error) { log.Printf("receiver error: %v", err) },
+	})
+
+	receiver.Handle(webhooks.EventComputerPolicyFinished, func(ctx context.Context, event *webhooks.Event) error {
+		finished := event.Payload.(*webhooks.ComputerPolicyFinishedEvent)
+		fmt.Printf("  policy %d finished on %s, successful: %t\n", finished.PolicyID, finished.Computer.SerialNumber, finished.Successful)
+		return nil
+	})
+
+	receiver.Handle(webhooks.EventSmartGroupComputerMembershipChange, func(ctx context.Context, event *webhooks.Event) error {
+		change := event.Payload.(*webhooks.SmartGroupMembershipChangeEvent)
+		fmt.Printf("  %s: added %v, removed %v\n", change.Name, change.GroupAddedDevicesIDs, change.GroupRemovedDevicesIDs)
+		return nil
+	})
+
+	receiver.HandleAll(func(ctx context.Context, event *webhooks.Event) error {
+		fmt.Printf("  received %s (%s) at %s: %+v\n", event.Type(), event.ContentType, event.Time().UTC(), event.Payload)
+		return nil
+	})
+
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	fixtures, err := filepath.Glob(filepath.Join(fixturesDir, "*"))
+	if err != nil {
+		log.Fatalf("Failed to list fixtures: %v", err)
+	}
+
+	for _, fixture := range fixtures {
+		body, err := os.ReadFile(fixture)
+		if err != nil {
+			log.Fatalf("Failed to read fixture %s: %v", fixture, err)
+		}
+
+		contentType := webhooks.ContentTypeJSON
+		if strings.HasSuffix(fixture, ".xml") {
+			contentType = webhooks.ContentTypeXML
+		}
+
+		req, err := http.NewRequest(http.MethodPost, server.URL, bytes.NewReader(body))
+		if err != nil {
+			log.Fatalf("Failed to build request: %v", err)
+		}
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("Authorization", "Bearer example-token")
+
+		fmt.Printf("%s\n", filepath.Base(fixture))
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			log.Fatalf("Failed to post fixture %s: %v", fixture, err)
+		}
+		resp.Body.Close()
+		fmt.Printf("  response: %s\n", resp.Status)
+	}
+}
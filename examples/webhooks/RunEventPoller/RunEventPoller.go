@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/jamfpro"
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/webhooks"
+)
+
+func main() {
+	// Define the path to the JSON configuration file
+	configFilePath := "/Users/dafyddwatkins/localtesting/jamfpro/clientconfig.json"
+
+	// Initialize the Jamf Pro client with the HTTP client configuration
+	client, err := jamfpro.BuildClientWithConfigFile(configFilePath)
+	if err != nil {
+		log.Fatalf("Failed to initialize Jamf Pro client: %v", err)
+	}
+
+	// The same handlers can serve webhook deliveries and polled events
+	receiver := webhooks.NewReceiver(webhooks.ReceiverOptions{})
+	receiver.Handle(webhooks.EventComputerAdded, func(ctx context.Context, event *webhooks.Event) error {
+		computer := event.Payload.(*webhooks.Computer)
+		fmt.Printf("%s added: %s (%s)\n", event.Time().Format(time.RFC3339), computer.DeviceName, computer.SerialNumber)
+		return nil
+	})
+	receiver.Handle(webhooks.EventComputerPolicyFinished, func(ctx context.Context, event *webhooks.Event) error {
+		finished := event.Payload.(*webhooks.ComputerPolicyFinishedEvent)
+		fmt.Printf("%s policy %d finished on %s, successful: %t\n", event.Time().Format(time.RFC3339), finished.PolicyID, finished.Computer.DeviceName, finished.Successful)
+		return nil
+	})
+	receiver.HandleAll(func(ctx context.Context, event *webhooks.Event) error {
+		fmt.Printf("%s %s\n", event.Time().Format(time.RFC3339), event.Type())
+		return nil
+	})
+
+	poller, err := client.NewEventPoller(jamfpro.EventPollerOptions{
+		Handler:        receiver.Dispatch,
+		CheckpointFile: "event-poller-checkpoint.json",
+		Interval:       5 * time.Minute,
+	})
+	if err != nil {
+		log.Fatalf("Failed to create event poller: %v", err)
+	}
+
+	// Poll until interrupted
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := poller.Run(ctx); err != nil && err != context.Canceled {
+		log.Fatalf("Event poller stopped: %v", err)
+	}
+}
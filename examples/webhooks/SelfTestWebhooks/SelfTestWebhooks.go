@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/jamfpro"
+)
+
+func main() {
+	// Define the path to the JSON configuration file
+	configFilePath := "/Users/dafyddwatkins/localtesting/jamfpro/clientconfig.json"
+
+	// Initialize the Jamf Pro client with the HTTP client configuration
+	client, err := jamfpro.BuildClientWithConfigFile(configFilePath)
+	if err != nil {
+		log.Fatalf("Failed to initialize Jamf Pro client: %v", err)
+	}
+
+	// The receiver listens locally; Jamf Pro must be able to reach it at PublicURL,
+	// e.g. through a reverse proxy or tunnel.
+	options := jamfpro.WebhookSelfTestOptions{
+		ListenAddr: ":8080",
+		PublicURL:  "https://hooks.example.com/jamf",
+		Timeout:    90 * time.Second,
+	}
+
+	result, err := client.SelfTestWebhooks(context.Background(), options)
+	if err != nil {
+		log.Fatalf("Webhook self test failed: %v", err)
+	}
+
+	fmt.Printf("Webhook %d delivered a %s event in %s (%s)\n",
+		result.WebhookID, result.Event.Type(), result.RoundTrip.Round(time.Millisecond), result.Event.ContentType)
+}
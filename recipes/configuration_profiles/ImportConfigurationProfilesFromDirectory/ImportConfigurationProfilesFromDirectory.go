@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/jamfpro"
+)
+
+func main() {
+	// Define the path to the JSON configuration file
+	configFilePath := "/Users/dafyddwatkins/localtesting/jamfpro/clientconfig.json"
+
+	// Initialize the Jamf Pro client with the HTTP client configuration
+	client, err := jamfpro.BuildClientWithConfigFile(configFilePath)
+	if err != nil {
+		log.Fatalf("Failed to initialize Jamf Pro client: %v", err)
+	}
+
+	// Directory previously written by ExportConfigurationProfiles, containing macos/ and mobile/ sub directories.
+	// Profiles are matched by name or payload identifier and only changed profiles are updated.
+	importDir := "/Users/dafyddwatkins/localtesting/jamfpro"
+	results, err := client.ImportConfigurationProfiles(importDir)
+	for _, result := range results {
+		if result.Error != nil {
+			log.Printf("Failed to import %s profile %q: %v", result.Kind, result.Name, result.Error)
+			continue
+		}
+		fmt.Printf("%s profile %q (ID %d): %s\n", result.Kind, result.Name, result.ID, result.Action)
+	}
+	if err != nil {
+		log.Fatalf("Import completed with errors: %v", err)
+	}
+
+	fmt.Println("Import completed!")
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"crypto/x509"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/jamfpro"
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/mobileconfig"
+)
+
+// The Classic API does not accept CMS signed payloads, so a signed profile cannot be handed to
+// CreateMacOSConfigurationProfile as it is. The supported flow is:
+//
+//  1. sign the profile with mobileconfig.Signer (or receive it signed from the security team),
+//  2. verify the signature against the trusted roots with mobileconfig.Verify,
+//  3. upload the verified, unsigned content, and keep the signed file as the approved original.
+func main() {
+	// Define the path to the JSON configuration file
+	configFilePath := "/Users/dafyddwatkins/localtesting/jamfpro/clientconfig.json"
+
+	// Initialize the Jamf Pro client with the HTTP client configuration
+	client, err := jamfpro.BuildClientWithConfigFile(configFilePath)
+	if err != nil {
+		log.Fatalf("Failed to initialize Jamf Pro client: %v", err)
+	}
+
+	// Sign the profile. Skip this step when the profile was signed elsewhere and read the signed file instead.
+	signer, err := mobileconfig.NewSignerFromFiles("/Users/dafyddwatkins/localtesting/signing/cert.pem", "/Users/dafyddwatkins/localtesting/signing/key.pem")
+	if err != nil {
+		log.Fatalf("Failed to load signer: %v", err)
+	}
+
+	profileToSign, err := mobileconfig.ParseFile("/Users/dafyddwatkins/GitHub/deploymenttheory/go-api-sdk-jamfpro/examples/support_files/accessibility-chara-nosub-test.mobileconfig")
+	if err != nil {
+		log.Fatalf("Failed to parse profile: %v", err)
+	}
+
+	signed, err := profileToSign.Sign(signer)
+	if err != nil {
+		log.Fatalf("Failed to sign profile: %v", err)
+	}
+
+	signedPath := "/Users/dafyddwatkins/localtesting/signing/accessibility-signed.mobileconfig"
+	if err := os.WriteFile(signedPath, signed, 0644); err != nil {
+		log.Fatalf("Failed to write signed profile: %v", err)
+	}
+
+	// Verify the signed profile against the trusted roots
+	rootPEM, err := os.ReadFile("/Users/dafyddwatkins/localtesting/signing/root.pem")
+	if err != nil {
+		log.Fatalf("Failed to read root certificate: %v", err)
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(rootPEM) {
+		log.Fatalf("No certificate found in root certificate file")
+	}
+
+	signedData, err := os.ReadFile(signedPath)
+	if err != nil {
+		log.Fatalf("Failed to read signed profile: %v", err)
+	}
+
+	content, signerDetails, err := mobileconfig.Verify(signedData, roots)
+	if err != nil {
+		log.Fatalf("Refusing to upload profile: %v", err)
+	}
+	fmt.Printf("Profile signed by %s (SHA-256 %s)\n", signerDetails.Subject, signerDetails.SHA256Fingerprint)
+
+	// Upload the verified content
+	profile := &jamfpro.ResourceMacOSConfigurationProfile{
+		General: jamfpro.MacOSConfigurationProfileSubsetGeneral{
+			Name:               "accessibility-signed-test",
+			Description:        fmt.Sprintf("Verified signature of %s", signerDetails.Subject),
+			Site:               jamfpro.SharedResourceSite{ID: -1, Name: "None"},
+			Category:           jamfpro.SharedResourceCategory{ID: -1, Name: "No category assigned"},
+			DistributionMethod: "Install Automatically",
+			Level:              "computer",
+			RedeployOnUpdate:   "Newly Assigned",
+			Payloads:           string(content),
+		},
+	}
+
+	created, err := client.CreateMacOSConfigurationProfile(profile)
+	if err != nil {
+		log.Fatalf("Error creating macOS Configuration Profile: %v", err)
+	}
+
+	fmt.Printf("Successfully created macOS Configuration Profile with ID: %d\n", created.ID)
+}
@@ -1,12 +1,10 @@
 package main
 
 import (
-	"encoding/xml"
+	"crypto/x509"
 	"fmt"
 	"log"
 	"os"
-	"path/filepath"
-	"strings"
 
 	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/jamfpro"
 )
@@ -21,70 +19,31 @@ func main() {
 		log.Fatalf("Failed to initialize Jamf Pro client: %v", err)
 	}
 
-	// Fetch the list of mobile device configuration profiles
-	profilesList, err := client.GetMobileDeviceConfigurationProfiles()
+	// Load the root certificates signed profiles must chain to
+	rootPEM, err := os.ReadFile("/Users/dafyddwatkins/localtesting/signing/root.pem")
 	if err != nil {
-		log.Fatalf("Failed to fetch Mobile Device Configuration Profiles: %v", err)
+		log.Fatalf("Failed to read root certificate: %v", err)
 	}
-
-	fmt.Println("Found the following Mobile Device Configuration Profiles:")
-	for _, profile := range profilesList.ConfigurationProfiles {
-		fmt.Printf("ID: %d, Name: %s\n", profile.ID, profile.Name)
-	}
-	fmt.Println("These profiles will be exported.")
-
-	exportDir := "/Users/dafyddwatkins/localtesting/jamfpro"
-	if err := os.MkdirAll(exportDir, 0750); err != nil {
-		log.Fatalf("Failed to create export directory: %v", err)
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(rootPEM) {
+		log.Fatalf("No certificate found in root certificate file")
 	}
 
-	for _, profile := range profilesList.ConfigurationProfiles {
-		respProfile, err := client.GetMobileDeviceConfigurationProfileByID(profile.ID)
-		if err != nil {
-			log.Printf("Failed to fetch profile with ID %d: %v", profile.ID, err)
-			continue
-		}
-
-		xmlData, err := xml.MarshalIndent(respProfile, "", "  ")
-		if err != nil {
-			log.Printf("Failed to convert profile with ID %d to XML: %v", profile.ID, err)
-			continue
-		}
-
-		payloadsContent := extractPayloads(string(xmlData))
-		if payloadsContent == "" {
-			log.Printf("No <payloads> content found for profile ID %d", profile.ID)
-			continue
-		}
-
-		filename := filepath.Join(exportDir, profile.Name+".mobileconfig")
-		file, err := os.Create(filename)
-		if err != nil {
-			log.Printf("Failed to create file for profile with ID %d: %v", profile.ID, err)
+	// Export each profile as a .mobileconfig file with a .json metadata sidecar.
+	// Signed profiles are verified against the roots and exported unsigned, the signer is recorded in the sidecar.
+	// Leave TrustRoots nil to strip signatures without verifying them.
+	exportDir := "/Users/dafyddwatkins/localtesting/jamfpro/mobile"
+	results, err := client.ExportMobileDeviceConfigurationProfiles(exportDir, &jamfpro.ConfigurationProfileExportOptions{TrustRoots: roots})
+	for _, result := range results {
+		if result.Error != nil {
+			log.Printf("Failed to export profile with ID %d: %v", result.ID, result.Error)
 			continue
 		}
-		defer file.Close()
-
-		if _, err := file.WriteString(payloadsContent); err != nil {
-			log.Printf("Failed to write to file for profile with ID %d: %v", profile.ID, err)
-			continue
-		}
-
-		fmt.Printf("Exported profile with ID %d to %s\n", profile.ID, filename)
+		fmt.Printf("Exported profile with ID %d to %s\n", result.ID, result.Path)
 	}
-
-	fmt.Println("Export completed!")
-}
-
-func extractPayloads(xmlData string) string {
-	startTag := "<payloads>"
-	endTag := "</payloads>"
-	startIndex := strings.Index(xmlData, startTag)
-	endIndex := strings.Index(xmlData, endTag)
-
-	if startIndex == -1 || endIndex == -1 {
-		return ""
+	if err != nil {
+		log.Fatalf("Failed to export Mobile Device Configuration Profiles: %v", err)
 	}
 
-	return xmlData[startIndex+len(startTag) : endIndex]
+	fmt.Println("Export completed!")
 }
@@ -0,0 +1,73 @@
+package jamfpro
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/deploymenttheory/go-api-http-client/httpclient"
+)
+
+// newTestClient returns a client whose requests are served by mux. The HTTP client always builds
+// https://<instance>.jamfcloud.com URLs, so connections are redirected to a local TLS server through
+// http.DefaultTransport, which is restored when the test ends. Tests using it must not run in parallel.
+func newTestClient(t *testing.T, mux *http.ServeMux) *Client {
+	t.Helper()
+
+	mux.HandleFunc("/api/v1/auth/token", func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, http.StatusOK, map[string]string{
+			"token":   "test-token",
+			"expires": time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+		})
+	})
+
+	server := httptest.NewTLSServer(mux)
+	t.Cleanup(server.Close)
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defaultTransport := http.DefaultTransport
+	transport := defaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	transport.DialContext = func(ctx context.Context, network, _ string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, network, serverURL.Host)
+	}
+	http.DefaultTransport = transport
+	t.Cleanup(func() { http.DefaultTransport = defaultTransport })
+
+	client, err := BuildClient(httpclient.ClientConfig{
+		Auth:        httpclient.AuthConfig{Username: "test", Password: "test-password"},
+		Environment: httpclient.EnvironmentConfig{APIType: "jamfpro", InstanceName: "test"},
+		ClientOptions: httpclient.ClientOptions{
+			Logging:     httpclient.LoggingConfig{LogLevel: "LogLevelFatal", LogOutputFormat: "console"},
+			Concurrency: httpclient.ConcurrencyConfig{MaxConcurrentRequests: 1},
+			Timeout: httpclient.TimeoutConfig{
+				CustomTimeout:            10 * time.Second,
+				TokenRefreshBufferPeriod: time.Minute,
+				TotalRetryDuration:       5 * time.Second,
+			},
+			Retry: httpclient.RetryConfig{MaxRetryAttempts: 1},
+		},
+	})
+	if err != nil {
+		t.Fatalf("BuildClient: %v", err)
+	}
+
+	return client
+}
+
+// writeTestJSON writes v as a JSON response with the given status code.
+func writeTestJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
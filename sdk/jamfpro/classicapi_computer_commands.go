@@ -0,0 +1,391 @@
+// classicapi_computer_commands.go
+// Jamf Pro Classic Api - Computer Commands
+// api reference: https://developer.jamf.com/jamf-pro/reference/computercommands
+// Classic API requires the structs to support an XML data structure.
+
+package jamfpro
+
+import (
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const uriComputerCommands = "/JSSResource/computercommands"
+
+// Computer MDM commands supported by the Classic API
+const (
+	ComputerCommandBlankPush                = "BlankPush"
+	ComputerCommandDeleteUser               = "DeleteUser"
+	ComputerCommandDeviceLock               = "DeviceLock"
+	ComputerCommandDisableRemoteDesktop     = "DisableRemoteDesktop"
+	ComputerCommandEnableRemoteDesktop      = "EnableRemoteDesktop"
+	ComputerCommandEraseDevice              = "EraseDevice"
+	ComputerCommandScheduleOSUpdate         = "ScheduleOSUpdate"
+	ComputerCommandSettingsDisableBluetooth = "SettingsDisableBluetooth"
+	ComputerCommandSettingsEnableBluetooth  = "SettingsEnableBluetooth"
+	ComputerCommandUnlockUserAccount        = "UnlockUserAccount"
+	ComputerCommandUnmanageDevice           = "UnmanageDevice"
+)
+
+// ScheduleOSUpdate actions
+const (
+	ComputerCommandOSUpdateActionDownloadOnly       = 1
+	ComputerCommandOSUpdateActionDownloadAndInstall = 2
+)
+
+// Computer command statuses
+const (
+	ComputerCommandStatusPending   = "Pending"
+	ComputerCommandStatusCompleted = "Completed"
+	ComputerCommandStatusFailed    = "Failed"
+)
+
+// computerCommandPasscodePattern matches the six digit passcode required by DeviceLock and EraseDevice.
+var computerCommandPasscodePattern = regexp.MustCompile(`^[0-9]{6}$`)
+
+// List
+
+// ResponseComputerCommandsList represents the response for a list of computer commands.
+type ResponseComputerCommandsList struct {
+	Size             int                        `xml:"size"`
+	ComputerCommands []ComputerCommandsListItem `xml:"computer_command"`
+}
+
+// ComputerCommandsListItem represents a single computer command in the list.
+type ComputerCommandsListItem struct {
+	ID      int    `xml:"id"`
+	Name    string `xml:"name"`
+	Command string `xml:"command,omitempty"`
+	UUID    string `xml:"uuid,omitempty"`
+	Status  string `xml:"status,omitempty"`
+}
+
+// Resource
+
+// ResourceComputerCommand represents a computer command, both when issuing it and when reading it back.
+type ResourceComputerCommand struct {
+	XMLName   xml.Name                        `xml:"computer_command"`
+	General   ComputerCommandSubsetGeneral    `xml:"general"`
+	Computers []ComputerCommandSubsetComputer `xml:"computers>computer,omitempty"`
+}
+
+// Subsets
+
+// ComputerCommandSubsetGeneral holds the command name and its parameters.
+type ComputerCommandSubsetGeneral struct {
+	Command     string `xml:"command"`
+	UUID        string `xml:"uuid,omitempty"`
+	Passcode    string `xml:"passcode,omitempty"`
+	LockMessage string `xml:"lock_message,omitempty"`
+	UserName    string `xml:"user_name,omitempty"`
+	DateSent    string `xml:"date_sent,omitempty"`
+}
+
+// ComputerCommandSubsetComputer identifies a target computer and, when read back, its command status.
+type ComputerCommandSubsetComputer struct {
+	ID           int    `xml:"id"`
+	Name         string `xml:"name,omitempty"`
+	UDID         string `xml:"udid,omitempty"`
+	SerialNumber string `xml:"serial_number,omitempty"`
+	Status       string `xml:"status,omitempty"`
+}
+
+// Response
+
+// ResponseComputerCommandCreate represents the response to issuing a computer command. Jamf Pro
+// returns one entry per target computer.
+type ResponseComputerCommandCreate struct {
+	XMLName  xml.Name                       `xml:"computer_command"`
+	Commands []ComputerCommandSubsetCreated `xml:"command"`
+}
+
+// ComputerCommandSubsetCreated holds the command UUID issued to a single computer.
+type ComputerCommandSubsetCreated struct {
+	Name        string `xml:"name"`
+	CommandUUID string `xml:"command_uuid"`
+	ComputerID  int    `xml:"computer_id"`
+}
+
+// ComputerCommandTargets selects the computers a command is sent to. Names and serial numbers are
+// resolved to computer IDs before the command is issued.
+type ComputerCommandTargets struct {
+	IDs           []int
+	Names         []string
+	SerialNumbers []string
+}
+
+// UUIDs returns the command UUIDs issued by Jamf Pro, in response order.
+func (r *ResponseComputerCommandCreate) UUIDs() []string {
+	uuids := make([]string, 0, len(r.Commands))
+	for _, command := range r.Commands {
+		if command.CommandUUID != "" {
+			uuids = append(uuids, command.CommandUUID)
+		}
+	}
+	return uuids
+}
+
+// Builders
+
+// NewComputerCommand returns a command without parameters, e.g. ComputerCommandBlankPush or
+// ComputerCommandUnmanageDevice.
+func NewComputerCommand(command string) *ResourceComputerCommand {
+	return &ResourceComputerCommand{General: ComputerCommandSubsetGeneral{Command: command}}
+}
+
+// NewComputerDeviceLockCommand returns a DeviceLock command. passcode must be six digits; message is
+// shown on the lock screen and may be empty.
+func NewComputerDeviceLockCommand(passcode, message string) *ResourceComputerCommand {
+	command := NewComputerCommand(ComputerCommandDeviceLock)
+	command.General.Passcode = passcode
+	command.General.LockMessage = message
+	return command
+}
+
+// NewComputerEraseDeviceCommand returns an EraseDevice command. passcode must be six digits.
+func NewComputerEraseDeviceCommand(passcode string) *ResourceComputerCommand {
+	command := NewComputerCommand(ComputerCommandEraseDevice)
+	command.General.Passcode = passcode
+	return command
+}
+
+// NewComputerDeleteUserCommand returns a DeleteUser command for the given local user account.
+func NewComputerDeleteUserCommand(userName string) *ResourceComputerCommand {
+	command := NewComputerCommand(ComputerCommandDeleteUser)
+	command.General.UserName = userName
+	return command
+}
+
+// NewComputerUnlockUserAccountCommand returns an UnlockUserAccount command for the given local user account.
+func NewComputerUnlockUserAccountCommand(userName string) *ResourceComputerCommand {
+	command := NewComputerCommand(ComputerCommandUnlockUserAccount)
+	command.General.UserName = userName
+	return command
+}
+
+// Validate checks that the command is supported and that its required parameters are set.
+// ScheduleOSUpdate is sent with SendComputerScheduleOSUpdateCommand instead.
+func (r *ResourceComputerCommand) Validate() error {
+	general := r.General
+
+	switch general.Command {
+	case ComputerCommandDeviceLock, ComputerCommandEraseDevice:
+		if !computerCommandPasscodePattern.MatchString(general.Passcode) {
+			return fmt.Errorf("%s requires a six digit passcode", general.Command)
+		}
+	case ComputerCommandDeleteUser, ComputerCommandUnlockUserAccount:
+		if general.UserName == "" {
+			return fmt.Errorf("%s requires a user name", general.Command)
+		}
+	case ComputerCommandBlankPush, ComputerCommandDisableRemoteDesktop, ComputerCommandEnableRemoteDesktop,
+		ComputerCommandSettingsDisableBluetooth, ComputerCommandSettingsEnableBluetooth, ComputerCommandUnmanageDevice:
+	case ComputerCommandScheduleOSUpdate:
+		return fmt.Errorf("%s must be sent with SendComputerScheduleOSUpdateCommand", general.Command)
+	case "":
+		return fmt.Errorf("computer command name is required")
+	default:
+		return fmt.Errorf("unsupported computer command: %s", general.Command)
+	}
+
+	if general.LockMessage != "" && general.Command != ComputerCommandDeviceLock {
+		return fmt.Errorf("lock message is only supported by %s", ComputerCommandDeviceLock)
+	}
+
+	return nil
+}
+
+// CRUD
+
+// GetComputerCommands retrieves all computer commands.
+func (c *Client) GetComputerCommands() (*ResponseComputerCommandsList, error) {
+	endpoint := uriComputerCommands
+
+	var commandsList ResponseComputerCommandsList
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &commandsList)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGet, "computer commands", err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &commandsList, nil
+}
+
+// GetComputerCommandsByName retrieves all computer commands with the given command name, e.g. DeviceLock.
+func (c *Client) GetComputerCommandsByName(name string) (*ResponseComputerCommandsList, error) {
+	endpoint := fmt.Sprintf("%s/name/%s", uriComputerCommands, name)
+
+	var commandsList ResponseComputerCommandsList
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &commandsList)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByName, "computer commands", name, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &commandsList, nil
+}
+
+// GetComputerCommandsByStatus retrieves all computer commands and returns those with the given status,
+// e.g. ComputerCommandStatusPending. The comparison is case insensitive.
+func (c *Client) GetComputerCommandsByStatus(status string) (*ResponseComputerCommandsList, error) {
+	commandsList, err := c.GetComputerCommands()
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByString, "computer commands", "status", status, err)
+	}
+
+	var filtered ResponseComputerCommandsList
+	for _, command := range commandsList.ComputerCommands {
+		if strings.EqualFold(command.Status, status) {
+			filtered.ComputerCommands = append(filtered.ComputerCommands, command)
+		}
+	}
+	filtered.Size = len(filtered.ComputerCommands)
+
+	return &filtered, nil
+}
+
+// GetComputerCommandByUUID retrieves a computer command by its UUID.
+func (c *Client) GetComputerCommandByUUID(uuid string) (*ResourceComputerCommand, error) {
+	endpoint := fmt.Sprintf("%s/uuid/%s", uriComputerCommands, uuid)
+
+	var command ResourceComputerCommand
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &command)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByString, "computer command", "uuid", uuid, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &command, nil
+}
+
+// GetComputerCommandStatusByUUID retrieves the per computer status of a command by its UUID.
+func (c *Client) GetComputerCommandStatusByUUID(uuid string) (*ResourceComputerCommand, error) {
+	endpoint := fmt.Sprintf("%s/status/%s", uriComputerCommands, uuid)
+
+	var command ResourceComputerCommand
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &command)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByString, "computer command status", "uuid", uuid, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &command, nil
+}
+
+// SendComputerCommand validates command and issues it to the target computers. The returned response
+// holds one command UUID per computer.
+func (c *Client) SendComputerCommand(command *ResourceComputerCommand, targets ComputerCommandTargets) (*ResponseComputerCommandCreate, error) {
+	if err := command.Validate(); err != nil {
+		return nil, fmt.Errorf(errMsgFailedCreate, "computer command", err)
+	}
+
+	computerIDs, err := c.resolveComputerCommandTargets(targets)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedCreate, "computer command", err)
+	}
+
+	prepared := *command
+	prepared.Computers = make([]ComputerCommandSubsetComputer, 0, len(computerIDs))
+	for _, id := range computerIDs {
+		prepared.Computers = append(prepared.Computers, ComputerCommandSubsetComputer{ID: id})
+	}
+
+	endpoint := fmt.Sprintf("%s/command/%s", uriComputerCommands, command.General.Command)
+
+	var response ResponseComputerCommandCreate
+	resp, err := c.HTTP.DoRequest("POST", endpoint, &prepared, &response)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedCreate, "computer command", err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &response, nil
+}
+
+// SendComputerScheduleOSUpdateCommand issues a ScheduleOSUpdate command to the target computers.
+// action is ComputerCommandOSUpdateActionDownloadOnly or ComputerCommandOSUpdateActionDownloadAndInstall.
+func (c *Client) SendComputerScheduleOSUpdateCommand(action int, targets ComputerCommandTargets) (*ResponseComputerCommandCreate, error) {
+	if action != ComputerCommandOSUpdateActionDownloadOnly && action != ComputerCommandOSUpdateActionDownloadAndInstall {
+		return nil, fmt.Errorf(errMsgFailedCreate, "computer command", fmt.Errorf("unsupported %s action: %d", ComputerCommandScheduleOSUpdate, action))
+	}
+
+	computerIDs, err := c.resolveComputerCommandTargets(targets)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedCreate, "computer command", err)
+	}
+
+	ids := make([]string, 0, len(computerIDs))
+	for _, id := range computerIDs {
+		ids = append(ids, strconv.Itoa(id))
+	}
+
+	endpoint := fmt.Sprintf("%s/command/%s/action/%d/id/%s", uriComputerCommands, ComputerCommandScheduleOSUpdate, action, strings.Join(ids, ","))
+
+	var response ResponseComputerCommandCreate
+	resp, err := c.HTTP.DoRequest("POST", endpoint, nil, &response)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedCreate, "computer command", err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &response, nil
+}
+
+// resolveComputerCommandTargets returns the de-duplicated computer IDs selected by targets.
+func (c *Client) resolveComputerCommandTargets(targets ComputerCommandTargets) ([]int, error) {
+	seen := map[int]bool{}
+	var ids []int
+
+	addID := func(id int) {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	for _, id := range targets.IDs {
+		addID(id)
+	}
+
+	for _, name := range targets.Names {
+		computer, err := c.GetComputerByName(name)
+		if err != nil {
+			return nil, err
+		}
+		addID(computer.General.ID)
+	}
+
+	for _, serial := range targets.SerialNumbers {
+		computer, err := c.GetComputerBySerialNumber(serial)
+		if err != nil {
+			return nil, err
+		}
+		addID(computer.General.ID)
+	}
+
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("no target computers specified")
+	}
+
+	return ids, nil
+}
@@ -14,7 +14,7 @@ type ResourceComputerHistory struct {
 	General           ComputerHistorySubsetGeneralInfo     `json:"general" xml:"general"`
 	ComputerUsageLogs []ComputerHistorySubsetUsageLog      `json:"computer_usage_logs,omitempty" xml:"computer_usage_logs,omitempty"`
 	Audits            []ComputerHistorySubsetAudit         `json:"audits,omitempty" xml:"audits,omitempty"`
-	PolicyLogs        []ComputerHistorySubsetPolicyLog     `json:"policy_logs,omitempty" xml:"policy_logs,omitempty"`
+	PolicyLogs        []ComputerHistorySubsetPolicyDetails `json:"policy_logs,omitempty" xml:"policy_logs>policy_log,omitempty"`
 	CasperRemoteLogs  []ComputerHistorySubsetCasperRemote  `json:"casper_remote_logs,omitempty" xml:"casper_remote_logs,omitempty"`
 	ScreenSharingLogs []ComputerHistorySubsetScreenSharing `json:"screen_sharing_logs,omitempty" xml:"screen_sharing_logs,omitempty"`
 	CasperImagingLogs []ComputerHistorySubsetCasperImaging `json:"casper_imaging_logs,omitempty" xml:"casper_imaging_logs,omitempty"`
@@ -42,10 +42,11 @@ type ComputerHistorySubsetAudit struct {
 	Audit ComputerHistorySubsetEventDetails `json:"audit,omitempty" xml:"audit,omitempty"`
 }
 
-// ComputerHistorySubsetPolicyLog stores logs related to policies.
-type ComputerHistorySubsetPolicyLog struct {
-	PolicyLog ComputerHistorySubsetPolicyDetails `json:"policy_log,omitempty" xml:"policy_log,omitempty"`
-}
+// ComputerHistorySubsetPolicyLog is the element of ResourceComputerHistory.PolicyLogs.
+//
+// Deprecated: PolicyLogs now decodes each policy_log element directly into ComputerHistorySubsetPolicyDetails,
+// which the old wrapper struct never populated. Use ComputerHistorySubsetPolicyDetails.
+type ComputerHistorySubsetPolicyLog = ComputerHistorySubsetPolicyDetails
 
 // ComputerHistorySubsetCasperRemote stores logs for Casper remote actions.
 type ComputerHistorySubsetCasperRemote struct {
@@ -64,9 +65,9 @@ type ComputerHistorySubsetCasperImaging struct {
 
 // ComputerHistorySubsetCommands groups completed, pending, and failed commands.
 type ComputerHistorySubsetCommands struct {
-	Completed []ComputerHistorySubsetCommand `json:"completed,omitempty" xml:"completed,omitempty"`
-	Pending   []ComputerHistorySubsetCommand `json:"pending,omitempty" xml:"pending,omitempty"`
-	Failed    []ComputerHistorySubsetCommand `json:"failed,omitempty" xml:"failed,omitempty"`
+	Completed []ComputerHistorySubsetCommand `json:"completed,omitempty" xml:"completed>command,omitempty"`
+	Pending   []ComputerHistorySubsetCommand `json:"pending,omitempty" xml:"pending>command,omitempty"`
+	Failed    []ComputerHistorySubsetCommand `json:"failed,omitempty" xml:"failed>command,omitempty"`
 }
 
 // ComputerHistorySubsetLocation stores location data related to a user.
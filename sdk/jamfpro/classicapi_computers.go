@@ -436,6 +436,23 @@ func (c *Client) GetComputerByName(name string) (*ResponseComputer, error) {
 	return &computer, nil
 }
 
+// GetComputerBySerialNumber retrieves the computer by its serial number
+func (c *Client) GetComputerBySerialNumber(serial string) (*ResponseComputer, error) {
+	endpoint := fmt.Sprintf("%s/serialnumber/%s", uriComputers, serial)
+
+	var computer ResponseComputer
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &computer)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByString, "computer", "serial number", serial, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &computer, nil
+}
+
 // CreateComputer creates a new computer.
 func (c *Client) CreateComputer(computer ResponseComputer) (*ResponseComputer, error) {
 	endpoint := uriComputers
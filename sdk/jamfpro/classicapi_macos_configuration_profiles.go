@@ -16,6 +16,8 @@ package jamfpro
 import (
 	"encoding/xml"
 	"fmt"
+
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/mobileconfig"
 )
 
 const uriMacOSConfigurationProfiles = "/JSSResource/osxconfigurationprofiles"
@@ -62,6 +64,10 @@ type MacOSConfigurationProfileSubsetGeneral struct {
 	UUID               string                 `xml:"uuid,omitempty"`
 	RedeployOnUpdate   string                 `xml:"redeploy_on_update,omitempty"`
 	Payloads           string                 `xml:"payloads,omitempty"`
+
+	// Profile optionally supplies the payloads as a typed configuration profile. When set it is
+	// marshaled into Payloads on create and update.
+	Profile *mobileconfig.Profile `xml:"-"`
 }
 
 // MacOSConfigurationProfileSubsetScope represents the scope subset of a macOS configuration profile.
@@ -225,14 +231,24 @@ func (c *Client) GetMacOSConfigurationProfileByNameByID(name string) (*ResourceM
 // CreateMacOSConfigurationProfile creates a new macOS Configuration Profile on the Jamf Pro server and returns the profile with its ID updated.
 // It sends a POST request to the Jamf Pro server with the profile details and expects a response with the ID of the newly created profile.
 // CreateMacOSConfigurationProfile creates a new macOS Configuration Profile on the Jamf Pro server and returns the ID of the newly created profile.
+// CMS signed payloads are refused, as the Classic API only accepts the unsigned profile: verify a signed profile with
+// mobileconfig.Verify and send the returned content, as shown in recipes/macos_configuration_profiles/CreateMacOSConfigurationProfileFromSignedProfile.
 func (c *Client) CreateMacOSConfigurationProfile(profile *ResourceMacOSConfigurationProfile) (*ResponseMacOSConfigurationProfileCreationUpdate, error) {
 	endpoint := fmt.Sprintf("%s/id/0", uriMacOSConfigurationProfiles)
 
+	payloads, err := buildConfigurationProfilePayloads(profile.General.Profile, profile.General.Payloads)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedCreate, "macOS configuration profile", err)
+	}
+
+	prepared := *profile
+	prepared.General.Payloads = payloads
+
 	requestBody := struct {
 		XMLName xml.Name `xml:"os_x_configuration_profile"`
 		*ResourceMacOSConfigurationProfile
 	}{
-		ResourceMacOSConfigurationProfile: profile,
+		ResourceMacOSConfigurationProfile: &prepared,
 	}
 
 	var response ResponseMacOSConfigurationProfileCreationUpdate
@@ -250,15 +266,23 @@ func (c *Client) CreateMacOSConfigurationProfile(profile *ResourceMacOSConfigura
 }
 
 // UpdateMacOSConfigurationProfileByID updates an existing macOS Configuration Profile by its ID on the Jamf Pro server
-// and returns the ID of the updated profile.
+// and returns the ID of the updated profile. CMS signed payloads are refused, see CreateMacOSConfigurationProfile.
 func (c *Client) UpdateMacOSConfigurationProfileByID(id int, profile *ResourceMacOSConfigurationProfile) (int, error) {
 	endpoint := fmt.Sprintf("%s/id/%d", uriMacOSConfigurationProfiles, id)
 
+	payloads, err := buildConfigurationProfilePayloads(profile.General.Profile, profile.General.Payloads)
+	if err != nil {
+		return 0, fmt.Errorf(errMsgFailedUpdateByID, "macOS configuration profile", id, err)
+	}
+
+	prepared := *profile
+	prepared.General.Payloads = payloads
+
 	requestBody := struct {
 		XMLName xml.Name `xml:"os_x_configuration_profile"`
 		*ResourceMacOSConfigurationProfile
 	}{
-		ResourceMacOSConfigurationProfile: profile,
+		ResourceMacOSConfigurationProfile: &prepared,
 	}
 
 	var response ResponseMacOSConfigurationProfileCreationUpdate
@@ -276,15 +300,23 @@ func (c *Client) UpdateMacOSConfigurationProfileByID(id int, profile *ResourceMa
 }
 
 // UpdateMacOSConfigurationProfileByName updates an existing macOS Configuration Profile by its name on the Jamf Pro server
-// and returns the ID of the updated profile.
+// and returns the ID of the updated profile. CMS signed payloads are refused, see CreateMacOSConfigurationProfile.
 func (c *Client) UpdateMacOSConfigurationProfileByName(name string, profile *ResourceMacOSConfigurationProfile) (int, error) {
 	endpoint := fmt.Sprintf("%s/name/%s", uriMacOSConfigurationProfiles, name)
 
+	payloads, err := buildConfigurationProfilePayloads(profile.General.Profile, profile.General.Payloads)
+	if err != nil {
+		return 0, fmt.Errorf(errMsgFailedUpdateByName, "macOS configuration profile", name, err)
+	}
+
+	prepared := *profile
+	prepared.General.Payloads = payloads
+
 	requestBody := struct {
 		XMLName xml.Name `xml:"os_x_configuration_profile"`
 		*ResourceMacOSConfigurationProfile
 	}{
-		ResourceMacOSConfigurationProfile: profile,
+		ResourceMacOSConfigurationProfile: &prepared,
 	}
 
 	var response ResponseMacOSConfigurationProfileCreationUpdate
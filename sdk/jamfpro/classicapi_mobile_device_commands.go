@@ -0,0 +1,342 @@
+// classicapi_mobile_device_commands.go
+// Jamf Pro Classic Api - Mobile Device Commands
+// api reference: https://developer.jamf.com/jamf-pro/reference/mobiledevicecommands
+// Classic API requires the structs to support an XML data structure.
+
+package jamfpro
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+const uriMobileDeviceCommands = "/JSSResource/mobiledevicecommands"
+
+// Mobile device MDM commands supported by the Classic API. They apply to iOS, iPadOS and tvOS devices,
+// subject to the command being supported by the device's platform and supervision state.
+const (
+	MobileDeviceCommandBlankPush                      = "BlankPush"
+	MobileDeviceCommandClearPasscode                  = "ClearPasscode"
+	MobileDeviceCommandClearRestrictionsPassword      = "ClearRestrictionsPassword"
+	MobileDeviceCommandDeviceLock                     = "DeviceLock"
+	MobileDeviceCommandDeviceName                     = "DeviceName"
+	MobileDeviceCommandDisableLostMode                = "DisableLostMode"
+	MobileDeviceCommandEnableLostMode                 = "EnableLostMode"
+	MobileDeviceCommandEraseDevice                    = "EraseDevice"
+	MobileDeviceCommandPlayLostModeSound              = "PlayLostModeSound"
+	MobileDeviceCommandRestartDevice                  = "RestartDevice"
+	MobileDeviceCommandSettingsDisableBluetooth       = "SettingsDisableBluetooth"
+	MobileDeviceCommandSettingsEnableBluetooth        = "SettingsEnableBluetooth"
+	MobileDeviceCommandSettingsDisableDataRoaming     = "SettingsDisableDataRoaming"
+	MobileDeviceCommandSettingsEnableDataRoaming      = "SettingsEnableDataRoaming"
+	MobileDeviceCommandSettingsDisableVoiceRoaming    = "SettingsDisableVoiceRoaming"
+	MobileDeviceCommandSettingsEnableVoiceRoaming     = "SettingsEnableVoiceRoaming"
+	MobileDeviceCommandSettingsDisablePersonalHotspot = "SettingsDisablePersonalHotspot"
+	MobileDeviceCommandSettingsEnablePersonalHotspot  = "SettingsEnablePersonalHotspot"
+	MobileDeviceCommandShutDownDevice                 = "ShutDownDevice"
+	MobileDeviceCommandUnmanageDevice                 = "UnmanageDevice"
+	MobileDeviceCommandUpdateInventory                = "UpdateInventory"
+)
+
+// mobileDeviceCommandsWithoutParameters are the commands which take no parameters.
+var mobileDeviceCommandsWithoutParameters = map[string]bool{
+	MobileDeviceCommandBlankPush:                      true,
+	MobileDeviceCommandClearPasscode:                  true,
+	MobileDeviceCommandClearRestrictionsPassword:      true,
+	MobileDeviceCommandDisableLostMode:                true,
+	MobileDeviceCommandPlayLostModeSound:              true,
+	MobileDeviceCommandRestartDevice:                  true,
+	MobileDeviceCommandSettingsDisableBluetooth:       true,
+	MobileDeviceCommandSettingsEnableBluetooth:        true,
+	MobileDeviceCommandSettingsDisableDataRoaming:     true,
+	MobileDeviceCommandSettingsEnableDataRoaming:      true,
+	MobileDeviceCommandSettingsDisableVoiceRoaming:    true,
+	MobileDeviceCommandSettingsEnableVoiceRoaming:     true,
+	MobileDeviceCommandSettingsDisablePersonalHotspot: true,
+	MobileDeviceCommandSettingsEnablePersonalHotspot:  true,
+	MobileDeviceCommandShutDownDevice:                 true,
+	MobileDeviceCommandUnmanageDevice:                 true,
+	MobileDeviceCommandUpdateInventory:                true,
+}
+
+// List
+
+// ResponseMobileDeviceCommandsList represents the response for a list of mobile device commands.
+type ResponseMobileDeviceCommandsList struct {
+	Size                 int                            `xml:"size"`
+	MobileDeviceCommands []MobileDeviceCommandsListItem `xml:"mobile_device_command"`
+}
+
+// MobileDeviceCommandsListItem represents a single mobile device command in the list.
+type MobileDeviceCommandsListItem struct {
+	UUID     string `xml:"uuid"`
+	Command  string `xml:"command"`
+	Username string `xml:"username,omitempty"`
+	DateSent string `xml:"date_sent,omitempty"`
+}
+
+// Resource
+
+// ResourceMobileDeviceCommand represents a mobile device command to be issued.
+type ResourceMobileDeviceCommand struct {
+	XMLName       xml.Name                                `xml:"mobile_device_command"`
+	General       MobileDeviceCommandSubsetGeneral        `xml:"general"`
+	MobileDevices []MobileDeviceCommandSubsetMobileDevice `xml:"mobile_devices>mobile_device,omitempty"`
+}
+
+// Subsets
+
+// MobileDeviceCommandSubsetGeneral holds the command name and its parameters.
+type MobileDeviceCommandSubsetGeneral struct {
+	Command                string `xml:"command"`
+	LockMessage            string `xml:"lock_message,omitempty"`
+	DeviceName             string `xml:"device_name,omitempty"`
+	LostModeMessage        string `xml:"lost_mode_message,omitempty"`
+	LostModePhone          string `xml:"lost_mode_phone,omitempty"`
+	LostModeFootnote       string `xml:"lost_mode_footnote,omitempty"`
+	AlwaysEnforceLostMode  *bool  `xml:"always_enforce_lost_mode,omitempty"`
+	LostModeWithSound      *bool  `xml:"lost_mode_with_sound,omitempty"`
+	PreserveDataPlan       *bool  `xml:"preserve_data_plan,omitempty"`
+	DisallowProximitySetup *bool  `xml:"disallow_proximity_setup,omitempty"`
+}
+
+// MobileDeviceCommandSubsetMobileDevice identifies a target mobile device and, when read back, its command status.
+type MobileDeviceCommandSubsetMobileDevice struct {
+	ID     int    `xml:"id"`
+	UDID   string `xml:"udid,omitempty"`
+	Name   string `xml:"name,omitempty"`
+	Status string `xml:"status,omitempty"`
+}
+
+// Response
+
+// ResponseMobileDeviceCommand represents an issued mobile device command. Jamf Pro issues a single
+// command UUID for all target devices.
+type ResponseMobileDeviceCommand struct {
+	XMLName           xml.Name                                `xml:"mobile_device_command"`
+	UUID              string                                  `xml:"uuid"`
+	Command           string                                  `xml:"command"`
+	ProfileIdentifier string                                  `xml:"profile_identifier,omitempty"`
+	DateSent          string                                  `xml:"date_sent,omitempty"`
+	MobileDevices     []MobileDeviceCommandSubsetMobileDevice `xml:"mobile_devices>mobile_device,omitempty"`
+}
+
+// MobileDeviceCommandTargets selects the mobile devices a command is sent to. Names and serial numbers
+// are resolved to mobile device IDs before the command is issued.
+type MobileDeviceCommandTargets struct {
+	IDs           []int
+	Names         []string
+	SerialNumbers []string
+}
+
+// UUIDs returns the command UUIDs issued by Jamf Pro.
+func (r *ResponseMobileDeviceCommand) UUIDs() []string {
+	if r.UUID == "" {
+		return nil
+	}
+	return []string{r.UUID}
+}
+
+// Builders
+
+// NewMobileDeviceCommand returns a command without parameters, e.g. MobileDeviceCommandRestartDevice or
+// MobileDeviceCommandSettingsEnableBluetooth.
+func NewMobileDeviceCommand(command string) *ResourceMobileDeviceCommand {
+	return &ResourceMobileDeviceCommand{General: MobileDeviceCommandSubsetGeneral{Command: command}}
+}
+
+// NewMobileDeviceLockCommand returns a DeviceLock command. message is shown on the lock screen and may be empty.
+func NewMobileDeviceLockCommand(message string) *ResourceMobileDeviceCommand {
+	command := NewMobileDeviceCommand(MobileDeviceCommandDeviceLock)
+	command.General.LockMessage = message
+	return command
+}
+
+// NewMobileDeviceNameCommand returns a DeviceName command which renames supervised devices.
+func NewMobileDeviceNameCommand(deviceName string) *ResourceMobileDeviceCommand {
+	command := NewMobileDeviceCommand(MobileDeviceCommandDeviceName)
+	command.General.DeviceName = deviceName
+	return command
+}
+
+// NewMobileDeviceEnableLostModeCommand returns an EnableLostMode command. At least one of message and
+// phone is required; footnote is optional.
+func NewMobileDeviceEnableLostModeCommand(message, phone, footnote string) *ResourceMobileDeviceCommand {
+	command := NewMobileDeviceCommand(MobileDeviceCommandEnableLostMode)
+	command.General.LostModeMessage = message
+	command.General.LostModePhone = phone
+	command.General.LostModeFootnote = footnote
+	return command
+}
+
+// NewMobileDeviceEraseCommand returns an EraseDevice command. preserveDataPlan keeps the cellular data
+// plan on devices with eSIM, disallowProximitySetup prevents Quick Start after the wipe.
+func NewMobileDeviceEraseCommand(preserveDataPlan, disallowProximitySetup bool) *ResourceMobileDeviceCommand {
+	command := NewMobileDeviceCommand(MobileDeviceCommandEraseDevice)
+	command.General.PreserveDataPlan = &preserveDataPlan
+	command.General.DisallowProximitySetup = &disallowProximitySetup
+	return command
+}
+
+// Validate checks that the command is supported and that only its own parameters are set.
+func (r *ResourceMobileDeviceCommand) Validate() error {
+	general := r.General
+
+	switch general.Command {
+	case "":
+		return fmt.Errorf("mobile device command name is required")
+	case MobileDeviceCommandDeviceLock, MobileDeviceCommandEraseDevice, MobileDeviceCommandEnableLostMode:
+	case MobileDeviceCommandDeviceName:
+		if general.DeviceName == "" {
+			return fmt.Errorf("%s requires a device name", general.Command)
+		}
+	default:
+		if !mobileDeviceCommandsWithoutParameters[general.Command] {
+			return fmt.Errorf("unsupported mobile device command: %s", general.Command)
+		}
+	}
+
+	if general.Command == MobileDeviceCommandEnableLostMode && general.LostModeMessage == "" && general.LostModePhone == "" {
+		return fmt.Errorf("%s requires a message or a phone number", general.Command)
+	}
+
+	if general.LockMessage != "" && general.Command != MobileDeviceCommandDeviceLock {
+		return fmt.Errorf("lock message is only supported by %s", MobileDeviceCommandDeviceLock)
+	}
+
+	if general.Command != MobileDeviceCommandEnableLostMode &&
+		(general.LostModeMessage != "" || general.LostModePhone != "" || general.LostModeFootnote != "" ||
+			general.AlwaysEnforceLostMode != nil || general.LostModeWithSound != nil) {
+		return fmt.Errorf("lost mode options are only supported by %s", MobileDeviceCommandEnableLostMode)
+	}
+
+	if general.Command != MobileDeviceCommandEraseDevice && (general.PreserveDataPlan != nil || general.DisallowProximitySetup != nil) {
+		return fmt.Errorf("erase options are only supported by %s", MobileDeviceCommandEraseDevice)
+	}
+
+	return nil
+}
+
+// CRUD
+
+// GetMobileDeviceCommands retrieves all mobile device commands.
+func (c *Client) GetMobileDeviceCommands() (*ResponseMobileDeviceCommandsList, error) {
+	endpoint := uriMobileDeviceCommands
+
+	var commandsList ResponseMobileDeviceCommandsList
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &commandsList)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGet, "mobile device commands", err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &commandsList, nil
+}
+
+// GetMobileDeviceCommandsByName retrieves all mobile device commands with the given command name, e.g. DeviceLock.
+func (c *Client) GetMobileDeviceCommandsByName(name string) (*ResponseMobileDeviceCommandsList, error) {
+	endpoint := fmt.Sprintf("%s/name/%s", uriMobileDeviceCommands, name)
+
+	var commandsList ResponseMobileDeviceCommandsList
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &commandsList)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByName, "mobile device commands", name, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &commandsList, nil
+}
+
+// GetMobileDeviceCommandByUUID retrieves a mobile device command and its per device status by its UUID.
+func (c *Client) GetMobileDeviceCommandByUUID(uuid string) (*ResponseMobileDeviceCommand, error) {
+	endpoint := fmt.Sprintf("%s/uuid/%s", uriMobileDeviceCommands, uuid)
+
+	var command ResponseMobileDeviceCommand
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &command)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByString, "mobile device command", "uuid", uuid, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &command, nil
+}
+
+// SendMobileDeviceCommand validates command and issues it to the target mobile devices.
+func (c *Client) SendMobileDeviceCommand(command *ResourceMobileDeviceCommand, targets MobileDeviceCommandTargets) (*ResponseMobileDeviceCommand, error) {
+	if err := command.Validate(); err != nil {
+		return nil, fmt.Errorf(errMsgFailedCreate, "mobile device command", err)
+	}
+
+	deviceIDs, err := c.resolveMobileDeviceCommandTargets(targets)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedCreate, "mobile device command", err)
+	}
+
+	prepared := *command
+	prepared.MobileDevices = make([]MobileDeviceCommandSubsetMobileDevice, 0, len(deviceIDs))
+	for _, id := range deviceIDs {
+		prepared.MobileDevices = append(prepared.MobileDevices, MobileDeviceCommandSubsetMobileDevice{ID: id})
+	}
+
+	endpoint := fmt.Sprintf("%s/command", uriMobileDeviceCommands)
+
+	var response ResponseMobileDeviceCommand
+	resp, err := c.HTTP.DoRequest("POST", endpoint, &prepared, &response)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedCreate, "mobile device command", err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &response, nil
+}
+
+// resolveMobileDeviceCommandTargets returns the de-duplicated mobile device IDs selected by targets.
+func (c *Client) resolveMobileDeviceCommandTargets(targets MobileDeviceCommandTargets) ([]int, error) {
+	seen := map[int]bool{}
+	var ids []int
+
+	addID := func(id int) {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	for _, id := range targets.IDs {
+		addID(id)
+	}
+
+	for _, name := range targets.Names {
+		device, err := c.GetMobileDeviceByName(name)
+		if err != nil {
+			return nil, err
+		}
+		addID(device.General.ID)
+	}
+
+	for _, serial := range targets.SerialNumbers {
+		device, err := c.GetMobileDeviceBySerialNumber(serial)
+		if err != nil {
+			return nil, err
+		}
+		addID(device.General.ID)
+	}
+
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("no target mobile devices specified")
+	}
+
+	return ids, nil
+}
@@ -16,6 +16,8 @@ package jamfpro
 import (
 	"encoding/xml"
 	"fmt"
+
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/mobileconfig"
 )
 
 const uriMobileDeviceConfigurationProfiles = "/JSSResource/mobiledeviceconfigurationprofiles"
@@ -62,6 +64,10 @@ type MobileDeviceConfigurationProfileSubsetGeneral struct {
 	RedeployOnUpdate              string                 `xml:"redeploy_on_update,omitempty"`
 	RedeployDaysBeforeCertExpires int                    `xml:"redeploy_Dayss_before_certificate_expires,omitempty"`
 	Payloads                      string                 `xml:"payloads,omitempty"`
+
+	// Profile optionally supplies the payloads as a typed configuration profile. When set it is
+	// marshaled into Payloads on create and update.
+	Profile *mobileconfig.Profile `xml:"-"`
 }
 
 type MobileDeviceConfigurationProfileSubsetScope struct {
@@ -222,15 +228,25 @@ func (c *Client) GetMobileDeviceConfigurationProfileByNameWithSubset(name string
 }
 
 // CreateMobileDeviceConfigurationProfile creates a new mobile device configuration profile on the Jamf Pro server.
+// CMS signed payloads are refused, as the Classic API only accepts the unsigned profile: verify a signed profile with
+// mobileconfig.Verify and send the returned content, as shown in recipes/macos_configuration_profiles/CreateMacOSConfigurationProfileFromSignedProfile.
 func (c *Client) CreateMobileDeviceConfigurationProfile(profile *ResourceMobileDeviceConfigurationProfile) (*ResponseMobileDeviceConfigurationProfileCreateAndUpdate, error) {
 	endpoint := fmt.Sprintf("%s/id/0", uriMobileDeviceConfigurationProfiles)
 
+	payloads, err := buildConfigurationProfilePayloads(profile.General.Profile, profile.General.Payloads)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedCreate, "mobile device configuration profile", err)
+	}
+
+	prepared := *profile
+	prepared.General.Payloads = payloads
+
 	// Wrap the profile with the desired XML name using an anonymous struct
 	requestBody := struct {
 		XMLName xml.Name `xml:"configuration_profile"`
 		*ResourceMobileDeviceConfigurationProfile
 	}{
-		ResourceMobileDeviceConfigurationProfile: profile,
+		ResourceMobileDeviceConfigurationProfile: &prepared,
 	}
 
 	var responseProfile ResponseMobileDeviceConfigurationProfileCreateAndUpdate
@@ -247,15 +263,24 @@ func (c *Client) CreateMobileDeviceConfigurationProfile(profile *ResourceMobileD
 }
 
 // UpdateMobileDeviceConfigurationProfileByID updates a mobile device configuration profile by its ID on the Jamf Pro server.
+// CMS signed payloads are refused, see CreateMobileDeviceConfigurationProfile.
 func (c *Client) UpdateMobileDeviceConfigurationProfileByID(id int, profile *ResourceMobileDeviceConfigurationProfile) (*ResponseMobileDeviceConfigurationProfileCreateAndUpdate, error) {
 	endpoint := fmt.Sprintf("%s/id/%d", uriMobileDeviceConfigurationProfiles, id)
 
+	payloads, err := buildConfigurationProfilePayloads(profile.General.Profile, profile.General.Payloads)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedUpdateByID, "mobile device configuration profile", id, err)
+	}
+
+	prepared := *profile
+	prepared.General.Payloads = payloads
+
 	// Wrap the profile with the desired XML name using an anonymous struct
 	requestBody := struct {
 		XMLName xml.Name `xml:"configuration_profile"`
 		*ResourceMobileDeviceConfigurationProfile
 	}{
-		ResourceMobileDeviceConfigurationProfile: profile,
+		ResourceMobileDeviceConfigurationProfile: &prepared,
 	}
 
 	var responseProfile ResponseMobileDeviceConfigurationProfileCreateAndUpdate
@@ -272,14 +297,23 @@ func (c *Client) UpdateMobileDeviceConfigurationProfileByID(id int, profile *Res
 }
 
 // UpdateMobileDeviceConfigurationProfileByName updates a mobile device configuration profile by its name on the Jamf Pro server.
+// CMS signed payloads are refused, see CreateMobileDeviceConfigurationProfile.
 func (c *Client) UpdateMobileDeviceConfigurationProfileByName(name string, profile *ResourceMobileDeviceConfigurationProfile) (*ResponseMobileDeviceConfigurationProfileCreateAndUpdate, error) {
 	endpoint := fmt.Sprintf("%s/name/%s", uriMobileDeviceConfigurationProfiles, name)
 
+	payloads, err := buildConfigurationProfilePayloads(profile.General.Profile, profile.General.Payloads)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedUpdateByName, "mobile device configuration profile", name, err)
+	}
+
+	prepared := *profile
+	prepared.General.Payloads = payloads
+
 	requestBody := struct {
 		XMLName xml.Name `xml:"configuration_profile"`
 		*ResourceMobileDeviceConfigurationProfile
 	}{
-		ResourceMobileDeviceConfigurationProfile: profile,
+		ResourceMobileDeviceConfigurationProfile: &prepared,
 	}
 
 	var responseProfile ResponseMobileDeviceConfigurationProfileCreateAndUpdate
@@ -0,0 +1,89 @@
+// classicapi_mobile_device_history.go
+// Jamf Pro Classic Api - Mobile Device History
+// api reference: https://developer.jamf.com/jamf-pro/reference/mobiledevicehistory
+// Classic API requires the structs to support an XML data structure.
+
+package jamfpro
+
+import "fmt"
+
+const uriMobileDeviceHistory = "/JSSResource/mobiledevicehistory"
+
+// ResourceMobileDeviceHistory represents the root structure of the mobile device history resource.
+type ResourceMobileDeviceHistory struct {
+	General            MobileDeviceHistorySubsetGeneral            `xml:"general"`
+	ManagementCommands MobileDeviceHistorySubsetManagementCommands `xml:"management_commands,omitempty"`
+}
+
+// MobileDeviceHistorySubsetGeneral stores general information about the mobile device.
+type MobileDeviceHistorySubsetGeneral struct {
+	ID           int    `xml:"id,omitempty"`
+	Name         string `xml:"name,omitempty"`
+	UDID         string `xml:"udid,omitempty"`
+	SerialNumber string `xml:"serial_number,omitempty"`
+	MacAddress   string `xml:"mac_address,omitempty"`
+}
+
+// MobileDeviceHistorySubsetManagementCommands groups completed, pending, and failed management commands.
+type MobileDeviceHistorySubsetManagementCommands struct {
+	Completed []MobileDeviceHistorySubsetCommand `xml:"completed>command,omitempty"`
+	Pending   []MobileDeviceHistorySubsetCommand `xml:"pending>command,omitempty"`
+	Failed    []MobileDeviceHistorySubsetCommand `xml:"failed>command,omitempty"`
+}
+
+// MobileDeviceHistorySubsetCommand details a management command with its issue and completion status.
+type MobileDeviceHistorySubsetCommand struct {
+	Name           string `xml:"name,omitempty"`
+	Status         string `xml:"status,omitempty"`
+	Error          string `xml:"error,omitempty"`
+	Issued         string `xml:"issued,omitempty"`
+	IssuedEpoch    int64  `xml:"issued_epoch,omitempty"`
+	IssuedUTC      string `xml:"issued_utc,omitempty"`
+	LastPush       string `xml:"last_push,omitempty"`
+	LastPushEpoch  int64  `xml:"last_push_epoch,omitempty"`
+	LastPushUTC    string `xml:"last_push_utc,omitempty"`
+	Username       string `xml:"username,omitempty"`
+	Completed      string `xml:"completed,omitempty"`
+	CompletedEpoch int64  `xml:"completed_epoch,omitempty"`
+	CompletedUTC   string `xml:"completed_utc,omitempty"`
+	Failed         string `xml:"failed,omitempty"`
+	FailedEpoch    int64  `xml:"failed_epoch,omitempty"`
+	FailedUTC      string `xml:"failed_utc,omitempty"`
+}
+
+// CRUD Methods
+
+// GetMobileDeviceHistoryByID retrieves the historical information of a mobile device given its ID.
+func (c *Client) GetMobileDeviceHistoryByID(id int) (*ResourceMobileDeviceHistory, error) {
+	endpoint := fmt.Sprintf("%s/id/%d", uriMobileDeviceHistory, id)
+
+	var history ResourceMobileDeviceHistory
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &history)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByID, "mobile device history", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &history, nil
+}
+
+// GetMobileDeviceHistoryByIDAndDataSubset retrieves a subset of the historical information of a mobile device
+// given its ID and subset name, e.g. ManagementCommands.
+func (c *Client) GetMobileDeviceHistoryByIDAndDataSubset(id int, subset string) (*ResourceMobileDeviceHistory, error) {
+	endpoint := fmt.Sprintf("%s/id/%d/subset/%s", uriMobileDeviceHistory, id, subset)
+
+	var history ResourceMobileDeviceHistory
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &history)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByID, "mobile device history with data subset", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &history, nil
+}
@@ -274,6 +274,23 @@ func (c *Client) GetMobileDeviceByName(name string) (*ResourceMobileDevice, erro
 	return &device, nil
 }
 
+// GetMobileDeviceBySerialNumber retrieves a specific mobile device by its serial number.
+func (c *Client) GetMobileDeviceBySerialNumber(serial string) (*ResourceMobileDevice, error) {
+	endpoint := fmt.Sprintf("%s/serialnumber/%s", uriMobileDevices, serial)
+
+	var device ResourceMobileDevice
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &device)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByString, "mobile device", "serial number", serial, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &device, nil
+}
+
 // GetMobileDeviceByIDAndDataSubset retrieves a specific subset of data for a mobile device by its ID.
 func (c *Client) GetMobileDeviceByIDAndDataSubset(id int, subset string) (*ResourceMobileDevice, error) {
 	endpoint := fmt.Sprintf("%s/id/%d/subset/%s", uriMobileDevices, id, subset)
@@ -242,6 +242,32 @@ func (c *Client) UpdatePatchPolicy(policy *ResourcePatchPolicies, softwareTitleC
 	return &responsePolicy, nil
 }
 
+// UpdatePatchPolicyTargetVersionByID changes only the target version of a patch policy, leaving its scope and
+// user interaction untouched.
+func (c *Client) UpdatePatchPolicyTargetVersionByID(id int, targetVersion string) error {
+	endpoint := fmt.Sprintf("%s/id/%d", uriPatchPolicies, id)
+
+	requestBody := struct {
+		XMLName xml.Name `xml:"patch_policy"`
+		General struct {
+			TargetVersion string `xml:"target_version"`
+		} `xml:"general"`
+	}{}
+	requestBody.General.TargetVersion = targetVersion
+
+	var responsePolicy ResourcePatchPolicies
+	resp, err := c.HTTP.DoRequest("PUT", endpoint, &requestBody, &responsePolicy)
+	if err != nil {
+		return fmt.Errorf(errMsgFailedUpdateByID, "patch policy target version", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return nil
+}
+
 // DeletePatchPolicyByID deletes a patch policy by its ID.
 func (c *Client) DeletePatchPolicyByID(id int) error {
 	endpoint := fmt.Sprintf("%s/id/%d", uriPatchPolicies, id)
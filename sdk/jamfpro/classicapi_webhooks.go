@@ -45,6 +45,7 @@ type ResourceWebhook struct {
 	AuthenticationType          string                                      `xml:"authentication_type,omitempty"`
 	Username                    string                                      `xml:"username,omitempty"`
 	Password                    string                                      `xml:"password,omitempty"`
+	AuthorizationHeaders        string                                      `xml:"authorization_headers,omitempty"`
 	EnableDisplayFieldsForGroup bool                                        `xml:"enable_display_fields_for_group_object,omitempty"`
 	DisplayFields               []SharedAdvancedSearchContainerDisplayField `xml:"display_fields>display_field,omitempty"`
 	SmartGroupID                int                                         `xml:"smart_group_id,omitempty"`
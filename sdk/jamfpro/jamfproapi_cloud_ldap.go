@@ -5,11 +5,21 @@
 
 package jamfpro
 
-import "fmt"
+import (
+	"fmt"
 
-// TODO - Figure out if we need this.
+	"github.com/mitchellh/mapstructure"
+)
 
 const uriCloudLdaps = "/api/v2/cloud-ldaps"
+const uriCloudIdentityProviders = "/api/v1/cloud-idp"
+
+// List
+
+type ResponseCloudIdentityProvidersList struct {
+	TotalCount int                             `json:"totalCount"`
+	Results    []ResourceCloudIdentityProvider `json:"results"`
+}
 
 // Responses
 
@@ -19,8 +29,50 @@ type ResponseCloudIdentityProviderDefaultMappings struct {
 	CloudIdentityProviderDefaultMappingsSubsetMembershipMappings CloudIdentityProviderDefaultMappingsSubsetMembershipMappings `json:"membershipMappings"`
 }
 
+// Resource
+
+type ResourceCloudIdentityProvider struct {
+	ID           string `json:"id"`
+	DisplayName  string `json:"displayName"`
+	Enabled      bool   `json:"enabled"`
+	ProviderName string `json:"providerName"`
+}
+
+// ResourceCloudLdap represents the configuration of a Cloud Identity Provider. Only the common and server
+// settings are modelled.
+type ResourceCloudLdap struct {
+	CloudIdPCommon CloudLdapSubsetCloudIdPCommon `json:"cloudIdPCommon"`
+	Server         CloudLdapSubsetServer         `json:"server"`
+}
+
 // Subsets & Containers
 
+type CloudLdapSubsetCloudIdPCommon struct {
+	ID           string `json:"id"`
+	ProviderName string `json:"providerName"`
+	DisplayName  string `json:"displayName"`
+}
+
+type CloudLdapSubsetServer struct {
+	Enabled                                  bool                    `json:"enabled"`
+	ServerUrl                                string                  `json:"serverUrl"`
+	DomainName                               string                  `json:"domainName"`
+	Port                                     int                     `json:"port"`
+	Keystore                                 CloudLdapSubsetKeystore `json:"keystore"`
+	ConnectionTimeout                        int                     `json:"connectionTimeout"`
+	SearchTimeout                            int                     `json:"searchTimeout"`
+	UseWildcards                             bool                    `json:"useWildcards"`
+	ConnectionType                           string                  `json:"connectionType"`
+	MembershipCalculationOptimizationEnabled bool                    `json:"membershipCalculationOptimizationEnabled"`
+}
+
+type CloudLdapSubsetKeystore struct {
+	Type           string `json:"type"`
+	ExpirationDate string `json:"expirationDate"`
+	Subject        string `json:"subject"`
+	FileName       string `json:"fileName"`
+}
+
 type CloudIdentityProviderDefaultMappingsSubsetUserMappings struct {
 	ObjectClassLimitation string `json:"objectClassLimitation"`
 	ObjectClasses         string `json:"objectClasses"`
@@ -69,5 +121,43 @@ func (c *Client) GetDefaultCloudIdentityProviderDefaultMappings(providerName str
 	}
 
 	return &out, nil
+}
+
+// GetCloudIdentityProviders retrieves a list of all Cloud Identity Providers.
+func (c *Client) GetCloudIdentityProviders(sort_filter string) (*ResponseCloudIdentityProvidersList, error) {
+	resp, err := c.DoPaginatedGet(uriCloudIdentityProviders, standardPageSize, startingPageNumber, sort_filter)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedPaginatedGet, "cloud identity providers", err)
+	}
+
+	var out ResponseCloudIdentityProvidersList
+	out.TotalCount = resp.Size
+
+	for _, value := range resp.Results {
+		var newObj ResourceCloudIdentityProvider
+		err := mapstructure.Decode(value, &newObj)
+		if err != nil {
+			return nil, fmt.Errorf(errMsgFailedMapstruct, "cloud identity provider", err)
+		}
+		out.Results = append(out.Results, newObj)
+	}
+
+	return &out, nil
+}
 
+// GetCloudLdapByID retrieves the configuration of a Cloud Identity Provider, including its keystore.
+func (c *Client) GetCloudLdapByID(id string) (*ResourceCloudLdap, error) {
+	endpoint := fmt.Sprintf("%s/%s", uriCloudLdaps, id)
+	var out ResourceCloudLdap
+
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByID, "cloud ldap", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
 }
@@ -18,6 +18,10 @@ import (
 
 const uriComputersInventory = "/api/v1/computers-inventory-detail" // Define the constant for the computers inventory endpoint
 
+// uriComputersInventoryProAPI serves the FileVault and recovery lock endpoints, which are not available under
+// computers-inventory-detail.
+const uriComputersInventoryProAPI = "/api/v1/computers-inventory"
+
 // List
 
 // ResponseComputerInventoryList represents the top-level JSON response structure.
@@ -538,7 +542,7 @@ func (c *Client) GetComputersInventory(sort_filter string) (*ResponseComputerInv
 		uriComputersInventory,
 		standardPageSize,
 		startingPageNumber,
-		"",
+		sort_filter,
 	)
 	if err != nil {
 		return nil, fmt.Errorf(errMsgFailedPaginatedGet, "computers-inventories", err)
@@ -628,7 +632,7 @@ func (c *Client) DeleteComputerInventoryByID(id string) error {
 
 // GetComputersFileVaultInventory retrieves all computer inventory filevault information.
 func (c *Client) GetComputersFileVaultInventory(sort_filter string) (*FileVaultInventoryList, error) {
-	endpoint := fmt.Sprintf("%s/filevault", uriComputersInventory)
+	endpoint := fmt.Sprintf("%s/filevault", uriComputersInventoryProAPI)
 	resp, err := c.DoPaginatedGet(
 		endpoint,
 		standardPageSize,
@@ -655,7 +659,7 @@ func (c *Client) GetComputersFileVaultInventory(sort_filter string) (*FileVaultI
 
 // GetComputerFileVaultInventoryByID returns file vault details by the computer ID.
 func (c *Client) GetComputerFileVaultInventoryByID(id string) (*FileVaultInventory, error) {
-	endpoint := fmt.Sprintf("%s/%s/filevault", uriComputersInventory, id)
+	endpoint := fmt.Sprintf("%s/%s/filevault", uriComputersInventoryProAPI, id)
 
 	var fileVaultInventory FileVaultInventory
 	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &fileVaultInventory)
@@ -672,7 +676,7 @@ func (c *Client) GetComputerFileVaultInventoryByID(id string) (*FileVaultInvento
 
 // GetComputerRecoveryLockPasswordByID returns a computer recover lock password by the computer ID.
 func (c *Client) GetComputerRecoveryLockPasswordByID(id string) (*ResponseRecoveryLockPassword, error) {
-	endpoint := fmt.Sprintf("%s/%s/view-recovery-lock-password", uriComputersInventory, id)
+	endpoint := fmt.Sprintf("%s/%s/view-recovery-lock-password", uriComputersInventoryProAPI, id)
 
 	var recoveryLockPasswordResponse ResponseRecoveryLockPassword
 	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &recoveryLockPasswordResponse)
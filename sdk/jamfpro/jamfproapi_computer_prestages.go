@@ -37,6 +37,11 @@ type DeviceScopeSubsetAssignmentItem struct {
 	UserAssigned   string `json:"userAssigned"`
 }
 
+// ResponseComputerPrestageScopeAssignments represents the prestage assignment of every scoped serial number.
+type ResponseComputerPrestageScopeAssignments struct {
+	SerialsByPrestageId map[string]string `json:"serialsByPrestageId"`
+}
+
 // ResponseComputerPrestageCreate represents the response structure for creating a building.
 type ResponseComputerPrestageCreate struct {
 	ID   string `json:"id"`
@@ -316,3 +321,75 @@ func (c *Client) GetDeviceScopeForComputerPrestageByID(id string) (*ResponseDevi
 
 	return &deviceScope, nil
 }
+
+// GetComputerPrestageScopeAssignments retrieves the prestage ID of every serial number in a computer prestage
+// scope, keyed by serial number.
+func (c *Client) GetComputerPrestageScopeAssignments() (*ResponseComputerPrestageScopeAssignments, error) {
+	endpoint := fmt.Sprintf("%s/scope", uriComputerPrestagesV2)
+
+	var assignments ResponseComputerPrestageScopeAssignments
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &assignments)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGet, "computer prestage scope assignments", err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &assignments, nil
+}
+
+// GetComputerPrestageByScopedSerialNumber retrieves the computer prestage whose scope holds a serial number.
+func (c *Client) GetComputerPrestageByScopedSerialNumber(serialNumber string) (*ResourceComputerPrestage, error) {
+	assignments, err := c.GetComputerPrestageScopeAssignments()
+	if err != nil {
+		return nil, err
+	}
+
+	id, ok := assignments.SerialsByPrestageId[serialNumber]
+	if !ok {
+		return nil, fmt.Errorf(errMsgFailedGetByString, "computer prestage", "scoped serial number", serialNumber, "serial number is not in any prestage scope")
+	}
+
+	return c.GetComputerPrestageByID(id)
+}
+
+// AddDeviceScopeForComputerPrestageByID adds serial numbers to the scope of a computer prestage. Serial numbers
+// already in scope are skipped; on a versionLock conflict the change is retried against the current scope.
+func (c *Client) AddDeviceScopeForComputerPrestageByID(id string, serialNumbers []string) (*ResponseDeviceScope, error) {
+	endpoint := fmt.Sprintf("%s/%s/scope", uriComputerPrestagesV2, id)
+
+	deviceScope, err := c.updatePrestageScope(endpoint, prestageScopeAdd, serialNumbers)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedUpdateByID, "computer prestage scope", id, err)
+	}
+
+	return deviceScope, nil
+}
+
+// RemoveDeviceScopeForComputerPrestageByID removes serial numbers from the scope of a computer prestage. Serial
+// numbers not in scope are skipped; on a versionLock conflict the change is retried against the current scope.
+func (c *Client) RemoveDeviceScopeForComputerPrestageByID(id string, serialNumbers []string) (*ResponseDeviceScope, error) {
+	endpoint := fmt.Sprintf("%s/%s/scope", uriComputerPrestagesV2, id)
+
+	deviceScope, err := c.updatePrestageScope(endpoint, prestageScopeRemove, serialNumbers)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedUpdateByID, "computer prestage scope", id, err)
+	}
+
+	return deviceScope, nil
+}
+
+// ReplaceDeviceScopeForComputerPrestageByID replaces the scope of a computer prestage with the given serial
+// numbers. On a versionLock conflict the change is retried against the current scope.
+func (c *Client) ReplaceDeviceScopeForComputerPrestageByID(id string, serialNumbers []string) (*ResponseDeviceScope, error) {
+	endpoint := fmt.Sprintf("%s/%s/scope", uriComputerPrestagesV2, id)
+
+	deviceScope, err := c.updatePrestageScope(endpoint, prestageScopeReplace, serialNumbers)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedUpdateByID, "computer prestage scope", id, err)
+	}
+
+	return deviceScope, nil
+}
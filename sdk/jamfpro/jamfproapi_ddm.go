@@ -0,0 +1,290 @@
+// jamfproapi_ddm.go
+// Jamf Pro Api - Declarative Device Management
+// api reference: https://developer.jamf.com/jamf-pro/reference/get_v1-ddm-clientmanagementid-status-items
+// Jamf Pro API requires the structs to support a JSON data structure.
+// Devices are addressed by their client management ID, e.g. ComputerInventorySubsetGeneral.ManagementId.
+// Status items are the latest values of the DDM status report of a device, keyed by the status item path
+// Apple defines, e.g. "device.operating-system.version". All values are returned as strings.
+
+package jamfpro
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const uriDDM = "/api/v1/ddm"
+
+// DDM status item keys
+const (
+	DDMStatusKeyOperatingSystemVersion       = "device.operating-system.version"
+	DDMStatusKeyOperatingSystemBuildVersion  = "device.operating-system.build-version"
+	DDMStatusKeyOperatingSystemSupplemental  = "device.operating-system.supplemental.build-version"
+	DDMStatusKeyOperatingSystemFamily        = "device.operating-system.family"
+	DDMStatusKeyModelIdentifier              = "device.model.identifier"
+	DDMStatusKeySerialNumber                 = "device.identifier.serial-number"
+	DDMStatusKeyUDID                         = "device.identifier.udid"
+	DDMStatusKeyBatteryHealth                = "device.power.battery-health"
+	DDMStatusKeySoftwareUpdateInstallState   = "softwareupdate.install-state"
+	DDMStatusKeySoftwareUpdatePendingVersion = "softwareupdate.pending-version.os-version"
+	DDMStatusKeySoftwareUpdateInstallReason  = "softwareupdate.install-reason.reason"
+	DDMStatusKeySoftwareUpdateFailureCount   = "softwareupdate.failure-reason.count"
+	DDMStatusKeySoftwareUpdateFailureReason  = "softwareupdate.failure-reason.reason"
+	DDMStatusKeySoftwareUpdateBetaEnrollment = "softwareupdate.beta-enrollment"
+	DDMStatusKeyPasscodeCompliant            = "passcode.is-compliant"
+	DDMStatusKeyPasscodePresent              = "passcode.is-present"
+	DDMStatusKeyFileVaultEnabled             = "diskmanagement.filevault.enabled"
+	DDMStatusKeyClientCapabilities           = "management.client-capabilities"
+	DDMStatusKeyDeclarationsActivations      = "management.declarations.activations"
+	DDMStatusKeyDeclarationsConfigurations   = "management.declarations.configurations"
+	DDMStatusKeyDeclarationsAssets           = "management.declarations.assets"
+	DDMStatusKeyDeclarationsManagement       = "management.declarations.management"
+	DDMStatusKeyDeclarationsPrefix           = "management.declarations"
+)
+
+// DDM declaration validity
+const (
+	DDMDeclarationValid           = "valid"
+	DDMDeclarationInvalid         = "invalid"
+	DDMDeclarationValidityUnknown = "unknown"
+)
+
+// Response
+
+type ResponseDDMStatusItems struct {
+	StatusItems []ResourceDDMStatusItem `json:"statusItems"`
+}
+
+// Resource
+
+type ResourceDDMStatusItem struct {
+	Key            string `json:"key"`
+	Value          string `json:"value"`
+	LastUpdateTime string `json:"lastUpdateTime"`
+}
+
+// DDMStatus is the typed view of the status items of a device. Fields of status items the device did not
+// report are left empty, or nil for booleans.
+type DDMStatus struct {
+	ClientManagementID           string                 `json:"clientManagementId"`
+	OperatingSystemVersion       string                 `json:"operatingSystemVersion,omitempty"`
+	OperatingSystemBuildVersion  string                 `json:"operatingSystemBuildVersion,omitempty"`
+	OperatingSystemSupplemental  string                 `json:"operatingSystemSupplemental,omitempty"`
+	ModelIdentifier              string                 `json:"modelIdentifier,omitempty"`
+	SerialNumber                 string                 `json:"serialNumber,omitempty"`
+	BatteryHealth                string                 `json:"batteryHealth,omitempty"`
+	SoftwareUpdateInstallState   string                 `json:"softwareUpdateInstallState,omitempty"`
+	SoftwareUpdatePendingVersion string                 `json:"softwareUpdatePendingVersion,omitempty"`
+	SoftwareUpdateInstallReason  string                 `json:"softwareUpdateInstallReason,omitempty"`
+	SoftwareUpdateFailureCount   int                    `json:"softwareUpdateFailureCount,omitempty"`
+	SoftwareUpdateFailureReason  string                 `json:"softwareUpdateFailureReason,omitempty"`
+	PasscodeCompliant            *bool                  `json:"passcodeCompliant,omitempty"`
+	PasscodePresent              *bool                  `json:"passcodePresent,omitempty"`
+	FileVaultEnabled             *bool                  `json:"fileVaultEnabled,omitempty"`
+	ClientCapabilities           string                 `json:"clientCapabilities,omitempty"`
+	Declarations                 []DDMDeclarationStatus `json:"declarations,omitempty"`
+	// Items holds every status item by key, including the ones without a typed field.
+	Items map[string]ResourceDDMStatusItem `json:"items"`
+}
+
+// DDMDeclarationStatus is the state of a declaration on a device.
+type DDMDeclarationStatus struct {
+	Identifier  string `json:"identifier"`
+	Kind        string `json:"kind"`
+	Active      bool   `json:"active"`
+	Valid       string `json:"valid"`
+	ServerToken string `json:"serverToken"`
+}
+
+// Applied reports whether the device accepted the declaration and has it active.
+func (d DDMDeclarationStatus) Applied() bool {
+	return d.Active && d.Valid == DDMDeclarationValid
+}
+
+// Declaration returns the status of the declaration with the given identifier.
+func (s *DDMStatus) Declaration(identifier string) (DDMDeclarationStatus, bool) {
+	for _, declaration := range s.Declarations {
+		if declaration.Identifier == identifier {
+			return declaration, true
+		}
+	}
+	return DDMDeclarationStatus{}, false
+}
+
+// CRUD
+
+// GetDDMStatusItemsByClientManagementID retrieves all status items of a device.
+func (c *Client) GetDDMStatusItemsByClientManagementID(clientManagementID string) (*ResponseDDMStatusItems, error) {
+	endpoint := fmt.Sprintf("%s/%s/status-items", uriDDM, clientManagementID)
+
+	var out ResponseDDMStatusItems
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByID, "ddm status items", clientManagementID, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// GetDDMStatusItemByClientManagementIDAndKey retrieves a single status item of a device.
+func (c *Client) GetDDMStatusItemByClientManagementIDAndKey(clientManagementID string, key string) (*ResourceDDMStatusItem, error) {
+	endpoint := fmt.Sprintf("%s/%s/status-items/%s", uriDDM, clientManagementID, key)
+
+	var out ResourceDDMStatusItem
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByString, "ddm status item", "key", key, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// GetDDMStatusByClientManagementID retrieves the status items of a device as a DDMStatus.
+func (c *Client) GetDDMStatusByClientManagementID(clientManagementID string) (*DDMStatus, error) {
+	items, err := c.GetDDMStatusItemsByClientManagementID(clientManagementID)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewDDMStatus(clientManagementID, items.StatusItems), nil
+}
+
+// SyncDDMByClientManagementID asks a device to synchronise its declarations, after which it sends a fresh
+// status report.
+func (c *Client) SyncDDMByClientManagementID(clientManagementID string) error {
+	endpoint := fmt.Sprintf("%s/%s/sync", uriDDM, clientManagementID)
+
+	resp, err := c.HTTP.DoRequest("POST", endpoint, nil, nil)
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+	if err != nil && (resp == nil || resp.StatusCode != http.StatusNoContent) {
+		return fmt.Errorf("failed to sync ddm by client management id: %s, error: %v", clientManagementID, err)
+	}
+
+	return nil
+}
+
+// NewDDMStatus builds the typed view of the status items of a device.
+func NewDDMStatus(clientManagementID string, items []ResourceDDMStatusItem) *DDMStatus {
+	status := &DDMStatus{
+		ClientManagementID: clientManagementID,
+		Items:              make(map[string]ResourceDDMStatusItem, len(items)),
+	}
+
+	for _, item := range items {
+		status.Items[item.Key] = item
+
+		switch item.Key {
+		case DDMStatusKeyOperatingSystemVersion:
+			status.OperatingSystemVersion = item.Value
+		case DDMStatusKeyOperatingSystemBuildVersion:
+			status.OperatingSystemBuildVersion = item.Value
+		case DDMStatusKeyOperatingSystemSupplemental:
+			status.OperatingSystemSupplemental = item.Value
+		case DDMStatusKeyModelIdentifier:
+			status.ModelIdentifier = item.Value
+		case DDMStatusKeySerialNumber:
+			status.SerialNumber = item.Value
+		case DDMStatusKeyBatteryHealth:
+			status.BatteryHealth = item.Value
+		case DDMStatusKeySoftwareUpdateInstallState:
+			status.SoftwareUpdateInstallState = item.Value
+		case DDMStatusKeySoftwareUpdatePendingVersion:
+			status.SoftwareUpdatePendingVersion = item.Value
+		case DDMStatusKeySoftwareUpdateInstallReason:
+			status.SoftwareUpdateInstallReason = item.Value
+		case DDMStatusKeySoftwareUpdateFailureCount:
+			status.SoftwareUpdateFailureCount, _ = strconv.Atoi(item.Value)
+		case DDMStatusKeySoftwareUpdateFailureReason:
+			status.SoftwareUpdateFailureReason = item.Value
+		case DDMStatusKeyPasscodeCompliant:
+			status.PasscodeCompliant = parseDDMBool(item.Value)
+		case DDMStatusKeyPasscodePresent:
+			status.PasscodePresent = parseDDMBool(item.Value)
+		case DDMStatusKeyFileVaultEnabled:
+			status.FileVaultEnabled = parseDDMBool(item.Value)
+		case DDMStatusKeyClientCapabilities:
+			status.ClientCapabilities = item.Value
+		case DDMStatusKeyDeclarationsActivations, DDMStatusKeyDeclarationsConfigurations, DDMStatusKeyDeclarationsAssets, DDMStatusKeyDeclarationsManagement:
+			kind := strings.TrimPrefix(item.Key, DDMStatusKeyDeclarationsPrefix+".")
+			status.Declarations = append(status.Declarations, parseDDMDeclarations(kind, item.Value)...)
+		}
+	}
+
+	return status
+}
+
+func parseDDMBool(value string) *bool {
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil
+	}
+	return &parsed
+}
+
+// parseDDMDeclarations parses the value of a management.declarations status item. Depending on the Jamf Pro
+// version the list is either JSON or in the form [{active=true, identifier=..., valid=valid, server-token=...}].
+func parseDDMDeclarations(kind string, value string) []DDMDeclarationStatus {
+	var entries []map[string]interface{}
+	if err := json.Unmarshal([]byte(value), &entries); err != nil {
+		entries = parseDDMKeyValueList(value)
+	}
+
+	declarations := make([]DDMDeclarationStatus, 0, len(entries))
+	for _, entry := range entries {
+		declaration := DDMDeclarationStatus{
+			Identifier:  ddmEntryString(entry, "identifier"),
+			Kind:        kind,
+			Active:      ddmEntryString(entry, "active") == "true",
+			Valid:       ddmEntryString(entry, "valid"),
+			ServerToken: ddmEntryString(entry, "server-token"),
+		}
+		if declaration.Valid == "" {
+			declaration.Valid = DDMDeclarationValidityUnknown
+		}
+		declarations = append(declarations, declaration)
+	}
+
+	return declarations
+}
+
+func ddmEntryString(entry map[string]interface{}, key string) string {
+	value, ok := entry[key]
+	if !ok || value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+func parseDDMKeyValueList(value string) []map[string]interface{} {
+	var entries []map[string]interface{}
+	value = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(value), "["), "]")
+	for _, object := range strings.Split(value, "}") {
+		object = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(object), ","))
+		object = strings.TrimPrefix(object, "{")
+		if object == "" {
+			continue
+		}
+		entry := make(map[string]interface{})
+		for _, pair := range strings.Split(object, ",") {
+			key, val, found := strings.Cut(pair, "=")
+			if found {
+				entry[strings.TrimSpace(key)] = strings.TrimSpace(val)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
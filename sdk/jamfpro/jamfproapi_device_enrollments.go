@@ -2,11 +2,19 @@
 // Jamf Pro Api - Device Enrollments
 // api reference: https://developer.jamf.com/jamf-pro/reference/get_v1-device-enrollments
 // Jamf Pro API requires the structs to support a JSON data structure.
+// A device enrollment is an Automated Device Enrollment (ADE) instance, created from a server token (.p7m)
+// downloaded from Apple Business Manager or Apple School Manager. Server tokens expire after a year and are
+// renewed by uploading a new token to the existing instance, which keeps its prestages and device assignments.
 
 package jamfpro
 
 import (
+	"encoding/base64"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
 
 	"github.com/mitchellh/mapstructure"
 )
@@ -21,6 +29,63 @@ type ResponseDeviceEnrollmentsList struct {
 	Results    []ResourceDeviceEnrollment `json:"results"`
 }
 
+// Responses
+
+// ResponseDeviceEnrollmentCreate represents the response for creating a device enrollment instance.
+type ResponseDeviceEnrollmentCreate struct {
+	ID   string `json:"id"`
+	Href string `json:"href"`
+}
+
+// ResponseDeviceEnrollmentDevicesList represents the devices assigned to a device enrollment instance.
+type ResponseDeviceEnrollmentDevicesList struct {
+	TotalCount int                            `json:"totalCount"`
+	Results    []DeviceEnrollmentSubsetDevice `json:"results"`
+}
+
+type DeviceEnrollmentSubsetDevice struct {
+	ID                                string                                `json:"id"`
+	DeviceEnrollmentProgramInstanceId string                                `json:"deviceEnrollmentProgramInstanceId"`
+	PrestageId                        string                                `json:"prestageId"`
+	SerialNumber                      string                                `json:"serialNumber"`
+	Description                       string                                `json:"description"`
+	Model                             string                                `json:"model"`
+	Color                             string                                `json:"color"`
+	AssetTag                          string                                `json:"assetTag"`
+	ProfileStatus                     string                                `json:"profileStatus"`
+	SyncState                         DeviceEnrollmentSubsetDeviceSyncState `json:"syncState"`
+	ProfileAssignTime                 string                                `json:"profileAssignTime"`
+	ProfilePushTime                   string                                `json:"profilePushTime"`
+	DeviceAssignedDate                string                                `json:"deviceAssignedDate"`
+}
+
+type DeviceEnrollmentSubsetDeviceSyncState struct {
+	ID           int    `json:"id"`
+	SerialNumber string `json:"serialNumber"`
+	ProfileUUID  string `json:"profileUUID"`
+	SyncStatus   string `json:"syncStatus"`
+	FailureCount int    `json:"failureCount"`
+	Timestamp    int64  `json:"timestamp"`
+}
+
+// ResponseDeviceEnrollmentSyncState represents the state of a device enrollment sync with Apple.
+type ResponseDeviceEnrollmentSyncState struct {
+	SyncState  string `json:"syncState"`
+	InstanceID string `json:"instanceId"`
+	Timestamp  string `json:"timestamp"`
+}
+
+// DeviceEnrollmentTokenExpiration describes when the server token of a device enrollment instance expires.
+type DeviceEnrollmentTokenExpiration struct {
+	ID        string
+	Name      string
+	ExpiresAt time.Time
+	// ExpiresIn is the time left until ExpiresAt when it was checked; negative once expired.
+	ExpiresIn time.Duration
+	// Error is set, and ExpiresAt is zero, when the instance has no readable token expiration date.
+	Error string
+}
+
 // Resource
 
 // DeviceEnrollment represents a single device enrollment instance.
@@ -39,6 +104,19 @@ type ResourceDeviceEnrollment struct {
 	TokenExpirationDate   string `json:"tokenExpirationDate"`
 }
 
+// ResourceDeviceEnrollmentUpdate represents the fields of a device enrollment instance which can be changed.
+type ResourceDeviceEnrollmentUpdate struct {
+	Name                  string `json:"name"`
+	SupervisionIdentityId string `json:"supervisionIdentityId,omitempty"`
+	SiteId                string `json:"siteId,omitempty"`
+}
+
+// ResourceDeviceEnrollmentToken represents a server token upload. EncodedToken is the base64 encoded .p7m file.
+type ResourceDeviceEnrollmentToken struct {
+	TokenFileName string `json:"tokenFileName"`
+	EncodedToken  string `json:"encodedToken"`
+}
+
 // CRUD
 
 // GetDeviceEnrollments retrieves a paginated list of device enrollments.
@@ -67,3 +145,267 @@ func (c *Client) GetDeviceEnrollments(sort_filter string) (*ResponseDeviceEnroll
 
 	return &out, nil
 }
+
+// GetDeviceEnrollmentByID retrieves a device enrollment instance by its ID.
+func (c *Client) GetDeviceEnrollmentByID(id string) (*ResourceDeviceEnrollment, error) {
+	endpoint := fmt.Sprintf("%s/%s", uriDeviceEnrollments, id)
+
+	var out ResourceDeviceEnrollment
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByID, "device enrollment", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// GetDeviceEnrollmentByName retrieves a device enrollment instance by its name.
+func (c *Client) GetDeviceEnrollmentByName(name string) (*ResourceDeviceEnrollment, error) {
+	enrollments, err := c.GetDeviceEnrollments("")
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedPaginatedGet, "device enrollments", err)
+	}
+
+	for _, value := range enrollments.Results {
+		if value.Name == name {
+			return &value, nil
+		}
+	}
+
+	return nil, fmt.Errorf(errMsgFailedGetByName, "device enrollment", name, errMsgNoName)
+}
+
+// CreateDeviceEnrollmentWithToken creates a device enrollment instance from a server token.
+func (c *Client) CreateDeviceEnrollmentWithToken(token *ResourceDeviceEnrollmentToken) (*ResponseDeviceEnrollmentCreate, error) {
+	endpoint := fmt.Sprintf("%s/upload-token", uriDeviceEnrollments)
+
+	var out ResponseDeviceEnrollmentCreate
+	resp, err := c.HTTP.DoRequest("POST", endpoint, token, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedCreate, "device enrollment", err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// UpdateDeviceEnrollmentTokenByID renews the server token of a device enrollment instance.
+func (c *Client) UpdateDeviceEnrollmentTokenByID(id string, token *ResourceDeviceEnrollmentToken) (*ResourceDeviceEnrollment, error) {
+	endpoint := fmt.Sprintf("%s/%s/upload-token", uriDeviceEnrollments, id)
+
+	var out ResourceDeviceEnrollment
+	resp, err := c.HTTP.DoRequest("PUT", endpoint, token, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedUpdateByID, "device enrollment token", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// CreateDeviceEnrollmentWithTokenFile creates a device enrollment instance from a server token file (.p7m).
+func (c *Client) CreateDeviceEnrollmentWithTokenFile(filePath string) (*ResponseDeviceEnrollmentCreate, error) {
+	token, err := ReadDeviceEnrollmentTokenFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.CreateDeviceEnrollmentWithToken(token)
+}
+
+// UpdateDeviceEnrollmentTokenFileByID renews the server token of a device enrollment instance from a server
+// token file (.p7m).
+func (c *Client) UpdateDeviceEnrollmentTokenFileByID(id string, filePath string) (*ResourceDeviceEnrollment, error) {
+	token, err := ReadDeviceEnrollmentTokenFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.UpdateDeviceEnrollmentTokenByID(id, token)
+}
+
+// ReadDeviceEnrollmentTokenFile reads a server token file (.p7m) into a token upload.
+func ReadDeviceEnrollmentTokenFile(filePath string) (*ResourceDeviceEnrollmentToken, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read device enrollment token file: %v", err)
+	}
+
+	return &ResourceDeviceEnrollmentToken{
+		TokenFileName: filepath.Base(filePath),
+		EncodedToken:  base64.StdEncoding.EncodeToString(data),
+	}, nil
+}
+
+// UpdateDeviceEnrollmentByID updates the name, supervision identity and site of a device enrollment instance.
+func (c *Client) UpdateDeviceEnrollmentByID(id string, enrollmentUpdate *ResourceDeviceEnrollmentUpdate) (*ResourceDeviceEnrollment, error) {
+	endpoint := fmt.Sprintf("%s/%s", uriDeviceEnrollments, id)
+
+	var out ResourceDeviceEnrollment
+	resp, err := c.HTTP.DoRequest("PUT", endpoint, enrollmentUpdate, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedUpdateByID, "device enrollment", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// DeleteDeviceEnrollmentByID deletes a device enrollment instance by its ID.
+func (c *Client) DeleteDeviceEnrollmentByID(id string) error {
+	endpoint := fmt.Sprintf("%s/%s", uriDeviceEnrollments, id)
+
+	resp, err := c.HTTP.DoRequest("DELETE", endpoint, nil, nil)
+	if err != nil {
+		return fmt.Errorf(errMsgFailedDeleteByID, "device enrollment", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return nil
+}
+
+// GetDeviceEnrollmentPublicKey retrieves the PEM encoded public key, which is uploaded to Apple Business Manager
+// or Apple School Manager to generate a server token.
+func (c *Client) GetDeviceEnrollmentPublicKey() ([]byte, error) {
+	endpoint := fmt.Sprintf("%s/public-key", uriDeviceEnrollments)
+
+	var out []byte
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGet, "device enrollment public key", err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return out, nil
+}
+
+// GetDeviceEnrollmentDevicesByID retrieves the devices assigned to a device enrollment instance.
+func (c *Client) GetDeviceEnrollmentDevicesByID(id string) (*ResponseDeviceEnrollmentDevicesList, error) {
+	endpoint := fmt.Sprintf("%s/%s/devices", uriDeviceEnrollments, id)
+
+	var out ResponseDeviceEnrollmentDevicesList
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByID, "device enrollment devices", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// GetDeviceEnrollmentSyncStates retrieves the sync history of all device enrollment instances.
+func (c *Client) GetDeviceEnrollmentSyncStates() ([]ResponseDeviceEnrollmentSyncState, error) {
+	endpoint := fmt.Sprintf("%s/syncs", uriDeviceEnrollments)
+
+	var out []ResponseDeviceEnrollmentSyncState
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGet, "device enrollment sync states", err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return out, nil
+}
+
+// GetDeviceEnrollmentSyncStatesByID retrieves the sync history of a device enrollment instance.
+func (c *Client) GetDeviceEnrollmentSyncStatesByID(id string) ([]ResponseDeviceEnrollmentSyncState, error) {
+	endpoint := fmt.Sprintf("%s/%s/syncs", uriDeviceEnrollments, id)
+
+	var out []ResponseDeviceEnrollmentSyncState
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByID, "device enrollment sync states", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return out, nil
+}
+
+// GetLatestDeviceEnrollmentSyncStateByID retrieves the latest sync state of a device enrollment instance.
+func (c *Client) GetLatestDeviceEnrollmentSyncStateByID(id string) (*ResponseDeviceEnrollmentSyncState, error) {
+	endpoint := fmt.Sprintf("%s/%s/syncs/latest", uriDeviceEnrollments, id)
+
+	var out ResponseDeviceEnrollmentSyncState
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByID, "latest device enrollment sync state", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// GetDeviceEnrollmentTokenExpirations returns the server token expiry of every device enrollment instance whose
+// token expires within the given duration, soonest first. A zero duration returns every instance.
+// An instance whose token expiration date is empty or cannot be parsed does not fail the others: it is returned
+// first, whatever the duration, with Error set.
+func (c *Client) GetDeviceEnrollmentTokenExpirations(within time.Duration) ([]DeviceEnrollmentTokenExpiration, error) {
+	enrollments, err := c.GetDeviceEnrollments("")
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var out []DeviceEnrollmentTokenExpiration
+	for _, enrollment := range enrollments.Results {
+		expiration := DeviceEnrollmentTokenExpiration{
+			ID:   enrollment.ID,
+			Name: enrollment.Name,
+		}
+
+		expiresAt, err := ParseDeviceEnrollmentTokenExpirationDate(enrollment.TokenExpirationDate)
+		if err != nil {
+			expiration.Error = err.Error()
+			out = append(out, expiration)
+			continue
+		}
+
+		expiration.ExpiresAt = expiresAt
+		expiration.ExpiresIn = expiresAt.Sub(now)
+		if within > 0 && expiration.ExpiresIn > within {
+			continue
+		}
+		out = append(out, expiration)
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].ExpiresAt.Before(out[j].ExpiresAt) })
+
+	return out, nil
+}
+
+// ParseDeviceEnrollmentTokenExpirationDate parses TokenExpirationDate, which Jamf Pro returns as a date
+// (2006-01-02) and older versions as a timestamp. A date expires at the end of the day, in UTC.
+func ParseDeviceEnrollmentTokenExpirationDate(value string) (time.Time, error) {
+	return parseExpiryTimestamp(value)
+}
@@ -3,7 +3,106 @@
 // api reference: https://developer.jamf.com/jamf-pro/reference/post_v1-dock-items
 // Jamf Pro API requires the structs to support a JSON data structure.
 // Url endpoint introduced with jamf pro 11.2
+// The endpoint has no list operation; use the Classic API GetDockItems to list dock items. Dock item IDs
+// are shared between both APIs. Functions carry a V1 suffix to distinguish them from the Classic API ones.
 
 package jamfpro
 
-// TODO - endpoint currently doesnt have a full set of CRUD functions. missing a list for example
+import "fmt"
+
+const uriDockItemsV1 = "/api/v1/dock-items"
+
+// Dock item types accepted by the Jamf Pro API
+const (
+	DockItemTypeApp    = "APP"
+	DockItemTypeFile   = "FILE"
+	DockItemTypeFolder = "FOLDER"
+)
+
+// Responses
+
+// ResponseDockItemCreateV1 represents the response for creating a dock item.
+type ResponseDockItemCreateV1 struct {
+	ID   string `json:"id"`
+	Href string `json:"href"`
+}
+
+// Resource
+
+// ResourceDockItemV1 represents a dock item. Path is a file URL, e.g. file://localhost/Applications/Safari.app/.
+type ResourceDockItemV1 struct {
+	ID       string `json:"id,omitempty"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Path     string `json:"path"`
+	Contents string `json:"contents,omitempty"`
+}
+
+// CRUD
+
+// GetDockItemByIDV1 retrieves a dock item by ID.
+func (c *Client) GetDockItemByIDV1(id string) (*ResourceDockItemV1, error) {
+	endpoint := fmt.Sprintf("%s/%s", uriDockItemsV1, id)
+	var out ResourceDockItemV1
+
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByID, "dock item", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// CreateDockItemV1 creates a dock item.
+func (c *Client) CreateDockItemV1(dockItem *ResourceDockItemV1) (*ResponseDockItemCreateV1, error) {
+	endpoint := uriDockItemsV1
+	var out ResponseDockItemCreateV1
+
+	resp, err := c.HTTP.DoRequest("POST", endpoint, dockItem, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedCreate, "dock item", err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// UpdateDockItemByIDV1 updates a dock item by ID.
+func (c *Client) UpdateDockItemByIDV1(id string, dockItemUpdate *ResourceDockItemV1) (*ResourceDockItemV1, error) {
+	endpoint := fmt.Sprintf("%s/%s", uriDockItemsV1, id)
+	var out ResourceDockItemV1
+
+	resp, err := c.HTTP.DoRequest("PUT", endpoint, dockItemUpdate, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedUpdateByID, "dock item", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// DeleteDockItemByIDV1 deletes a dock item by ID.
+func (c *Client) DeleteDockItemByIDV1(id string) error {
+	endpoint := fmt.Sprintf("%s/%s", uriDockItemsV1, id)
+
+	resp, err := c.HTTP.DoRequest("DELETE", endpoint, nil, nil)
+	if err != nil {
+		return fmt.Errorf(errMsgFailedDeleteByID, "dock item", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return nil
+}
@@ -29,6 +29,12 @@ type ResponseEnrollmentCustomizationCreate struct {
 	Href string `json:"href"`
 }
 
+// ResponseEnrollmentCustomizationImageUpload represents the response for uploading a branding image. The url is
+// set as IconUrl of the branding settings.
+type ResponseEnrollmentCustomizationImageUpload struct {
+	Url string `json:"url"`
+}
+
 // Resource
 
 type ResourceEnrollmentCustomization struct {
@@ -51,7 +57,6 @@ type EnrollmentCustomizationSubsetBrandingSettings struct {
 
 // CRUD
 
-// TODO Upload an image - https://developer.jamf.com/jamf-pro/reference/post_v2-enrollment-customizations-images
 // TODO Download an image - https://developer.jamf.com/jamf-pro/reference/get_v2-enrollment-customizations-images-id
 
 // Returns paginated list of Enrollment Customization
@@ -151,3 +156,24 @@ func (c *Client) DeleteEnrollmentCustomizationByID(id string) error {
 
 	return nil
 }
+
+// UploadEnrollmentCustomizationImage uploads a branding image for use in enrollment customizations
+func (c *Client) UploadEnrollmentCustomizationImage(filePath string) (*ResponseEnrollmentCustomizationImageUpload, error) {
+	endpoint := fmt.Sprintf("%s/images", uriEnrollmentCustomizationSettings)
+
+	files := map[string]string{
+		"file": filePath,
+	}
+	var out ResponseEnrollmentCustomizationImageUpload
+
+	resp, err := c.HTTP.DoMultipartRequest("POST", endpoint, nil, files, &out)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload enrollment customization image: %v", err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
@@ -1,26 +1,49 @@
 // jamfproapi_health_check.go
 // Jamf Pro Api - Health Check
-// api reference: N/A
+// api reference: https://developer.jamf.com/jamf-pro/reference/get_v1-health-check
 // Jamf Pro API requires the structs to support an JSON data structure.
+// The endpoint reports the health of the Jamf Pro server through its status code and returns no body of
+// interest: 2xx when the server is healthy, 503 while it is starting up or cannot reach its database.
 
 package jamfpro
 
-import "fmt"
+import (
+	"fmt"
+	"net/http"
+)
 
 const uriHealthCheck = "/api/v1/health-check"
 
-// GetHealthCheck fetches a file list from Jamf Cloud Distribution Service
-func (c *Client) GetHealthCheck() ([]ResponseJCDS2List, error) {
+// Resource
+
+// ResponseHealthCheck represents the health of the Jamf Pro server.
+type ResponseHealthCheck struct {
+	Healthy    bool `json:"healthy"`
+	StatusCode int  `json:"statusCode"`
+}
+
+// GetHealthCheck checks the health of the Jamf Pro server. An error is returned when the server does not
+// report itself healthy.
+func (c *Client) GetHealthCheck() (*ResponseHealthCheck, error) {
 	endpoint := uriHealthCheck
-	var out []ResponseJCDS2List
-	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &out)
-	if err != nil {
-		return nil, fmt.Errorf(errMsgFailedGet, "Health Check", err)
-	}
 
+	// The body is ignored: a successful status is the health signal, and an empty body fails to decode.
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, nil)
 	if resp != nil && resp.Body != nil {
 		defer resp.Body.Close()
 	}
 
+	if resp == nil {
+		return &ResponseHealthCheck{Healthy: false}, fmt.Errorf(errMsgFailedGet, "health check", err)
+	}
+
+	out := &ResponseHealthCheck{
+		Healthy:    resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices,
+		StatusCode: resp.StatusCode,
+	}
+	if !out.Healthy {
+		return out, fmt.Errorf(errMsgFailedGet, "health check", fmt.Errorf("status code %d", resp.StatusCode))
+	}
+
 	return out, nil
 }
@@ -2,13 +2,70 @@
 // Jamf Pro Api - JAMF local administrator password (LAPS)
 // api reference: https://developer.jamf.com/jamf-pro/reference/get_v2-local-admin-password-pending-rotations
 // Jamf Pro API requires the structs to support an JSON data structure.
+// Device endpoints address a computer by its client management ID, e.g. ComputerInventorySubsetGeneral.ManagementId.
+// Viewing a password is audited and, with auto rotate enabled, schedules its rotation.
 
 package jamfpro
 
-import "fmt"
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"net/url"
+)
 
 const uriLocalAdminPassword = "/api/v2/local-admin-password"
 
+// LAPS account user sources
+const (
+	LocalAdminPasswordUserSourceMDM = "MDM"
+	LocalAdminPasswordUserSourceJMF = "JMF"
+)
+
+// DefaultLocalAdminPasswordLength is the length of the passwords generated by RotateLocalAdminPasswordByClientManagementID.
+const DefaultLocalAdminPasswordLength = 24
+
+// localAdminPasswordCharacters are the characters of generated passwords, without look-alikes.
+const localAdminPasswordCharacters = "ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz23456789-_.!#%+="
+
+// List
+
+type ResponseLocalAdminPasswordAccountList struct {
+	TotalCount int                                 `json:"totalCount"`
+	Results    []ResourceLocalAdminPasswordAccount `json:"results"`
+}
+
+type ResponseLocalAdminPasswordAuditList struct {
+	TotalCount int                               `json:"totalCount"`
+	Results    []ResourceLocalAdminPasswordAudit `json:"results"`
+}
+
+type ResponseLocalAdminPasswordHistoryList struct {
+	TotalCount int                                 `json:"totalCount"`
+	Results    []ResourceLocalAdminPasswordHistory `json:"results"`
+}
+
+type ResponseLocalAdminPasswordPendingRotationList struct {
+	TotalCount int                                         `json:"totalCount"`
+	Results    []ResourceLocalAdminPasswordPendingRotation `json:"results"`
+}
+
+// Response
+
+type ResponseLocalAdminPassword struct {
+	Password string `json:"password"`
+}
+
+type ResponseLocalAdminPasswordSet struct {
+	LapsUserPasswordList []LocalAdminPasswordSubsetUserPassword `json:"lapsUserPasswordList"`
+}
+
+// Request
+
+type RequestLocalAdminPasswordSet struct {
+	LapsUserPasswordList []LocalAdminPasswordSubsetUserPassword `json:"lapsUserPasswordList"`
+}
+
 // Resource
 type ResourceLocalAdminPasswordSettings struct {
 	AutoDeployEnabled        bool `json:"autoDeployEnabled"`
@@ -17,6 +74,52 @@ type ResourceLocalAdminPasswordSettings struct {
 	AutoRotateExpirationTime int  `json:"autoRotateExpirationTime"`
 }
 
+// ResourceLocalAdminPasswordAccount is a LAPS capable account on a device.
+type ResourceLocalAdminPasswordAccount struct {
+	ClientManagementId string `json:"clientManagementId"`
+	Guid               string `json:"guid"`
+	Username           string `json:"username"`
+	UserSource         string `json:"userSource"`
+}
+
+// ResourceLocalAdminPasswordAudit is a password of an account, with who viewed it and when.
+type ResourceLocalAdminPasswordAudit struct {
+	Password       string                                `json:"password"`
+	DateLastSeen   string                                `json:"dateLastSeen"`
+	ExpirationTime string                                `json:"expirationTime"`
+	Audits         []LocalAdminPasswordSubsetAuditViewer `json:"audits"`
+}
+
+// ResourceLocalAdminPasswordHistory is an event in the password history of an account, e.g. a view or a
+// rotation.
+type ResourceLocalAdminPasswordHistory struct {
+	Password       string `json:"password"`
+	DateLastSeen   string `json:"dateLastSeen"`
+	ExpirationTime string `json:"expirationTime"`
+	EventType      string `json:"eventType"`
+	EventTime      string `json:"eventTime"`
+	ViewedBy       string `json:"viewedBy"`
+}
+
+type ResourceLocalAdminPasswordPendingRotation struct {
+	LapsUser    ResourceLocalAdminPasswordAccount `json:"lapsUser"`
+	CreatedDate string                            `json:"createdDate"`
+}
+
+// Subsets
+
+type LocalAdminPasswordSubsetAuditViewer struct {
+	ViewedBy string `json:"viewedBy"`
+	DateSeen string `json:"dateSeen"`
+}
+
+type LocalAdminPasswordSubsetUserPassword struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// CRUD
+
 // GetLocalAdminPasswordSettings retrieves current Jamf Pro LAPS settings
 func (c *Client) GetLocalAdminPasswordSettings() (*ResourceLocalAdminPasswordSettings, error) {
 	endpoint := uriLocalAdminPassword + "/settings"
@@ -50,3 +153,141 @@ func (c *Client) UpdateLocalAdminPasswordSettings(settings *ResourceLocalAdminPa
 
 	return nil
 }
+
+// GetLocalAdminPasswordPendingRotations retrieves the accounts whose password is waiting to be rotated.
+func (c *Client) GetLocalAdminPasswordPendingRotations() (*ResponseLocalAdminPasswordPendingRotationList, error) {
+	endpoint := uriLocalAdminPassword + "/pending-rotations"
+	var out ResponseLocalAdminPasswordPendingRotationList
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGet, "LAPS pending rotations", err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// GetLocalAdminPasswordAccountsByClientManagementID retrieves the LAPS capable accounts of a device.
+func (c *Client) GetLocalAdminPasswordAccountsByClientManagementID(clientManagementID string) (*ResponseLocalAdminPasswordAccountList, error) {
+	endpoint := fmt.Sprintf("%s/%s/accounts", uriLocalAdminPassword, clientManagementID)
+	var out ResponseLocalAdminPasswordAccountList
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByID, "LAPS accounts", clientManagementID, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// GetLocalAdminPasswordByClientManagementID retrieves the current password of an account. The view is audited.
+func (c *Client) GetLocalAdminPasswordByClientManagementID(clientManagementID string, username string) (*ResponseLocalAdminPassword, error) {
+	endpoint := fmt.Sprintf("%s/%s/account/%s/password", uriLocalAdminPassword, clientManagementID, url.PathEscape(username))
+	var out ResponseLocalAdminPassword
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByString, "LAPS password", "username", username, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// GetLocalAdminPasswordAuditByClientManagementID retrieves the passwords of an account with who viewed them.
+func (c *Client) GetLocalAdminPasswordAuditByClientManagementID(clientManagementID string, username string) (*ResponseLocalAdminPasswordAuditList, error) {
+	endpoint := fmt.Sprintf("%s/%s/account/%s/audit", uriLocalAdminPassword, clientManagementID, url.PathEscape(username))
+	var out ResponseLocalAdminPasswordAuditList
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByString, "LAPS audit", "username", username, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// GetLocalAdminPasswordHistoryByClientManagementID retrieves the password history of an account.
+func (c *Client) GetLocalAdminPasswordHistoryByClientManagementID(clientManagementID string, username string) (*ResponseLocalAdminPasswordHistoryList, error) {
+	endpoint := fmt.Sprintf("%s/%s/account/%s/history", uriLocalAdminPassword, clientManagementID, url.PathEscape(username))
+	var out ResponseLocalAdminPasswordHistoryList
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByString, "LAPS history", "username", username, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// SetLocalAdminPasswordsByClientManagementID sets the passwords of accounts on a device. Jamf Pro sends them
+// to the device with the next MDM check-in.
+func (c *Client) SetLocalAdminPasswordsByClientManagementID(clientManagementID string, passwords []LocalAdminPasswordSubsetUserPassword) (*ResponseLocalAdminPasswordSet, error) {
+	endpoint := fmt.Sprintf("%s/%s/set-password", uriLocalAdminPassword, clientManagementID)
+	request := RequestLocalAdminPasswordSet{LapsUserPasswordList: passwords}
+	var out ResponseLocalAdminPasswordSet
+	resp, err := c.HTTP.DoRequest("PUT", endpoint, &request, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedUpdateByID, "LAPS password", clientManagementID, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// RotateLocalAdminPasswordByClientManagementID replaces the password of an account with a random one of
+// DefaultLocalAdminPasswordLength characters. The new password can be read with
+// GetLocalAdminPasswordByClientManagementID.
+func (c *Client) RotateLocalAdminPasswordByClientManagementID(clientManagementID string, username string) error {
+	password, err := GenerateLocalAdminPassword(DefaultLocalAdminPasswordLength)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.SetLocalAdminPasswordsByClientManagementID(clientManagementID, []LocalAdminPasswordSubsetUserPassword{
+		{Username: username, Password: password},
+	})
+
+	return err
+}
+
+// GenerateLocalAdminPassword returns a random password of the given length.
+func GenerateLocalAdminPassword(length int) (string, error) {
+	return generateRandomPassword(localAdminPasswordCharacters, length)
+}
+
+// generateRandomPassword returns a password of the given length drawn uniformly from characters.
+func generateRandomPassword(characters string, length int) (string, error) {
+	if length <= 0 {
+		return "", fmt.Errorf("password length must be positive, got %d", length)
+	}
+
+	max := big.NewInt(int64(len(characters)))
+	password := make([]byte, length)
+	for i := range password {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate password, error: %v", err)
+		}
+		password[i] = characters[n.Int64()]
+	}
+
+	return string(password), nil
+}
@@ -2,17 +2,47 @@
 // Jamf Pro Api - Managed Software Updates (BETA)
 // api reference: https://developer.jamf.com/jamf-pro/reference/get_v1-managed-software-updates-available-updates
 // Jamf Pro API requires the structs to support a JSON data structure.
+// Plans are created per device; a group plan creates one plan for each member. The progress of a plan is in
+// its status, the progress of the update on the device in the update statuses reported by the device.
 
 package jamfpro
 
 import (
 	"fmt"
+	"net/http"
+	"net/url"
 
 	"github.com/mitchellh/mapstructure"
 )
 
 const uriManagedSoftwareUpdates = "/api/v1/managed-software-updates"
 
+// Managed software update group types
+const (
+	ManagedSoftwareUpdateGroupTypeComputer     = "COMPUTER_GROUP"
+	ManagedSoftwareUpdateGroupTypeMobileDevice = "MOBILE_DEVICE_GROUP"
+)
+
+// Managed software update plan states which end a plan
+const (
+	ManagedSoftwareUpdatePlanStateCompleted = "PlanCompleted"
+	ManagedSoftwareUpdatePlanStateFailed    = "PlanFailed"
+	ManagedSoftwareUpdatePlanStateException = "PlanException"
+	ManagedSoftwareUpdatePlanStateCanceled  = "PlanCanceled"
+	ManagedSoftwareUpdatePlanStateRejecting = "RejectingPlan"
+)
+
+// Managed software update statuses reported by devices
+const (
+	ManagedSoftwareUpdateStatusIdle        = "IDLE"
+	ManagedSoftwareUpdateStatusDownloading = "DOWNLOADING"
+	ManagedSoftwareUpdateStatusDownloaded  = "DOWNLOADED"
+	ManagedSoftwareUpdateStatusInstalling  = "INSTALLING"
+	ManagedSoftwareUpdateStatusInstalled   = "INSTALLED"
+	ManagedSoftwareUpdateStatusError       = "ERROR"
+	ManagedSoftwareUpdateStatusUnknown     = "UNKNOWN"
+)
+
 // Structs
 
 // List
@@ -49,6 +79,16 @@ type ManagedSoftwareUpdatePlanListSubsetStatus struct {
 	ErrorReasons []string `json:"errorReasons"`
 }
 
+type ResponseManagedSoftwareUpdatePlanDeclarationList struct {
+	TotalCount int                                            `json:"totalCount"`
+	Results    []ResourceManagedSoftwareUpdatePlanDeclaration `json:"results"`
+}
+
+type ResponseManagedSoftwareUpdateStatusList struct {
+	TotalCount int                                   `json:"totalCount"`
+	Results    []ResourceManagedSoftwareUpdateStatus `json:"results"`
+}
+
 // Response
 
 type ResponseManagedSoftwareUpdatePlanCreate struct {
@@ -104,6 +144,35 @@ type ManagedSoftwareUpdatePlanConfig struct {
 	ForceInstallLocalDateTime string `json:"forceInstallLocalDateTime,omitempty"`
 }
 
+// ResourceManagedSoftwareUpdatePlanDeclaration represents a declaration sent to a device for a DDM based plan.
+type ResourceManagedSoftwareUpdatePlanDeclaration struct {
+	Uuid        string `json:"uuid"`
+	PayloadJson string `json:"payloadJson"`
+	Type        string `json:"type"`
+	Group       string `json:"group"`
+}
+
+// ResourceManagedSoftwareUpdatePlanEvents holds the event log of a plan. Events is a JSON document of the
+// form {"events": [...]}, whose entries differ per event type.
+type ResourceManagedSoftwareUpdatePlanEvents struct {
+	Events string `json:"events"`
+}
+
+// ResourceManagedSoftwareUpdateStatus represents the software update status last reported by a device.
+type ResourceManagedSoftwareUpdateStatus struct {
+	OsUpdatesStatusId       string                                    `json:"osUpdatesStatusId"`
+	Device                  ManagedSoftwareUpdatePlanListSubsetDevice `json:"device"`
+	Downloaded              bool                                      `json:"downloaded"`
+	DownloadPercentComplete float64                                   `json:"downloadPercentComplete"`
+	ProductKey              string                                    `json:"productKey"`
+	Status                  string                                    `json:"status"`
+	DeferralsRemaining      int                                       `json:"deferralsRemaining"`
+	MaxDeferrals            int                                       `json:"maxDeferrals"`
+	NextScheduledInstall    string                                    `json:"nextScheduledInstall"`
+	Created                 string                                    `json:"created"`
+	Updated                 string                                    `json:"updated"`
+}
+
 // ResourceManagedSoftwareUpdateFeatureToggle represents the payload for updating the feature toggle.
 type ResourceManagedSoftwareUpdateFeatureToggle struct {
 	Toggle bool `json:"toggle"`
@@ -245,3 +314,157 @@ func (c *Client) GetManagedSoftwareUpdatePlansByGroupID(groupId string, groupTyp
 
 	return &responseManagedSoftwareUpdatePlanList, nil
 }
+
+// GetManagedSoftwareUpdatePlanByID retrieves a managed software update plan by its UUID.
+func (c *Client) GetManagedSoftwareUpdatePlanByID(id string) (*ResourceManagedSoftwareUpdatePlanList, error) {
+	endpoint := fmt.Sprintf("%s/plans/%s", uriManagedSoftwareUpdates, id)
+
+	var plan ResourceManagedSoftwareUpdatePlanList
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &plan)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByID, "managed software update plan", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &plan, nil
+}
+
+// GetManagedSoftwareUpdatePlanDeclarationsByID retrieves the declarations sent to the device of a DDM based plan.
+func (c *Client) GetManagedSoftwareUpdatePlanDeclarationsByID(id string) (*ResponseManagedSoftwareUpdatePlanDeclarationList, error) {
+	endpoint := fmt.Sprintf("%s/plans/%s/declarations", uriManagedSoftwareUpdates, id)
+
+	var declarations ResponseManagedSoftwareUpdatePlanDeclarationList
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &declarations)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByID, "managed software update plan declarations", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &declarations, nil
+}
+
+// GetManagedSoftwareUpdatePlanEventsByID retrieves the event log of a managed software update plan.
+func (c *Client) GetManagedSoftwareUpdatePlanEventsByID(id string) (*ResourceManagedSoftwareUpdatePlanEvents, error) {
+	endpoint := fmt.Sprintf("%s/plans/%s/events", uriManagedSoftwareUpdates, id)
+
+	var events ResourceManagedSoftwareUpdatePlanEvents
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &events)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByID, "managed software update plan events", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &events, nil
+}
+
+// CancelManagedSoftwareUpdatePlanByID cancels a managed software update plan which has not finished yet.
+// Requires a Jamf Pro version which supports cancelling plans.
+func (c *Client) CancelManagedSoftwareUpdatePlanByID(id string) error {
+	endpoint := fmt.Sprintf("%s/plans/%s/cancel", uriManagedSoftwareUpdates, id)
+
+	resp, err := c.HTTP.DoRequest("POST", endpoint, nil, nil)
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+	if err != nil && (resp == nil || resp.StatusCode != http.StatusNoContent) {
+		return fmt.Errorf("failed to cancel managed software update plan by id: %s, error: %v", id, err)
+	}
+
+	return nil
+}
+
+// ForceManagedSoftwareUpdatePlanByID replaces a plan with one which installs the same version on the same device
+// at forceInstallLocalDateTime, e.g. "2024-06-01T18:00:00", in the device's time zone. Jamf Pro supersedes the
+// existing plan of the device. Deferrals are no longer offered.
+func (c *Client) ForceManagedSoftwareUpdatePlanByID(id string, forceInstallLocalDateTime string) (*ResponseManagedSoftwareUpdatePlanCreate, error) {
+	plan, err := c.GetManagedSoftwareUpdatePlanByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	forcedPlan := &ResourceManagedSoftwareUpdatePlan{
+		Devices: []ManagedSoftwareUpdatePlanObject{
+			{
+				ObjectType: plan.Device.ObjectType,
+				DeviceId:   plan.Device.DeviceId,
+			},
+		},
+		Config: ManagedSoftwareUpdatePlanConfig{
+			UpdateAction:              "DOWNLOAD_INSTALL_SCHEDULE",
+			VersionType:               plan.VersionType,
+			SpecificVersion:           plan.SpecificVersion,
+			ForceInstallLocalDateTime: forceInstallLocalDateTime,
+		},
+	}
+
+	return c.CreateManagedSoftwareUpdatePlanByDeviceID(forcedPlan)
+}
+
+// GetManagedSoftwareUpdateStatuses retrieves the software update statuses reported by devices, optionally
+// limited by an RSQL filter, e.g. `device.deviceId=="1"`.
+func (c *Client) GetManagedSoftwareUpdateStatuses(filter string) (*ResponseManagedSoftwareUpdateStatusList, error) {
+	endpoint := uriManagedSoftwareUpdates + "/update-statuses"
+	if filter != "" {
+		endpoint += "?filter=" + url.QueryEscape(filter)
+	}
+
+	var statuses ResponseManagedSoftwareUpdateStatusList
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &statuses)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGet, "managed software update statuses", err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &statuses, nil
+}
+
+// GetManagedSoftwareUpdateStatusesByComputerID retrieves the software update statuses reported by a computer.
+func (c *Client) GetManagedSoftwareUpdateStatusesByComputerID(id string) (*ResponseManagedSoftwareUpdateStatusList, error) {
+	return c.getManagedSoftwareUpdateStatuses("computers", id)
+}
+
+// GetManagedSoftwareUpdateStatusesByMobileDeviceID retrieves the software update statuses reported by a mobile device.
+func (c *Client) GetManagedSoftwareUpdateStatusesByMobileDeviceID(id string) (*ResponseManagedSoftwareUpdateStatusList, error) {
+	return c.getManagedSoftwareUpdateStatuses("mobile-devices", id)
+}
+
+// GetManagedSoftwareUpdateStatusesByGroupID retrieves the software update statuses reported by the members of
+// a computer or mobile device group.
+func (c *Client) GetManagedSoftwareUpdateStatusesByGroupID(groupId string, groupType string) (*ResponseManagedSoftwareUpdateStatusList, error) {
+	switch groupType {
+	case ManagedSoftwareUpdateGroupTypeComputer:
+		return c.getManagedSoftwareUpdateStatuses("computer-groups", groupId)
+	case ManagedSoftwareUpdateGroupTypeMobileDevice:
+		return c.getManagedSoftwareUpdateStatuses("mobile-device-groups", groupId)
+	default:
+		return nil, fmt.Errorf("unsupported group type: %s", groupType)
+	}
+}
+
+func (c *Client) getManagedSoftwareUpdateStatuses(resource string, id string) (*ResponseManagedSoftwareUpdateStatusList, error) {
+	endpoint := fmt.Sprintf("%s/update-statuses/%s/%s", uriManagedSoftwareUpdates, resource, id)
+
+	var statuses ResponseManagedSoftwareUpdateStatusList
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &statuses)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByID, "managed software update statuses of "+resource, id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &statuses, nil
+}
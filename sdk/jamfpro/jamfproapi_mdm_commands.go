@@ -0,0 +1,379 @@
+// jamfproapi_mdm_commands.go
+// Jamf Pro Api - MDM Commands
+// api reference: https://developer.jamf.com/jamf-pro/reference/post_v2-mdm-commands
+// Jamf Pro API requires the structs to support a JSON data structure.
+// Commands are addressed by the management ID of the device, not its inventory ID. Use
+// GetMobileDeviceManagementIDByID or ResolveMDMCommandTargets to look management IDs up.
+
+package jamfpro
+
+import (
+	"fmt"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+const (
+	uriMDMCommands         = "/api/v2/mdm/commands"
+	uriMobileDevicesProAPI = "/api/v2/mobile-devices"
+)
+
+// MDM command types supported by the Jamf Pro API
+const (
+	MDMCommandTypeClearPasscode     = "CLEAR_PASSCODE"
+	MDMCommandTypeDeviceLock        = "DEVICE_LOCK"
+	MDMCommandTypeDisableLostMode   = "DISABLE_LOST_MODE"
+	MDMCommandTypeEnableLostMode    = "ENABLE_LOST_MODE"
+	MDMCommandTypeEraseDevice       = "ERASE_DEVICE"
+	MDMCommandTypePlayLostModeSound = "PLAY_LOST_MODE_SOUND"
+	MDMCommandTypeRestartDevice     = "RESTART_DEVICE"
+	MDMCommandTypeSetRecoveryLock   = "SET_RECOVERY_LOCK"
+	MDMCommandTypeSettings          = "SETTINGS"
+	MDMCommandTypeShutDownDevice    = "SHUT_DOWN_DEVICE"
+)
+
+// MDM command statuses reported by the Jamf Pro API
+const (
+	MDMCommandStatusPending      = "Pending"
+	MDMCommandStatusAcknowledged = "Acknowledged"
+	MDMCommandStatusNotNow       = "NotNow"
+	MDMCommandStatusError        = "Error"
+)
+
+// List
+
+// ResponseMDMCommandsList represents the paginated list of MDM commands.
+type ResponseMDMCommandsList struct {
+	TotalCount int                  `json:"totalCount"`
+	Results    []ResourceMDMCommand `json:"results"`
+}
+
+// Resource
+
+// ResourceMDMCommand represents an MDM command sent to a single client.
+type ResourceMDMCommand struct {
+	UUID              string                   `json:"uuid"`
+	Client            MDMCommandSubsetClient   `json:"client"`
+	CommandState      string                   `json:"commandState,omitempty"`
+	CommandType       string                   `json:"commandType"`
+	DateSent          string                   `json:"dateSent"`
+	DateCompleted     string                   `json:"dateCompleted,omitempty"`
+	ProfileID         int                      `json:"profileId,omitempty"`
+	ProfileIdentifier string                   `json:"profileIdentifier,omitempty"`
+	ErrorDetails      []MDMCommandSubsetDetail `json:"errorDetails,omitempty"`
+}
+
+// ResourceMDMCommandRequest represents the request body used to send an MDM command to one or more clients.
+type ResourceMDMCommandRequest struct {
+	ClientData  []MDMCommandSubsetClientData `json:"clientData"`
+	CommandData MDMCommandSubsetCommandData  `json:"commandData"`
+}
+
+// Subsets
+
+// MDMCommandSubsetClient identifies the client an MDM command was sent to.
+type MDMCommandSubsetClient struct {
+	ManagementID string `json:"managementId"`
+	ClientType   string `json:"clientType"`
+}
+
+// MDMCommandSubsetDetail describes an error reported by a client for an MDM command.
+type MDMCommandSubsetDetail struct {
+	Code        int    `json:"code,omitempty"`
+	Domain      string `json:"domain,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// MDMCommandSubsetClientData identifies a target client by its management ID.
+type MDMCommandSubsetClientData struct {
+	ManagementID string `json:"managementId"`
+}
+
+// MDMCommandSubsetCommandData holds the command type and its parameters. Only the parameters supported
+// by CommandType may be set, see Validate.
+type MDMCommandSubsetCommandData struct {
+	CommandType string `json:"commandType"`
+
+	// DEVICE_LOCK
+	Message     string `json:"message,omitempty"`
+	PhoneNumber string `json:"phoneNumber,omitempty"`
+	Pin         string `json:"pin,omitempty"`
+
+	// ENABLE_LOST_MODE
+	LostModeMessage  string `json:"lostModeMessage,omitempty"`
+	LostModePhone    string `json:"lostModePhone,omitempty"`
+	LostModeFootnote string `json:"lostModeFootnote,omitempty"`
+
+	// ERASE_DEVICE
+	PreserveDataPlan       *bool  `json:"preserveDataPlan,omitempty"`
+	DisallowProximitySetup *bool  `json:"disallowProximitySetup,omitempty"`
+	ObliterationBehavior   string `json:"obliterationBehavior,omitempty"`
+
+	// RESTART_DEVICE
+	NotifyUser *bool `json:"notifyUser,omitempty"`
+
+	// SET_RECOVERY_LOCK, an empty password clears the recovery lock
+	NewPassword *string `json:"newPassword,omitempty"`
+
+	// SETTINGS
+	Bluetooth       *bool  `json:"bluetooth,omitempty"`
+	DataRoaming     *bool  `json:"dataRoaming,omitempty"`
+	VoiceRoaming    *bool  `json:"voiceRoaming,omitempty"`
+	PersonalHotspot *bool  `json:"personalHotspot,omitempty"`
+	DeviceName      string `json:"deviceName,omitempty"`
+	TimeZone        string `json:"timeZone,omitempty"`
+}
+
+// Response
+
+// ResponseMDMCommandCreate represents a command created for a single client.
+type ResponseMDMCommandCreate struct {
+	ID   string `json:"id"`
+	Href string `json:"href"`
+}
+
+// ResponseMDMCommandsCreate represents the commands created by SendMDMCommand, one per client.
+type ResponseMDMCommandsCreate []ResponseMDMCommandCreate
+
+// UUIDs returns the command UUIDs created by Jamf Pro, in response order.
+func (r ResponseMDMCommandsCreate) UUIDs() []string {
+	uuids := make([]string, 0, len(r))
+	for _, command := range r {
+		if command.ID != "" {
+			uuids = append(uuids, command.ID)
+		}
+	}
+	return uuids
+}
+
+// MDMCommandTargets selects the clients a Jamf Pro API command is sent to. Mobile device IDs are
+// resolved to management IDs before the command is issued.
+type MDMCommandTargets struct {
+	ManagementIDs   []string
+	MobileDeviceIDs []string
+}
+
+// Builders
+
+// NewMDMCommandRequest returns a request which sends commandData to the given management IDs.
+func NewMDMCommandRequest(commandData MDMCommandSubsetCommandData, managementIDs ...string) *ResourceMDMCommandRequest {
+	request := &ResourceMDMCommandRequest{CommandData: commandData}
+	for _, managementID := range managementIDs {
+		request.ClientData = append(request.ClientData, MDMCommandSubsetClientData{ManagementID: managementID})
+	}
+	return request
+}
+
+// NewMDMDeviceLockCommand returns DEVICE_LOCK command data. message and phoneNumber are shown on the lock
+// screen; pin is only used by macOS clients and must be six digits when set.
+func NewMDMDeviceLockCommand(message, phoneNumber, pin string) MDMCommandSubsetCommandData {
+	return MDMCommandSubsetCommandData{CommandType: MDMCommandTypeDeviceLock, Message: message, PhoneNumber: phoneNumber, Pin: pin}
+}
+
+// NewMDMEnableLostModeCommand returns ENABLE_LOST_MODE command data. At least one of message and phone
+// is required.
+func NewMDMEnableLostModeCommand(message, phone, footnote string) MDMCommandSubsetCommandData {
+	return MDMCommandSubsetCommandData{CommandType: MDMCommandTypeEnableLostMode, LostModeMessage: message, LostModePhone: phone, LostModeFootnote: footnote}
+}
+
+// NewMDMEraseDeviceCommand returns ERASE_DEVICE command data. preserveDataPlan keeps the cellular data plan
+// on devices with eSIM, disallowProximitySetup prevents Quick Start after the wipe.
+func NewMDMEraseDeviceCommand(preserveDataPlan, disallowProximitySetup bool) MDMCommandSubsetCommandData {
+	return MDMCommandSubsetCommandData{
+		CommandType:            MDMCommandTypeEraseDevice,
+		PreserveDataPlan:       &preserveDataPlan,
+		DisallowProximitySetup: &disallowProximitySetup,
+	}
+}
+
+// NewMDMRestartDeviceCommand returns RESTART_DEVICE command data.
+func NewMDMRestartDeviceCommand(notifyUser bool) MDMCommandSubsetCommandData {
+	return MDMCommandSubsetCommandData{CommandType: MDMCommandTypeRestartDevice, NotifyUser: &notifyUser}
+}
+
+// NewMDMSetRecoveryLockCommand returns SET_RECOVERY_LOCK command data. An empty password clears the recovery
+// lock of the computer.
+func NewMDMSetRecoveryLockCommand(password string) MDMCommandSubsetCommandData {
+	return MDMCommandSubsetCommandData{CommandType: MDMCommandTypeSetRecoveryLock, NewPassword: &password}
+}
+
+// NewMDMBluetoothSettingsCommand returns SETTINGS command data which enables or disables Bluetooth.
+func NewMDMBluetoothSettingsCommand(enabled bool) MDMCommandSubsetCommandData {
+	return MDMCommandSubsetCommandData{CommandType: MDMCommandTypeSettings, Bluetooth: &enabled}
+}
+
+// NewMDMCommand returns command data without parameters, e.g. MDMCommandTypeClearPasscode,
+// MDMCommandTypeShutDownDevice, MDMCommandTypePlayLostModeSound or MDMCommandTypeDisableLostMode.
+func NewMDMCommand(commandType string) MDMCommandSubsetCommandData {
+	return MDMCommandSubsetCommandData{CommandType: commandType}
+}
+
+// Validate checks that the command type is supported and that only its own parameters are set.
+func (d MDMCommandSubsetCommandData) Validate() error {
+	lockSet := d.Message != "" || d.PhoneNumber != ""
+	lostModeSet := d.LostModeMessage != "" || d.LostModePhone != "" || d.LostModeFootnote != ""
+	eraseSet := d.PreserveDataPlan != nil || d.DisallowProximitySetup != nil || d.ObliterationBehavior != ""
+	settingsSet := d.Bluetooth != nil || d.DataRoaming != nil || d.VoiceRoaming != nil || d.PersonalHotspot != nil ||
+		d.DeviceName != "" || d.TimeZone != ""
+
+	switch d.CommandType {
+	case "":
+		return fmt.Errorf("MDM command type is required")
+	case MDMCommandTypeDeviceLock, MDMCommandTypeEraseDevice:
+		if d.Pin != "" && !computerCommandPasscodePattern.MatchString(d.Pin) {
+			return fmt.Errorf("%s pin must be six digits", d.CommandType)
+		}
+	case MDMCommandTypeEnableLostMode:
+		if d.LostModeMessage == "" && d.LostModePhone == "" {
+			return fmt.Errorf("%s requires a message or a phone number", d.CommandType)
+		}
+	case MDMCommandTypeSettings:
+		if !settingsSet {
+			return fmt.Errorf("%s requires at least one setting", d.CommandType)
+		}
+	case MDMCommandTypeSetRecoveryLock:
+		if d.NewPassword == nil {
+			return fmt.Errorf("%s requires a new password, empty to clear the recovery lock", d.CommandType)
+		}
+	case MDMCommandTypeClearPasscode, MDMCommandTypeDisableLostMode, MDMCommandTypePlayLostModeSound,
+		MDMCommandTypeRestartDevice, MDMCommandTypeShutDownDevice:
+	default:
+		return fmt.Errorf("unsupported MDM command type: %s", d.CommandType)
+	}
+
+	switch {
+	case lockSet && d.CommandType != MDMCommandTypeDeviceLock:
+		return fmt.Errorf("lock options are only supported by %s", MDMCommandTypeDeviceLock)
+	case d.Pin != "" && d.CommandType != MDMCommandTypeDeviceLock && d.CommandType != MDMCommandTypeEraseDevice:
+		return fmt.Errorf("pin is only supported by %s and %s", MDMCommandTypeDeviceLock, MDMCommandTypeEraseDevice)
+	case lostModeSet && d.CommandType != MDMCommandTypeEnableLostMode:
+		return fmt.Errorf("lost mode options are only supported by %s", MDMCommandTypeEnableLostMode)
+	case eraseSet && d.CommandType != MDMCommandTypeEraseDevice:
+		return fmt.Errorf("erase options are only supported by %s", MDMCommandTypeEraseDevice)
+	case d.NotifyUser != nil && d.CommandType != MDMCommandTypeRestartDevice:
+		return fmt.Errorf("notify user is only supported by %s", MDMCommandTypeRestartDevice)
+	case settingsSet && d.CommandType != MDMCommandTypeSettings:
+		return fmt.Errorf("settings are only supported by %s", MDMCommandTypeSettings)
+	case d.NewPassword != nil && d.CommandType != MDMCommandTypeSetRecoveryLock:
+		return fmt.Errorf("new password is only supported by %s", MDMCommandTypeSetRecoveryLock)
+	}
+
+	return nil
+}
+
+// CRUD
+
+// GetMDMCommands retrieves MDM commands, optionally filtered with an RSQL filter such as
+// "&filter=uuid==\"<uuid>\"" or "&filter=clientManagementId==\"<id>\"".
+func (c *Client) GetMDMCommands(sort_filter string) (*ResponseMDMCommandsList, error) {
+	endpoint := uriMDMCommands
+
+	resp, err := c.DoPaginatedGet(endpoint, standardPageSize, startingPageNumber, sort_filter)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedPaginatedGet, "mdm commands", err)
+	}
+
+	var outStruct ResponseMDMCommandsList
+	outStruct.TotalCount = resp.Size
+	for _, value := range resp.Results {
+		var newObj ResourceMDMCommand
+		err := mapstructure.Decode(value, &newObj)
+		if err != nil {
+			return nil, fmt.Errorf(errMsgFailedMapstruct, "mdm command", err)
+		}
+		outStruct.Results = append(outStruct.Results, newObj)
+	}
+
+	return &outStruct, nil
+}
+
+// SendMDMCommand validates the command data and sends it to every client in the request. One command
+// is created per client.
+func (c *Client) SendMDMCommand(request *ResourceMDMCommandRequest) (ResponseMDMCommandsCreate, error) {
+	if err := request.CommandData.Validate(); err != nil {
+		return nil, fmt.Errorf(errMsgFailedCreate, "mdm command", err)
+	}
+
+	if len(request.ClientData) == 0 {
+		return nil, fmt.Errorf(errMsgFailedCreate, "mdm command", fmt.Errorf("no target clients specified"))
+	}
+
+	endpoint := uriMDMCommands
+
+	var response ResponseMDMCommandsCreate
+	resp, err := c.HTTP.DoRequest("POST", endpoint, request, &response)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedCreate, "mdm command", err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return response, nil
+}
+
+// SendMDMCommandToTargets resolves targets to management IDs and sends commandData to each of them.
+func (c *Client) SendMDMCommandToTargets(commandData MDMCommandSubsetCommandData, targets MDMCommandTargets) (ResponseMDMCommandsCreate, error) {
+	managementIDs, err := c.ResolveMDMCommandTargets(targets)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedCreate, "mdm command", err)
+	}
+
+	return c.SendMDMCommand(NewMDMCommandRequest(commandData, managementIDs...))
+}
+
+// ResolveMDMCommandTargets returns the de-duplicated management IDs selected by targets.
+func (c *Client) ResolveMDMCommandTargets(targets MDMCommandTargets) ([]string, error) {
+	seen := map[string]bool{}
+	var managementIDs []string
+
+	addID := func(id string) {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			managementIDs = append(managementIDs, id)
+		}
+	}
+
+	for _, id := range targets.ManagementIDs {
+		addID(id)
+	}
+
+	for _, deviceID := range targets.MobileDeviceIDs {
+		managementID, err := c.GetMobileDeviceManagementIDByID(deviceID)
+		if err != nil {
+			return nil, err
+		}
+		addID(managementID)
+	}
+
+	if len(managementIDs) == 0 {
+		return nil, fmt.Errorf("no target clients specified")
+	}
+
+	return managementIDs, nil
+}
+
+// GetMobileDeviceManagementIDByID returns the MDM management ID of a mobile device by its inventory ID.
+func (c *Client) GetMobileDeviceManagementIDByID(id string) (string, error) {
+	endpoint := fmt.Sprintf("%s/%s", uriMobileDevicesProAPI, id)
+
+	var device struct {
+		ID           string `json:"id"`
+		ManagementID string `json:"managementId"`
+	}
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &device)
+	if err != nil {
+		return "", fmt.Errorf(errMsgFailedGetByID, "mobile device management id", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	if device.ManagementID == "" {
+		return "", fmt.Errorf("mobile device %s has no management id", id)
+	}
+
+	return device.ManagementID, nil
+}
@@ -2,8 +2,441 @@
 // Jamf Pro Api - Mobile Device Groups
 // api reference: https://developer.jamf.com/jamf-pro/reference/get_v1-mobile-device-groups
 // Jamf Pro API requires the structs to support a JSON data structure.
+// Static group membership is changed with PATCH requests which add or remove the listed devices only, so
+// concurrent changes by other admins are not overwritten as they are by the Classic API full replace.
 
 package jamfpro
 
-// NOTE - This will be completed once jamf fully round off this endpoint. presently only covers static
-// account groups and doesn't smart groups.
+import (
+	"fmt"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+const (
+	uriMobileDeviceGroupsV1                = "/api/v1/mobile-device-groups"
+	uriMobileDeviceStaticGroups            = "/api/v1/mobile-device-groups/static-groups"
+	uriMobileDeviceStaticGroupMembership   = "/api/v1/mobile-device-groups/static-group-membership"
+	uriMobileDeviceSmartGroups             = "/api/v1/mobile-device-groups/smart-groups"
+	uriMobileDeviceSmartGroupMembership    = "/api/v1/mobile-device-groups/smart-group-membership"
+	mobileDeviceStaticGroupAssignmentBatch = 500
+)
+
+// List
+
+// ResponseMobileDeviceGroupsListV1 represents the list of all static and smart mobile device groups.
+type ResponseMobileDeviceGroupsListV1 []MobileDeviceGroupsListItemV1
+
+type MobileDeviceGroupsListItemV1 struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	IsSmartGroup bool   `json:"isSmartGroup"`
+}
+
+// ResponseMobileDeviceStaticGroupsList represents the paginated list of static mobile device groups.
+type ResponseMobileDeviceStaticGroupsList struct {
+	TotalCount int                               `json:"totalCount"`
+	Results    []ResourceMobileDeviceStaticGroup `json:"results"`
+}
+
+// ResponseMobileDeviceSmartGroupsList represents the paginated list of smart mobile device groups.
+type ResponseMobileDeviceSmartGroupsList struct {
+	TotalCount int                              `json:"totalCount"`
+	Results    []ResourceMobileDeviceSmartGroup `json:"results"`
+}
+
+// ResponseMobileDeviceGroupMembership represents the members of a static or smart mobile device group.
+type ResponseMobileDeviceGroupMembership struct {
+	TotalCount int                             `json:"totalCount"`
+	Results    []MobileDeviceGroupSubsetMember `json:"results"`
+}
+
+// MobileDeviceGroupSubsetMember is a group member. Jamf Pro returns the inventory record of each member;
+// the identifying fields are decoded.
+type MobileDeviceGroupSubsetMember struct {
+	MobileDeviceID string `json:"mobileDeviceId"`
+	UDID           string `json:"udid"`
+	SerialNumber   string `json:"serialNumber"`
+	DisplayName    string `json:"displayName"`
+}
+
+// Responses
+
+// ResponseMobileDeviceGroupCreate represents the response for creating a static or smart mobile device group.
+type ResponseMobileDeviceGroupCreate struct {
+	ID   string `json:"id"`
+	Href string `json:"href"`
+}
+
+// Resource
+
+// ResourceMobileDeviceStaticGroup represents a static mobile device group. Assignments is only sent: each
+// assignment adds (Selected true) or removes (Selected false) a mobile device.
+type ResourceMobileDeviceStaticGroup struct {
+	GroupID          string                                    `json:"groupId,omitempty"`
+	GroupName        string                                    `json:"groupName,omitempty"`
+	GroupDescription string                                    `json:"groupDescription,omitempty"`
+	SiteID           string                                    `json:"siteId,omitempty"`
+	Count            int                                       `json:"count,omitempty"`
+	Assignments      []MobileDeviceStaticGroupSubsetAssignment `json:"assignments,omitempty"`
+}
+
+type MobileDeviceStaticGroupSubsetAssignment struct {
+	MobileDeviceID string `json:"mobileDeviceId"`
+	Selected       bool   `json:"selected"`
+}
+
+// ResourceMobileDeviceSmartGroup represents a smart mobile device group.
+type ResourceMobileDeviceSmartGroup struct {
+	GroupID          string                                 `json:"groupId,omitempty"`
+	GroupName        string                                 `json:"groupName"`
+	GroupDescription string                                 `json:"groupDescription,omitempty"`
+	Criteria         []MobileDeviceSmartGroupSubsetCriteria `json:"criteria"`
+	SiteID           string                                 `json:"siteId,omitempty"`
+	Count            int                                    `json:"count,omitempty"`
+}
+
+// MobileDeviceSmartGroupSubsetCriteria is a smart group criterion, e.g. Name "Model", SearchType "like",
+// Value "iPad". AndOr joins the criterion to the previous one.
+type MobileDeviceSmartGroupSubsetCriteria struct {
+	Name         string `json:"name"`
+	Priority     int    `json:"priority"`
+	AndOr        string `json:"andOr"`
+	SearchType   string `json:"searchType"`
+	Value        string `json:"value"`
+	OpeningParen bool   `json:"openingParen"`
+	ClosingParen bool   `json:"closingParen"`
+}
+
+// CRUD
+
+// GetMobileDeviceGroupsV1 retrieves all static and smart mobile device groups.
+func (c *Client) GetMobileDeviceGroupsV1() (*ResponseMobileDeviceGroupsListV1, error) {
+	endpoint := uriMobileDeviceGroupsV1
+	var out ResponseMobileDeviceGroupsListV1
+
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGet, "mobile device groups", err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// Static groups
+
+// GetMobileDeviceStaticGroups retrieves all static mobile device groups with optional sorting and filtering.
+func (c *Client) GetMobileDeviceStaticGroups(sort_filter string) (*ResponseMobileDeviceStaticGroupsList, error) {
+	resp, err := c.DoPaginatedGet(
+		uriMobileDeviceStaticGroups,
+		standardPageSize,
+		startingPageNumber,
+		sort_filter,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedPaginatedGet, "mobile device static groups", err)
+	}
+
+	var out ResponseMobileDeviceStaticGroupsList
+	out.TotalCount = resp.Size
+
+	for _, value := range resp.Results {
+		var newObj ResourceMobileDeviceStaticGroup
+		err := mapstructure.Decode(value, &newObj)
+		if err != nil {
+			return nil, fmt.Errorf(errMsgFailedMapstruct, "mobile device static group", err)
+		}
+		out.Results = append(out.Results, newObj)
+	}
+
+	return &out, nil
+}
+
+// GetMobileDeviceStaticGroupByID retrieves a static mobile device group by ID.
+func (c *Client) GetMobileDeviceStaticGroupByID(id string) (*ResourceMobileDeviceStaticGroup, error) {
+	endpoint := fmt.Sprintf("%s/%s", uriMobileDeviceStaticGroups, id)
+	var out ResourceMobileDeviceStaticGroup
+
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByID, "mobile device static group", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// GetMobileDeviceStaticGroupByName retrieves a static mobile device group by name.
+func (c *Client) GetMobileDeviceStaticGroupByName(name string) (*ResourceMobileDeviceStaticGroup, error) {
+	groups, err := c.GetMobileDeviceStaticGroups("")
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedPaginatedGet, "mobile device static groups", err)
+	}
+
+	for _, value := range groups.Results {
+		if value.GroupName == name {
+			return &value, nil
+		}
+	}
+
+	return nil, fmt.Errorf(errMsgFailedGetByName, "mobile device static group", name, errMsgNoName)
+}
+
+// GetMobileDeviceStaticGroupMembershipByID retrieves the members of a static mobile device group.
+func (c *Client) GetMobileDeviceStaticGroupMembershipByID(id string, sort_filter string) (*ResponseMobileDeviceGroupMembership, error) {
+	endpoint := fmt.Sprintf("%s/%s", uriMobileDeviceStaticGroupMembership, id)
+
+	out, err := c.getMobileDeviceGroupMembership(endpoint, sort_filter)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByID, "mobile device static group membership", id, err)
+	}
+
+	return out, nil
+}
+
+// CreateMobileDeviceStaticGroup creates a static mobile device group, with its initial members as assignments.
+func (c *Client) CreateMobileDeviceStaticGroup(group *ResourceMobileDeviceStaticGroup) (*ResponseMobileDeviceGroupCreate, error) {
+	endpoint := uriMobileDeviceStaticGroups
+	var out ResponseMobileDeviceGroupCreate
+
+	resp, err := c.HTTP.DoRequest("POST", endpoint, group, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedCreate, "mobile device static group", err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// UpdateMobileDeviceStaticGroupByID updates a static mobile device group. Only the fields which are set are
+// changed, and only the devices listed in Assignments are added or removed.
+func (c *Client) UpdateMobileDeviceStaticGroupByID(id string, groupUpdate *ResourceMobileDeviceStaticGroup) (*ResourceMobileDeviceStaticGroup, error) {
+	endpoint := fmt.Sprintf("%s/%s", uriMobileDeviceStaticGroups, id)
+	var out ResourceMobileDeviceStaticGroup
+
+	resp, err := c.HTTP.DoRequest("PATCH", endpoint, groupUpdate, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedUpdateByID, "mobile device static group", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// AddMobileDevicesToStaticGroup adds mobile devices to a static group without affecting other members.
+// Large lists are sent in batches.
+func (c *Client) AddMobileDevicesToStaticGroup(id string, mobileDeviceIDs []string) error {
+	return c.assignMobileDevicesToStaticGroup(id, mobileDeviceIDs, true)
+}
+
+// RemoveMobileDevicesFromStaticGroup removes mobile devices from a static group without affecting other
+// members. Large lists are sent in batches.
+func (c *Client) RemoveMobileDevicesFromStaticGroup(id string, mobileDeviceIDs []string) error {
+	return c.assignMobileDevicesToStaticGroup(id, mobileDeviceIDs, false)
+}
+
+// assignMobileDevicesToStaticGroup sends membership changes in batches. Batches already sent are not
+// rolled back when a later one fails; the error reports how many devices were processed.
+func (c *Client) assignMobileDevicesToStaticGroup(id string, mobileDeviceIDs []string, selected bool) error {
+	for start := 0; start < len(mobileDeviceIDs); start += mobileDeviceStaticGroupAssignmentBatch {
+		end := start + mobileDeviceStaticGroupAssignmentBatch
+		if end > len(mobileDeviceIDs) {
+			end = len(mobileDeviceIDs)
+		}
+
+		update := &ResourceMobileDeviceStaticGroup{}
+		for _, mobileDeviceID := range mobileDeviceIDs[start:end] {
+			update.Assignments = append(update.Assignments, MobileDeviceStaticGroupSubsetAssignment{
+				MobileDeviceID: mobileDeviceID,
+				Selected:       selected,
+			})
+		}
+
+		if _, err := c.UpdateMobileDeviceStaticGroupByID(id, update); err != nil {
+			return fmt.Errorf("failed to update membership after %d of %d mobile devices: %v", start, len(mobileDeviceIDs), err)
+		}
+	}
+
+	return nil
+}
+
+// DeleteMobileDeviceStaticGroupByID deletes a static mobile device group by ID.
+func (c *Client) DeleteMobileDeviceStaticGroupByID(id string) error {
+	endpoint := fmt.Sprintf("%s/%s", uriMobileDeviceStaticGroups, id)
+
+	resp, err := c.HTTP.DoRequest("DELETE", endpoint, nil, nil)
+	if err != nil {
+		return fmt.Errorf(errMsgFailedDeleteByID, "mobile device static group", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return nil
+}
+
+// Smart groups
+
+// GetMobileDeviceSmartGroups retrieves all smart mobile device groups with optional sorting and filtering.
+func (c *Client) GetMobileDeviceSmartGroups(sort_filter string) (*ResponseMobileDeviceSmartGroupsList, error) {
+	resp, err := c.DoPaginatedGet(
+		uriMobileDeviceSmartGroups,
+		standardPageSize,
+		startingPageNumber,
+		sort_filter,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedPaginatedGet, "mobile device smart groups", err)
+	}
+
+	var out ResponseMobileDeviceSmartGroupsList
+	out.TotalCount = resp.Size
+
+	for _, value := range resp.Results {
+		var newObj ResourceMobileDeviceSmartGroup
+		err := mapstructure.Decode(value, &newObj)
+		if err != nil {
+			return nil, fmt.Errorf(errMsgFailedMapstruct, "mobile device smart group", err)
+		}
+		out.Results = append(out.Results, newObj)
+	}
+
+	return &out, nil
+}
+
+// GetMobileDeviceSmartGroupByID retrieves a smart mobile device group, including its criteria, by ID.
+func (c *Client) GetMobileDeviceSmartGroupByID(id string) (*ResourceMobileDeviceSmartGroup, error) {
+	endpoint := fmt.Sprintf("%s/%s", uriMobileDeviceSmartGroups, id)
+	var out ResourceMobileDeviceSmartGroup
+
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByID, "mobile device smart group", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// GetMobileDeviceSmartGroupByName retrieves a smart mobile device group by name.
+func (c *Client) GetMobileDeviceSmartGroupByName(name string) (*ResourceMobileDeviceSmartGroup, error) {
+	groups, err := c.GetMobileDeviceSmartGroups("")
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedPaginatedGet, "mobile device smart groups", err)
+	}
+
+	for _, value := range groups.Results {
+		if value.GroupName == name {
+			return &value, nil
+		}
+	}
+
+	return nil, fmt.Errorf(errMsgFailedGetByName, "mobile device smart group", name, errMsgNoName)
+}
+
+// GetMobileDeviceSmartGroupMembershipByID retrieves the current members of a smart mobile device group.
+func (c *Client) GetMobileDeviceSmartGroupMembershipByID(id string, sort_filter string) (*ResponseMobileDeviceGroupMembership, error) {
+	endpoint := fmt.Sprintf("%s/%s", uriMobileDeviceSmartGroupMembership, id)
+
+	out, err := c.getMobileDeviceGroupMembership(endpoint, sort_filter)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByID, "mobile device smart group membership", id, err)
+	}
+
+	return out, nil
+}
+
+// CreateMobileDeviceSmartGroup creates a smart mobile device group.
+func (c *Client) CreateMobileDeviceSmartGroup(group *ResourceMobileDeviceSmartGroup) (*ResponseMobileDeviceGroupCreate, error) {
+	endpoint := uriMobileDeviceSmartGroups
+	var out ResponseMobileDeviceGroupCreate
+
+	resp, err := c.HTTP.DoRequest("POST", endpoint, group, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedCreate, "mobile device smart group", err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// UpdateMobileDeviceSmartGroupByID replaces the name, description, site and criteria of a smart mobile
+// device group.
+func (c *Client) UpdateMobileDeviceSmartGroupByID(id string, groupUpdate *ResourceMobileDeviceSmartGroup) (*ResourceMobileDeviceSmartGroup, error) {
+	endpoint := fmt.Sprintf("%s/%s", uriMobileDeviceSmartGroups, id)
+	var out ResourceMobileDeviceSmartGroup
+
+	resp, err := c.HTTP.DoRequest("PUT", endpoint, groupUpdate, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedUpdateByID, "mobile device smart group", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// DeleteMobileDeviceSmartGroupByID deletes a smart mobile device group by ID.
+func (c *Client) DeleteMobileDeviceSmartGroupByID(id string) error {
+	endpoint := fmt.Sprintf("%s/%s", uriMobileDeviceSmartGroups, id)
+
+	resp, err := c.HTTP.DoRequest("DELETE", endpoint, nil, nil)
+	if err != nil {
+		return fmt.Errorf(errMsgFailedDeleteByID, "mobile device smart group", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return nil
+}
+
+// getMobileDeviceGroupMembership retrieves every page of a group membership endpoint.
+func (c *Client) getMobileDeviceGroupMembership(endpoint string, sort_filter string) (*ResponseMobileDeviceGroupMembership, error) {
+	resp, err := c.DoPaginatedGet(
+		endpoint,
+		standardPageSize,
+		startingPageNumber,
+		sort_filter,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var out ResponseMobileDeviceGroupMembership
+	out.TotalCount = resp.Size
+
+	for _, value := range resp.Results {
+		var newObj MobileDeviceGroupSubsetMember
+		err := mapstructure.Decode(value, &newObj)
+		if err != nil {
+			return nil, fmt.Errorf(errMsgFailedMapstruct, "mobile device group member", err)
+		}
+		out.Results = append(out.Results, newObj)
+	}
+
+	return &out, nil
+}
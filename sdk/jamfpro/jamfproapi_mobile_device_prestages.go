@@ -7,12 +7,17 @@ package jamfpro
 
 import (
 	"fmt"
+	"net/http"
 
 	"github.com/mitchellh/mapstructure"
 )
 
 const uriMobileDevicePrestages = "/api/v2/mobile-device-prestages"
 
+// maxMobileDevicePrestageUpdateAttempts is how often ModifyMobileDevicePrestageByID attempts an update before
+// a conflict is returned.
+const maxMobileDevicePrestageUpdateAttempts = 5
+
 // Structs
 
 // List
@@ -29,51 +34,70 @@ type ResponseMobileDevicePrestageCreate struct {
 	Href string `json:"href"`
 }
 
+// ResponseMobileDevicePrestageScopeAssignments represents the prestage assignment of every scoped serial number.
+type ResponseMobileDevicePrestageScopeAssignments struct {
+	SerialsByPrestageId map[string]string `json:"serialsByPrestageId"`
+}
+
+// ResponseMobileDevicePrestageSyncState represents the state of a prestage sync with Apple Business Manager.
+type ResponseMobileDevicePrestageSyncState struct {
+	SyncState  string `json:"syncState"`
+	PrestageId string `json:"prestageId"`
+	Timestamp  string `json:"timestamp"`
+}
+
+// ResponseMobileDevicePrestageAttachment represents a file attached to a mobile device prestage.
+type ResponseMobileDevicePrestageAttachment struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	FileType string `json:"fileType"`
+}
+
 // Resource
 
 type ResourceMobileDevicePrestage struct {
-	DisplayName                         string                                        `json:"displayName"`
-	Mandatory                           bool                                          `json:"mandatory"`
-	MdmRemovable                        bool                                          `json:"mdmRemovable"`
-	SupportPhoneNumber                  string                                        `json:"supportPhoneNumber"`
-	SupportEmailAddress                 string                                        `json:"supportEmailAddress"`
-	Department                          string                                        `json:"department"`
-	DefaultPrestage                     bool                                          `json:"defaultPrestage"`
-	EnrollmentSiteID                    string                                        `json:"enrollmentSiteId"`
-	KeepExistingSiteMembership          bool                                          `json:"keepExistingSiteMembership"`
-	KeepExistingLocationInformation     bool                                          `json:"keepExistingLocationInformation"`
-	RequireAuthentication               bool                                          `json:"requireAuthentication"`
-	AuthenticationPrompt                string                                        `json:"authenticationPrompt"`
-	PreventActivationLock               bool                                          `json:"preventActivationLock"`
-	EnableDeviceBasedActivationLock     bool                                          `json:"enableDeviceBasedActivationLock"`
-	DeviceEnrollmentProgramInstanceID   string                                        `json:"deviceEnrollmentProgramInstanceId"`
-	SkipSetupItems                      MobileDevicePrestageSubsetSkipSetupItems      `json:"skipSetupItems"`
-	LocationInformation                 MobileDevicePrestageSubsetLocationInformation `json:"locationInformation"`
-	PurchasingInformation               MobileDevicePrestageSubsetLocationInformation `json:"purchasingInformation"`
-	AnchorCertificates                  []string                                      `json:"anchorCertificates"`
-	EnrollmentCustomizationID           string                                        `json:"enrollmentCustomizationId"`
-	Language                            string                                        `json:"language"`
-	Region                              string                                        `json:"region"`
-	AutoAdvanceSetup                    bool                                          `json:"autoAdvanceSetup"`
-	AllowPairing                        bool                                          `json:"allowPairing"`
-	MultiUser                           bool                                          `json:"multiUser"`
-	Supervised                          bool                                          `json:"supervised"`
-	MaximumSharedAccounts               int                                           `json:"maximumSharedAccounts"`
-	ConfigureDeviceBeforeSetupAssistant bool                                          `json:"configureDeviceBeforeSetupAssistant"`
-	Names                               MobileDevicePrestageSubsetNames               `json:"names"`
-	SendTimezone                        bool                                          `json:"sendTimezone"`
-	Timezone                            string                                        `json:"timezone"`
-	StorageQuotaSizeMegabytes           int                                           `json:"storageQuotaSizeMegabytes"`
-	UseStorageQuotaSize                 bool                                          `json:"useStorageQuotaSize"`
-	TemporarySessionOnly                bool                                          `json:"temporarySessionOnly"`
-	EnforceTemporarySessionTimeout      bool                                          `json:"enforceTemporarySessionTimeout"`
-	TemporarySessionTimeout             int                                           `json:"temporarySessionTimeout"`
-	EnforceUserSessionTimeout           bool                                          `json:"enforceUserSessionTimeout"`
-	UserSessionTimeout                  int                                           `json:"userSessionTimeout"`
-	ID                                  string                                        `json:"id"`
-	ProfileUuid                         string                                        `json:"profileUuid"`
-	SiteId                              string                                        `json:"siteId"`
-	VersionLock                         int                                           `json:"versionLock"`
+	DisplayName                         string                                          `json:"displayName"`
+	Mandatory                           bool                                            `json:"mandatory"`
+	MdmRemovable                        bool                                            `json:"mdmRemovable"`
+	SupportPhoneNumber                  string                                          `json:"supportPhoneNumber"`
+	SupportEmailAddress                 string                                          `json:"supportEmailAddress"`
+	Department                          string                                          `json:"department"`
+	DefaultPrestage                     bool                                            `json:"defaultPrestage"`
+	EnrollmentSiteID                    string                                          `json:"enrollmentSiteId"`
+	KeepExistingSiteMembership          bool                                            `json:"keepExistingSiteMembership"`
+	KeepExistingLocationInformation     bool                                            `json:"keepExistingLocationInformation"`
+	RequireAuthentication               bool                                            `json:"requireAuthentication"`
+	AuthenticationPrompt                string                                          `json:"authenticationPrompt"`
+	PreventActivationLock               bool                                            `json:"preventActivationLock"`
+	EnableDeviceBasedActivationLock     bool                                            `json:"enableDeviceBasedActivationLock"`
+	DeviceEnrollmentProgramInstanceID   string                                          `json:"deviceEnrollmentProgramInstanceId"`
+	SkipSetupItems                      MobileDevicePrestageSubsetSkipSetupItems        `json:"skipSetupItems"`
+	LocationInformation                 MobileDevicePrestageSubsetLocationInformation   `json:"locationInformation"`
+	PurchasingInformation               MobileDevicePrestageSubsetPurchasingInformation `json:"purchasingInformation"`
+	AnchorCertificates                  []string                                        `json:"anchorCertificates"`
+	EnrollmentCustomizationID           string                                          `json:"enrollmentCustomizationId"`
+	Language                            string                                          `json:"language"`
+	Region                              string                                          `json:"region"`
+	AutoAdvanceSetup                    bool                                            `json:"autoAdvanceSetup"`
+	AllowPairing                        bool                                            `json:"allowPairing"`
+	MultiUser                           bool                                            `json:"multiUser"`
+	Supervised                          bool                                            `json:"supervised"`
+	MaximumSharedAccounts               int                                             `json:"maximumSharedAccounts"`
+	ConfigureDeviceBeforeSetupAssistant bool                                            `json:"configureDeviceBeforeSetupAssistant"`
+	Names                               MobileDevicePrestageSubsetNames                 `json:"names"`
+	SendTimezone                        bool                                            `json:"sendTimezone"`
+	Timezone                            string                                          `json:"timezone"`
+	StorageQuotaSizeMegabytes           int                                             `json:"storageQuotaSizeMegabytes"`
+	UseStorageQuotaSize                 bool                                            `json:"useStorageQuotaSize"`
+	TemporarySessionOnly                bool                                            `json:"temporarySessionOnly"`
+	EnforceTemporarySessionTimeout      bool                                            `json:"enforceTemporarySessionTimeout"`
+	TemporarySessionTimeout             int                                             `json:"temporarySessionTimeout"`
+	EnforceUserSessionTimeout           bool                                            `json:"enforceUserSessionTimeout"`
+	UserSessionTimeout                  int                                             `json:"userSessionTimeout"`
+	ID                                  string                                          `json:"id"`
+	ProfileUuid                         string                                          `json:"profileUuid"`
+	SiteId                              string                                          `json:"siteId"`
+	VersionLock                         int                                             `json:"versionLock"`
 }
 
 // Subsets
@@ -118,7 +142,7 @@ type MobileDevicePrestageSubsetNames struct {
 	PrestageDeviceNames []MobileDevicePrestageSubsetNamesName `json:"prestageDeviceNames"`
 	DeviceNamePrefix    string                                `json:"deviceNamePrefix"`
 	DeviceNameSuffix    string                                `json:"deviceNameSuffix"`
-	SingleDeviceName    string
+	SingleDeviceName    string                                `json:"singleDeviceName"`
 }
 
 type MobileDevicePrestageSubsetNamesName struct {
@@ -169,6 +193,22 @@ func (c *Client) GetMobileDevicePrestageByID(id string) (*ResourceMobileDevicePr
 	return &out, nil
 }
 
+// GetMobileDevicePrestageByName retrieves a single mobile prestage by its display name
+func (c *Client) GetMobileDevicePrestageByName(name string) (*ResourceMobileDevicePrestage, error) {
+	prestages, err := c.GetMobileDevicePrestages("")
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedPaginatedGet, "mobile device prestages", err)
+	}
+
+	for _, value := range prestages.Results {
+		if value.DisplayName == name {
+			return &value, nil
+		}
+	}
+
+	return nil, fmt.Errorf(errMsgFailedGetByName, "mobile device prestage", name, errMsgNoName)
+}
+
 // CreateMobileDevicePrestage creates a new mobile prestage and returns the id
 func (c *Client) CreateMobileDevicePrestage(newPrestage ResourceMobileDevicePrestage) (*ResponseMobileDevicePrestageCreate, error) {
 	endpoint := uriMobileDevicePrestages
@@ -200,4 +240,297 @@ func (c *Client) DeleteMobileDevicePrestageByID(id string) error {
 	return nil
 }
 
-// QUERY Which other endpoints required here? I think something to do with the scopes & syncs but strugging to make sense of it.
+// UpdateMobileDevicePrestageByID updates a mobile prestage at the given id. The prestage, location and
+// purchasing versionLocks are sent as given and must be those of the prestage the update is based on; Jamf
+// Pro rejects an update based on an outdated versionLock with 409 Conflict, which is returned as an error.
+// Use ModifyMobileDevicePrestageByID to re-read and re-apply a change on conflicts.
+func (c *Client) UpdateMobileDevicePrestageByID(id string, prestageUpdate *ResourceMobileDevicePrestage) (*ResourceMobileDevicePrestage, error) {
+	out, err := c.putMobileDevicePrestage(id, prestageUpdate)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedUpdateByID, "mobile device prestage", id, err)
+	}
+
+	return out, nil
+}
+
+// ModifyMobileDevicePrestageByID reads the mobile prestage at the given id, applies modify to it and saves it
+// with the versionLocks it was read with. When the prestage changed in between, Jamf Pro answers 409 Conflict
+// and the read, modify and save is started again, up to maxMobileDevicePrestageUpdateAttempts times. modify
+// may be called more than once and must only change the fields it is meant to change.
+func (c *Client) ModifyMobileDevicePrestageByID(id string, modify func(*ResourceMobileDevicePrestage) error) (*ResourceMobileDevicePrestage, error) {
+	var lastErr error
+	for attempt := 0; attempt < maxMobileDevicePrestageUpdateAttempts; attempt++ {
+		current, err := c.GetMobileDevicePrestageByID(id)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := modify(current); err != nil {
+			return nil, err
+		}
+
+		out, err := c.putMobileDevicePrestage(id, current)
+		if err == nil {
+			return out, nil
+		}
+		if !isConflictError(err) {
+			return nil, fmt.Errorf(errMsgFailedUpdateByID, "mobile device prestage", id, err)
+		}
+
+		lastErr = err
+	}
+
+	return nil, fmt.Errorf("mobile device prestage %s changed concurrently %d times in a row: %v", id, maxMobileDevicePrestageUpdateAttempts, lastErr)
+}
+
+// putMobileDevicePrestage sends a prestage update and returns the HTTP client's error unwrapped, so that
+// conflicts can be told apart.
+func (c *Client) putMobileDevicePrestage(id string, prestageUpdate *ResourceMobileDevicePrestage) (*ResourceMobileDevicePrestage, error) {
+	endpoint := fmt.Sprintf("%s/%s", uriMobileDevicePrestages, id)
+	var out ResourceMobileDevicePrestage
+
+	resp, err := c.HTTP.DoRequest("PUT", endpoint, prestageUpdate, &out)
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// UpdateMobileDevicePrestageByName updates a mobile prestage by its display name
+func (c *Client) UpdateMobileDevicePrestageByName(name string, prestageUpdate *ResourceMobileDevicePrestage) (*ResourceMobileDevicePrestage, error) {
+	target, err := c.GetMobileDevicePrestageByName(name)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByName, "mobile device prestage", name, err)
+	}
+
+	out, err := c.UpdateMobileDevicePrestageByID(target.ID, prestageUpdate)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedUpdateByName, "mobile device prestage", name, err)
+	}
+
+	return out, nil
+}
+
+// DeleteMobileDevicePrestageByName deletes a mobile prestage by its display name
+func (c *Client) DeleteMobileDevicePrestageByName(name string) error {
+	target, err := c.GetMobileDevicePrestageByName(name)
+	if err != nil {
+		return fmt.Errorf(errMsgFailedGetByName, "mobile device prestage", name, err)
+	}
+
+	err = c.DeleteMobileDevicePrestageByID(target.ID)
+	if err != nil {
+		return fmt.Errorf(errMsgFailedDeleteByName, "mobile device prestage", name, err)
+	}
+
+	return nil
+}
+
+// Scope
+
+// GetDeviceScopeForMobileDevicePrestageByID retrieves the device scope of a mobile prestage
+func (c *Client) GetDeviceScopeForMobileDevicePrestageByID(id string) (*ResponseDeviceScope, error) {
+	endpoint := fmt.Sprintf("%s/%s/scope", uriMobileDevicePrestages, id)
+
+	var out ResponseDeviceScope
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByID, "mobile device prestage scope", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// GetMobileDevicePrestageScopeAssignments retrieves the prestage ID of every serial number in a mobile
+// prestage scope, keyed by serial number
+func (c *Client) GetMobileDevicePrestageScopeAssignments() (*ResponseMobileDevicePrestageScopeAssignments, error) {
+	endpoint := fmt.Sprintf("%s/scope", uriMobileDevicePrestages)
+
+	var out ResponseMobileDevicePrestageScopeAssignments
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGet, "mobile device prestage scope assignments", err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// GetMobileDevicePrestageByScopedSerialNumber retrieves the mobile prestage whose scope holds a serial number
+func (c *Client) GetMobileDevicePrestageByScopedSerialNumber(serialNumber string) (*ResourceMobileDevicePrestage, error) {
+	assignments, err := c.GetMobileDevicePrestageScopeAssignments()
+	if err != nil {
+		return nil, err
+	}
+
+	id, ok := assignments.SerialsByPrestageId[serialNumber]
+	if !ok {
+		return nil, fmt.Errorf(errMsgFailedGetByString, "mobile device prestage", "scoped serial number", serialNumber, "serial number is not in any prestage scope")
+	}
+
+	return c.GetMobileDevicePrestageByID(id)
+}
+
+// AddDeviceScopeForMobileDevicePrestageByID adds serial numbers to the scope of a mobile prestage. Serial
+// numbers already in scope are skipped; on a versionLock conflict the change is retried against the current scope.
+func (c *Client) AddDeviceScopeForMobileDevicePrestageByID(id string, serialNumbers []string) (*ResponseDeviceScope, error) {
+	endpoint := fmt.Sprintf("%s/%s/scope", uriMobileDevicePrestages, id)
+
+	out, err := c.updatePrestageScope(endpoint, prestageScopeAdd, serialNumbers)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedUpdateByID, "mobile device prestage scope", id, err)
+	}
+
+	return out, nil
+}
+
+// RemoveDeviceScopeForMobileDevicePrestageByID removes serial numbers from the scope of a mobile prestage. Serial
+// numbers not in scope are skipped; on a versionLock conflict the change is retried against the current scope.
+func (c *Client) RemoveDeviceScopeForMobileDevicePrestageByID(id string, serialNumbers []string) (*ResponseDeviceScope, error) {
+	endpoint := fmt.Sprintf("%s/%s/scope", uriMobileDevicePrestages, id)
+
+	out, err := c.updatePrestageScope(endpoint, prestageScopeRemove, serialNumbers)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedUpdateByID, "mobile device prestage scope", id, err)
+	}
+
+	return out, nil
+}
+
+// ReplaceDeviceScopeForMobileDevicePrestageByID replaces the scope of a mobile prestage with the given serial
+// numbers. On a versionLock conflict the change is retried against the current scope.
+func (c *Client) ReplaceDeviceScopeForMobileDevicePrestageByID(id string, serialNumbers []string) (*ResponseDeviceScope, error) {
+	endpoint := fmt.Sprintf("%s/%s/scope", uriMobileDevicePrestages, id)
+
+	out, err := c.updatePrestageScope(endpoint, prestageScopeReplace, serialNumbers)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedUpdateByID, "mobile device prestage scope", id, err)
+	}
+
+	return out, nil
+}
+
+// Syncs
+
+// GetMobileDevicePrestageSyncStates retrieves the latest sync state of every mobile prestage
+func (c *Client) GetMobileDevicePrestageSyncStates() ([]ResponseMobileDevicePrestageSyncState, error) {
+	endpoint := fmt.Sprintf("%s/syncs", uriMobileDevicePrestages)
+
+	var out []ResponseMobileDevicePrestageSyncState
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGet, "mobile device prestage sync states", err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return out, nil
+}
+
+// GetMobileDevicePrestageSyncStatesByID retrieves the sync history of a mobile prestage
+func (c *Client) GetMobileDevicePrestageSyncStatesByID(id string) ([]ResponseMobileDevicePrestageSyncState, error) {
+	endpoint := fmt.Sprintf("%s/%s/syncs", uriMobileDevicePrestages, id)
+
+	var out []ResponseMobileDevicePrestageSyncState
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByID, "mobile device prestage sync states", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return out, nil
+}
+
+// GetLatestMobileDevicePrestageSyncStateByID retrieves the latest sync state of a mobile prestage
+func (c *Client) GetLatestMobileDevicePrestageSyncStateByID(id string) (*ResponseMobileDevicePrestageSyncState, error) {
+	endpoint := fmt.Sprintf("%s/%s/syncs/latest", uriMobileDevicePrestages, id)
+
+	var out ResponseMobileDevicePrestageSyncState
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByID, "latest mobile device prestage sync state", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// Attachments
+
+// GetMobileDevicePrestageAttachmentsByID retrieves the files attached to a mobile prestage
+func (c *Client) GetMobileDevicePrestageAttachmentsByID(id string) ([]ResponseMobileDevicePrestageAttachment, error) {
+	endpoint := fmt.Sprintf("%s/%s/attachments", uriMobileDevicePrestages, id)
+
+	var out []ResponseMobileDevicePrestageAttachment
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByID, "mobile device prestage attachments", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return out, nil
+}
+
+// UploadMobileDevicePrestageAttachmentByID attaches a file to a mobile prestage
+func (c *Client) UploadMobileDevicePrestageAttachmentByID(id string, filePath string) (*ResponseMobileDevicePrestageAttachment, error) {
+	endpoint := fmt.Sprintf("%s/%s/attachments", uriMobileDevicePrestages, id)
+
+	files := map[string]string{
+		"file": filePath,
+	}
+	var out ResponseMobileDevicePrestageAttachment
+
+	resp, err := c.HTTP.DoMultipartRequest("POST", endpoint, nil, files, &out)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload mobile device prestage attachment for id: %s, error: %v", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// DeleteMobileDevicePrestageAttachmentsByID removes attachments from a mobile prestage
+func (c *Client) DeleteMobileDevicePrestageAttachmentsByID(id string, attachmentIDs []string) error {
+	endpoint := fmt.Sprintf("%s/%s/attachments/delete-multiple", uriMobileDevicePrestages, id)
+
+	request := struct {
+		IDs []string `json:"ids"`
+	}{attachmentIDs}
+
+	// Jamf Pro answers 204 No Content, which the HTTP client reports as an unexpected MIME type.
+	resp, err := c.HTTP.DoRequest("POST", endpoint, &request, nil)
+	if err != nil && (resp == nil || resp.StatusCode != http.StatusNoContent) {
+		return fmt.Errorf(errMsgFailedDeleteByID, "mobile device prestage attachments", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return nil
+}
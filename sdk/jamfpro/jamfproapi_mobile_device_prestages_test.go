@@ -0,0 +1,123 @@
+package jamfpro
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+// testPrestageServer serves a single mobile device prestage guarded by its versionLock, the way Jamf Pro does.
+// conflicts is the number of updates answered with 409 Conflict regardless of their versionLock, as if the
+// prestage had been changed by someone else in between.
+type testPrestageServer struct {
+	prestage  ResourceMobileDevicePrestage
+	conflicts int
+	puts      []ResourceMobileDevicePrestage
+}
+
+func (s *testPrestageServer) client(t *testing.T) *Client {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2/mobile-device-prestages/1", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			writeTestJSON(w, http.StatusOK, s.prestage)
+		case http.MethodPut:
+			var update ResourceMobileDevicePrestage
+			json.NewDecoder(r.Body).Decode(&update)
+			s.puts = append(s.puts, update)
+
+			if s.conflicts > 0 {
+				s.conflicts--
+				s.prestage.VersionLock++
+			}
+			if update.VersionLock != s.prestage.VersionLock {
+				writeTestJSON(w, http.StatusConflict, map[string]interface{}{
+					"httpStatus": http.StatusConflict,
+					"errors":     []map[string]string{{"code": "OPTIMISTIC_LOCK_FAILED"}},
+				})
+				return
+			}
+
+			update.VersionLock++
+			s.prestage = update
+			writeTestJSON(w, http.StatusOK, s.prestage)
+		}
+	})
+	return newTestClient(t, mux)
+}
+
+func TestUpdateMobileDevicePrestageByIDSendsCallerVersionLock(t *testing.T) {
+	tests := []struct {
+		name        string
+		versionLock int
+		wantErr     bool
+	}{
+		{name: "current version lock", versionLock: 4},
+		{name: "outdated version lock", versionLock: 3, wantErr: true},
+		{name: "zero version lock", versionLock: 0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &testPrestageServer{prestage: ResourceMobileDevicePrestage{ID: "1", DisplayName: "iPads", VersionLock: 4}}
+			client := server.client(t)
+
+			_, err := client.UpdateMobileDevicePrestageByID("1", &ResourceMobileDevicePrestage{ID: "1", DisplayName: "Renamed", VersionLock: tt.versionLock})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UpdateMobileDevicePrestageByID error = %v, want error %v", err, tt.wantErr)
+			}
+			if len(server.puts) != 1 || server.puts[0].VersionLock != tt.versionLock {
+				t.Errorf("sent %+v, want a single update with versionLock %d", server.puts, tt.versionLock)
+			}
+			if tt.wantErr && server.prestage.DisplayName != "iPads" {
+				t.Error("a rejected update changed the prestage")
+			}
+		})
+	}
+}
+
+func TestModifyMobileDevicePrestageByID(t *testing.T) {
+	tests := []struct {
+		name      string
+		conflicts int
+		wantPuts  int
+		wantErr   bool
+	}{
+		{name: "no conflict", conflicts: 0, wantPuts: 1},
+		{name: "retried after conflicts", conflicts: 2, wantPuts: 3},
+		{name: "gives up", conflicts: maxMobileDevicePrestageUpdateAttempts, wantPuts: maxMobileDevicePrestageUpdateAttempts, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &testPrestageServer{
+				prestage:  ResourceMobileDevicePrestage{ID: "1", DisplayName: "iPads", Department: "IT", VersionLock: 4},
+				conflicts: tt.conflicts,
+			}
+			client := server.client(t)
+
+			modified := 0
+			out, err := client.ModifyMobileDevicePrestageByID("1", func(prestage *ResourceMobileDevicePrestage) error {
+				modified++
+				prestage.Department = "Education"
+				return nil
+			})
+			if len(server.puts) != tt.wantPuts || modified != tt.wantPuts {
+				t.Errorf("puts = %d, modify calls = %d, want %d", len(server.puts), modified, tt.wantPuts)
+			}
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("ModifyMobileDevicePrestageByID succeeded, want a conflict error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ModifyMobileDevicePrestageByID: %v", err)
+			}
+			if out.Department != "Education" || out.DisplayName != "iPads" {
+				t.Errorf("prestage = %+v", out)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+// jamfproapi_notifications.go
+// Jamf Pro Api - Notifications
+// api reference: https://developer.jamf.com/jamf-pro/reference/get_v1-notifications
+// Jamf Pro API requires the structs to support a JSON data structure.
+// Notifications are the alerts shown in the Jamf Pro web interface for the current user, e.g. an expiring push
+// certificate. Params differ per notification type.
+
+package jamfpro
+
+import "fmt"
+
+const uriNotifications = "/api/v1/notifications"
+
+// Notification types for expiring and expired credentials
+const (
+	NotificationTypePushCertWillExpire      = "PUSH_CERT_WILL_EXPIRE"
+	NotificationTypePushCertExpired         = "PUSH_CERT_EXPIRED"
+	NotificationTypeDEPInstanceWillExpire   = "DEP_INSTANCE_WILL_EXPIRE"
+	NotificationTypeDEPInstanceExpired      = "DEP_INSTANCE_EXPIRED"
+	NotificationTypeVPPAccountWillExpire    = "VPP_ACCOUNT_WILL_EXPIRE"
+	NotificationTypeVPPAccountExpired       = "VPP_ACCOUNT_EXPIRED"
+	NotificationTypeSSOCertWillExpire       = "SSO_CERT_WILL_EXPIRE"
+	NotificationTypeSSOCertExpired          = "SSO_CERT_EXPIRED"
+	NotificationTypeCloudLdapCertWillExpire = "CLOUD_LDAP_CERT_WILL_EXPIRE"
+	NotificationTypeCloudLdapCertExpired    = "CLOUD_LDAP_CERT_EXPIRED"
+)
+
+// Resource
+
+type ResourceNotification struct {
+	Type   string                 `json:"type"`
+	ID     string                 `json:"id"`
+	Params map[string]interface{} `json:"params"`
+}
+
+// CRUD
+
+// GetNotifications retrieves the notifications for the current user.
+func (c *Client) GetNotifications() ([]ResourceNotification, error) {
+	endpoint := uriNotifications
+	var out []ResourceNotification
+
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGet, "notifications", err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return out, nil
+}
+
+// DeleteNotificationByTypeAndID dismisses a notification for the current user.
+func (c *Client) DeleteNotificationByTypeAndID(notificationType, id string) error {
+	endpoint := fmt.Sprintf("%s/%s/%s", uriNotifications, notificationType, id)
+
+	resp, err := c.HTTP.DoRequest("DELETE", endpoint, nil, nil)
+	if err != nil {
+		return fmt.Errorf(errMsgFailedDeleteByID, "notification", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return nil
+}
@@ -2,11 +2,14 @@
 // Jamf Pro Api - Patch Policies On Dashboard
 // api reference: https://developer.jamf.com/jamf-pro/reference/get_v2-patch-policies
 // Jamf Pro Api requires the structs to support an JSON data structure.
+// Patch policies are created and updated with the Classic API; the Jamf Pro API covers the summaries, the
+// dashboard and the per-device logs, including retrying failed installations.
 
 package jamfpro
 
 import (
 	"fmt"
+	"net/http"
 
 	"github.com/mitchellh/mapstructure"
 )
@@ -21,8 +24,19 @@ type ResponsePatchPoliciesList struct {
 	Results []ResourcePatchPolicy `json:"results"`
 }
 
+// Struct for paginated response for patch policy logs
+type ResponsePatchPolicyLogsList struct {
+	TotalCount int                      `json:"totalCount"`
+	Results    []ResourcePatchPolicyLog `json:"results"`
+}
+
 // Response
 
+// Response struct for the number of patch policy logs eligible for a retry
+type ResponsePatchPolicyLogsRetryEligibleCount struct {
+	Count int `json:"count"`
+}
+
 // Response struct for creating a patch policy
 type ResponsePatchPolicyCreate struct {
 	ID   string `json:"id"`
@@ -46,6 +60,39 @@ type ResourcePatchPolicy struct {
 	Failed                       int    `json:"failed"`
 }
 
+// Resource struct representing the patch status of a device for a patch policy
+type ResourcePatchPolicyLog struct {
+	DeviceId                string `json:"deviceId"`
+	DeviceName              string `json:"deviceName"`
+	StatusCode              int    `json:"statusCode"`
+	StatusDate              string `json:"statusDate"`
+	StatusEnum              string `json:"statusEnum"`
+	AttemptNumber           int    `json:"attemptNumber"`
+	IgnoredForPatchPolicyId string `json:"ignoredForPatchPolicyId"`
+}
+
+// Resource struct representing an installation attempt of a patch policy on a device
+type ResourcePatchPolicyLogDetail struct {
+	ID            string                             `json:"id"`
+	AttemptNumber int                                `json:"attemptNumber"`
+	DeviceId      string                             `json:"deviceId"`
+	Actions       []PatchPolicyLogDetailSubsetAction `json:"actions"`
+}
+
+type PatchPolicyLogDetailSubsetAction struct {
+	ID     string `json:"id"`
+	Action string `json:"action"`
+	Status string `json:"status"`
+}
+
+// Patch policy log statuses
+const (
+	PatchPolicyLogStatusPending   = "PENDING"
+	PatchPolicyLogStatusCompleted = "COMPLETED"
+	PatchPolicyLogStatusFailed    = "FAILED"
+	PatchPolicyLogStatusUnknown   = "UNKNOWN"
+)
+
 // Gets full list of patch policies & handles pagination
 func (c *Client) GetPatchPolicies(sortFilter string) (*ResponsePatchPoliciesList, error) {
 	resp, err := c.DoPaginatedGet(
@@ -73,3 +120,162 @@ func (c *Client) GetPatchPolicies(sortFilter string) (*ResponsePatchPoliciesList
 
 	return &out, nil
 }
+
+// Gets whether a patch policy is on the dashboard
+func (c *Client) GetPatchPolicyDashboardStatusByID(id string) (*ResponsePatchDashboardStatus, error) {
+	endpoint := fmt.Sprintf("%s/%s/dashboard", uriPatchPoliciesJamfProAPI, id)
+	var out ResponsePatchDashboardStatus
+
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByID, "patch policy dashboard status", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// Adds a patch policy to the dashboard
+func (c *Client) AddPatchPolicyToDashboardByID(id string) error {
+	endpoint := fmt.Sprintf("%s/%s/dashboard", uriPatchPoliciesJamfProAPI, id)
+
+	resp, err := c.HTTP.DoRequest("POST", endpoint, nil, nil)
+	if err != nil && (resp == nil || resp.StatusCode != http.StatusNoContent) {
+		return fmt.Errorf(errMsgFailedUpdateByID, "patch policy dashboard", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return nil
+}
+
+// Removes a patch policy from the dashboard
+func (c *Client) RemovePatchPolicyFromDashboardByID(id string) error {
+	endpoint := fmt.Sprintf("%s/%s/dashboard", uriPatchPoliciesJamfProAPI, id)
+
+	resp, err := c.HTTP.DoRequest("DELETE", endpoint, nil, nil)
+	if err != nil {
+		return fmt.Errorf(errMsgFailedDeleteByID, "patch policy dashboard", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return nil
+}
+
+// Gets the patch status of every device in scope of a patch policy & handles pagination. Filter on status to
+// find failed installations, e.g. "&filter=statusEnum==FAILED".
+func (c *Client) GetPatchPolicyLogsByID(id string, sortFilter string) (*ResponsePatchPolicyLogsList, error) {
+	endpoint := fmt.Sprintf("%s/%s/logs", uriPatchPoliciesJamfProAPI, id)
+	resp, err := c.DoPaginatedGet(endpoint, standardPageSize, startingPageNumber, sortFilter)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedPaginatedGet, "patch policy logs", err)
+	}
+
+	var out ResponsePatchPolicyLogsList
+	out.TotalCount = resp.Size
+
+	for _, value := range resp.Results {
+		var newObj ResourcePatchPolicyLog
+		err := mapstructure.Decode(value, &newObj)
+		if err != nil {
+			return nil, fmt.Errorf(errMsgFailedMapstruct, "patch policy log", err)
+		}
+		out.Results = append(out.Results, newObj)
+	}
+
+	return &out, nil
+}
+
+// Gets the patch status of a device for a patch policy
+func (c *Client) GetPatchPolicyLogByDeviceID(id string, deviceID string) (*ResourcePatchPolicyLog, error) {
+	endpoint := fmt.Sprintf("%s/%s/logs/%s", uriPatchPoliciesJamfProAPI, id, deviceID)
+	var out ResourcePatchPolicyLog
+
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByID, "patch policy log for device", deviceID, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// Gets the installation attempts of a patch policy on a device, with the actions of each
+func (c *Client) GetPatchPolicyLogDetailsByDeviceID(id string, deviceID string) ([]ResourcePatchPolicyLogDetail, error) {
+	endpoint := fmt.Sprintf("%s/%s/logs/%s/details", uriPatchPoliciesJamfProAPI, id, deviceID)
+	var out []ResourcePatchPolicyLogDetail
+
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByID, "patch policy log details for device", deviceID, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return out, nil
+}
+
+// Gets the number of devices whose patch policy installation can be retried
+func (c *Client) GetPatchPolicyLogsRetryEligibleCountByID(id string) (*ResponsePatchPolicyLogsRetryEligibleCount, error) {
+	endpoint := fmt.Sprintf("%s/%s/logs/eligible-retry-count", uriPatchPoliciesJamfProAPI, id)
+	var out ResponsePatchPolicyLogsRetryEligibleCount
+
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByID, "patch policy logs eligible retry count", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// Retries the patch policy installation on the given devices
+func (c *Client) RetryPatchPolicyLogsByID(id string, deviceIDs []string) error {
+	endpoint := fmt.Sprintf("%s/%s/logs/retry", uriPatchPoliciesJamfProAPI, id)
+	request := struct {
+		DeviceIds []string `json:"deviceIds"`
+	}{deviceIDs}
+
+	resp, err := c.HTTP.DoRequest("POST", endpoint, &request, nil)
+	if err != nil && (resp == nil || resp.StatusCode != http.StatusNoContent) {
+		return fmt.Errorf(errMsgFailedUpdateByID, "patch policy logs retry", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return nil
+}
+
+// Retries the patch policy installation on every device eligible for a retry
+func (c *Client) RetryAllPatchPolicyLogsByID(id string) error {
+	endpoint := fmt.Sprintf("%s/%s/logs/retry-all", uriPatchPoliciesJamfProAPI, id)
+
+	resp, err := c.HTTP.DoRequest("POST", endpoint, nil, nil)
+	if err != nil && (resp == nil || resp.StatusCode != http.StatusNoContent) {
+		return fmt.Errorf(errMsgFailedUpdateByID, "patch policy logs retry all", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return nil
+}
@@ -2,12 +2,22 @@
 // Jamf Pro Api - Patch Software Title Configurations
 // api reference: https://developer.jamf.com/jamf-pro/reference/get_v2-patch-software-title-configurations-id
 // Jamf Pro API requires the structs to support a JSON data structure.
+// Besides the configuration itself, a software title configuration gives access to the patch definitions of the
+// title, the patch report (the version each device runs), the patch summary and the dashboard.
 
 package jamfpro
 
-import "fmt"
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
 
-const uriPatchSoftwareTitleConfigurations = "/api/v2/patch-software-title-configurations/"
+	"github.com/mitchellh/mapstructure"
+)
+
+const uriPatchSoftwareTitleConfigurations = "/api/v2/patch-software-title-configurations"
 
 // Structs
 
@@ -22,6 +32,48 @@ type ResponsePatchSoftwareTitleConfigurationCreate struct {
 	Href string `json:"href"`
 }
 
+type ResponsePatchSoftwareTitleDefinitionsList struct {
+	TotalCount int                            `json:"totalCount"`
+	Results    []PatchSoftwareTitleDefinition `json:"results"`
+}
+
+type ResponsePatchReportList struct {
+	TotalCount int                 `json:"totalCount"`
+	Results    []PatchReportDevice `json:"results"`
+}
+
+type ResponsePatchSoftwareTitleConfigurationHistoryList struct {
+	TotalCount int                                              `json:"totalCount"`
+	Results    []ResourcePatchSoftwareTitleConfigurationHistory `json:"results"`
+}
+
+// Responses
+
+// ResponsePatchSummary represents how many devices are up to date with a software title.
+type ResponsePatchSummary struct {
+	SoftwareTitleId              string `json:"softwareTitleId"`
+	SoftwareTitleConfigurationId string `json:"softwareTitleConfigurationId"`
+	Title                        string `json:"title"`
+	LatestVersion                string `json:"latestVersion"`
+	ReleaseDate                  string `json:"releaseDate"`
+	UpToDate                     int    `json:"upToDate"`
+	OutOfDate                    int    `json:"outOfDate"`
+	OnDashboard                  bool   `json:"onDashboard"`
+}
+
+// ResponsePatchDashboardStatus represents whether a software title or patch policy is on the dashboard.
+type ResponsePatchDashboardStatus struct {
+	OnDashboard bool `json:"onDashboard"`
+}
+
+// ResponsePatchReportDevicesBelowVersion represents the devices of a patch report which run a version older
+// than a given one. Unclassified holds the devices whose version has no patch definition, e.g. "Unknown" or a
+// version which has aged out of the definitions, so it cannot be told whether they are below the version.
+type ResponsePatchReportDevicesBelowVersion struct {
+	Below        []PatchReportDevice
+	Unclassified []PatchReportDevice
+}
+
 // Resource
 
 type ResourcePatchSoftwareTitleConfiguration struct {
@@ -46,6 +98,50 @@ type PatchSoftwareTitleConfigurationSubsetExtensionAttribute struct {
 	EaID     string `json:"eaId"`
 }
 
+// PatchSoftwareTitleDefinition represents a version of a software title. AbsoluteOrderId orders the versions,
+// 0 being the latest.
+type PatchSoftwareTitleDefinition struct {
+	Version                string                                `json:"version"`
+	MinimumOperatingSystem string                                `json:"minimumOperatingSystem"`
+	ReleaseDate            string                                `json:"releaseDate"`
+	Reboot                 bool                                  `json:"reboot"`
+	Standalone             bool                                  `json:"standalone"`
+	AbsoluteOrderId        string                                `json:"absoluteOrderId"`
+	KillApps               []PatchSoftwareTitleDefinitionKillApp `json:"killApps"`
+}
+
+type PatchSoftwareTitleDefinitionKillApp struct {
+	AppName string `json:"appName"`
+}
+
+// PatchReportDevice represents a device in a patch report, with the version of the software title it runs.
+type PatchReportDevice struct {
+	ComputerName           string `json:"computerName"`
+	DeviceId               string `json:"deviceId"`
+	Username               string `json:"username"`
+	OperatingSystemVersion string `json:"operatingSystemVersion"`
+	LastContactTime        string `json:"lastContactTime"`
+	BuildingName           string `json:"buildingName"`
+	DepartmentName         string `json:"departmentName"`
+	SiteName               string `json:"siteName"`
+	Version                string `json:"version"`
+}
+
+// PatchSummaryVersion represents how many devices run a version of a software title.
+type PatchSummaryVersion struct {
+	AbsoluteOrderId string `json:"absoluteOrderId"`
+	Version         string `json:"version"`
+	OnVersion       int    `json:"onVersion"`
+}
+
+type ResourcePatchSoftwareTitleConfigurationHistory struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+	Date     string `json:"date"`
+	Note     string `json:"note"`
+	Details  string `json:"details"`
+}
+
 type PatchSoftwareTitleConfigurationSubsetPackage struct {
 	PackageId   string `json:"packageId"`
 	Version     string `json:"version"`
@@ -58,7 +154,7 @@ type PatchSoftwareTitleConfigurationSubsetPackage struct {
 func (c *Client) GetPatchSoftwareTitleConfigurations() (*ResponsePatchSoftwareTitleConfigurationList, error) {
 	endpoint := uriPatchSoftwareTitleConfigurations
 	var out ResponsePatchSoftwareTitleConfigurationList
-	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &out)
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &out.Results)
 	if err != nil {
 		return nil, fmt.Errorf(errMsgFailedGet, "patch software title configurations", err)
 	}
@@ -149,3 +245,322 @@ func (c *Client) DeletePatchSoftwareTitleConfigurationById(id string) error {
 
 	return nil
 }
+
+// GetPatchSoftwareTitleDefinitionsById retrieves the patch definitions of a software title, latest first unless
+// sorted otherwise
+func (c *Client) GetPatchSoftwareTitleDefinitionsById(id string, sort_filter string) (*ResponsePatchSoftwareTitleDefinitionsList, error) {
+	endpoint := fmt.Sprintf("%s/%s/definitions", uriPatchSoftwareTitleConfigurations, id)
+	resp, err := c.DoPaginatedGet(endpoint, standardPageSize, startingPageNumber, sort_filter)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedPaginatedGet, "patch software title definitions", err)
+	}
+
+	var out ResponsePatchSoftwareTitleDefinitionsList
+	out.TotalCount = resp.Size
+
+	for _, value := range resp.Results {
+		var newObj PatchSoftwareTitleDefinition
+		err := mapstructure.Decode(value, &newObj)
+		if err != nil {
+			return nil, fmt.Errorf(errMsgFailedMapstruct, "patch software title definition", err)
+		}
+		out.Results = append(out.Results, newObj)
+	}
+
+	return &out, nil
+}
+
+// GetPatchReportById retrieves the patch report of a software title configuration: the devices which have the
+// title installed, with the version each runs. Filter on version to find devices on given versions, e.g.
+// "&filter=version=in=(120.0.1,120.0.2)".
+func (c *Client) GetPatchReportById(id string, sort_filter string) (*ResponsePatchReportList, error) {
+	endpoint := fmt.Sprintf("%s/%s/patch-report", uriPatchSoftwareTitleConfigurations, id)
+	resp, err := c.DoPaginatedGet(endpoint, standardPageSize, startingPageNumber, sort_filter)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedPaginatedGet, "patch report", err)
+	}
+
+	var out ResponsePatchReportList
+	out.TotalCount = resp.Size
+
+	for _, value := range resp.Results {
+		var newObj PatchReportDevice
+		err := mapstructure.Decode(value, &newObj)
+		if err != nil {
+			return nil, fmt.Errorf(errMsgFailedMapstruct, "patch report device", err)
+		}
+		out.Results = append(out.Results, newObj)
+	}
+
+	return &out, nil
+}
+
+// GetPatchReportByVersionById retrieves the patch report of a software title configuration grouped by version.
+func (c *Client) GetPatchReportByVersionById(id string) (map[string][]PatchReportDevice, error) {
+	report, err := c.GetPatchReportById(id, "")
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string][]PatchReportDevice)
+	for _, device := range report.Results {
+		out[device.Version] = append(out[device.Version], device)
+	}
+
+	return out, nil
+}
+
+// GetPatchReportDevicesBelowVersionById retrieves the devices which run a version of a software title older than
+// the given version, e.g. the first version fixing a vulnerability. Versions are ordered by the patch
+// definitions; devices on a version without definition, such as "Unknown", are returned as unclassified.
+func (c *Client) GetPatchReportDevicesBelowVersionById(id string, version string) (*ResponsePatchReportDevicesBelowVersion, error) {
+	definitions, err := c.GetPatchSoftwareTitleDefinitionsById(id, "")
+	if err != nil {
+		return nil, err
+	}
+
+	order := make(map[string]int, len(definitions.Results))
+	for _, definition := range definitions.Results {
+		absoluteOrder, err := strconv.Atoi(definition.AbsoluteOrderId)
+		if err != nil {
+			return nil, fmt.Errorf("invalid absolute order id %q for version %s", definition.AbsoluteOrderId, definition.Version)
+		}
+		order[definition.Version] = absoluteOrder
+	}
+
+	threshold, ok := order[version]
+	if !ok {
+		return nil, fmt.Errorf("version %s has no patch definition for software title configuration %s", version, id)
+	}
+
+	report, err := c.GetPatchReportById(id, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var out ResponsePatchReportDevicesBelowVersion
+	for _, device := range report.Results {
+		absoluteOrder, known := order[device.Version]
+		switch {
+		case !known:
+			out.Unclassified = append(out.Unclassified, device)
+		case absoluteOrder > threshold:
+			out.Below = append(out.Below, device)
+		}
+	}
+
+	return &out, nil
+}
+
+// ExportPatchReportById writes the patch report of a software title configuration as CSV, with a header row.
+// The report is exported client side, as the HTTP client cannot decode the CSV returned by the export-report
+// endpoint.
+func (c *Client) ExportPatchReportById(id string, sort_filter string, w io.Writer) error {
+	report, err := c.GetPatchReportById(id, sort_filter)
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	header := []string{"Computer Name", "Device ID", "Username", "Operating System Version", "Last Contact Time", "Building", "Department", "Site", "Version"}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, device := range report.Results {
+		record := []string{
+			device.ComputerName,
+			device.DeviceId,
+			device.Username,
+			device.OperatingSystemVersion,
+			device.LastContactTime,
+			device.BuildingName,
+			device.DepartmentName,
+			device.SiteName,
+			device.Version,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+// GetPatchSummaryById retrieves the patch summary of a software title configuration
+func (c *Client) GetPatchSummaryById(id string) (*ResponsePatchSummary, error) {
+	endpoint := fmt.Sprintf("%s/%s/patch-summary", uriPatchSoftwareTitleConfigurations, id)
+	var out ResponsePatchSummary
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByID, "patch summary", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// GetPatchSummaryVersionsById retrieves the number of devices on each version of a software title
+func (c *Client) GetPatchSummaryVersionsById(id string) ([]PatchSummaryVersion, error) {
+	endpoint := fmt.Sprintf("%s/%s/patch-summary/versions", uriPatchSoftwareTitleConfigurations, id)
+	var out []PatchSummaryVersion
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByID, "patch summary versions", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return out, nil
+}
+
+// GetPatchSoftwareTitleConfigurationDashboardStatusById retrieves whether a software title is on the dashboard
+func (c *Client) GetPatchSoftwareTitleConfigurationDashboardStatusById(id string) (*ResponsePatchDashboardStatus, error) {
+	endpoint := fmt.Sprintf("%s/%s/dashboard", uriPatchSoftwareTitleConfigurations, id)
+	var out ResponsePatchDashboardStatus
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByID, "patch software title configuration dashboard status", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// AddPatchSoftwareTitleConfigurationToDashboardById adds a software title to the dashboard
+func (c *Client) AddPatchSoftwareTitleConfigurationToDashboardById(id string) error {
+	endpoint := fmt.Sprintf("%s/%s/dashboard", uriPatchSoftwareTitleConfigurations, id)
+	resp, err := c.HTTP.DoRequest("POST", endpoint, nil, nil)
+	if err != nil && (resp == nil || resp.StatusCode != http.StatusNoContent) {
+		return fmt.Errorf(errMsgFailedUpdateByID, "patch software title configuration dashboard", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return nil
+}
+
+// RemovePatchSoftwareTitleConfigurationFromDashboardById removes a software title from the dashboard
+func (c *Client) RemovePatchSoftwareTitleConfigurationFromDashboardById(id string) error {
+	endpoint := fmt.Sprintf("%s/%s/dashboard", uriPatchSoftwareTitleConfigurations, id)
+	resp, err := c.HTTP.DoRequest("DELETE", endpoint, nil, nil)
+	if err != nil {
+		return fmt.Errorf(errMsgFailedDeleteByID, "patch software title configuration dashboard", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return nil
+}
+
+// GetPatchSoftwareTitleConfigurationHistoryById retrieves the history of a software title configuration
+func (c *Client) GetPatchSoftwareTitleConfigurationHistoryById(id string, sort_filter string) (*ResponsePatchSoftwareTitleConfigurationHistoryList, error) {
+	endpoint := fmt.Sprintf("%s/%s/history", uriPatchSoftwareTitleConfigurations, id)
+	resp, err := c.DoPaginatedGet(endpoint, standardPageSize, startingPageNumber, sort_filter)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedPaginatedGet, "patch software title configuration history", err)
+	}
+
+	var out ResponsePatchSoftwareTitleConfigurationHistoryList
+	out.TotalCount = resp.Size
+
+	for _, value := range resp.Results {
+		var newObj ResourcePatchSoftwareTitleConfigurationHistory
+		err := mapstructure.Decode(value, &newObj)
+		if err != nil {
+			return nil, fmt.Errorf(errMsgFailedMapstruct, "patch software title configuration history", err)
+		}
+		out.Results = append(out.Results, newObj)
+	}
+
+	return &out, nil
+}
+
+// CreatePatchSoftwareTitleConfigurationHistoryNoteById adds a note to the history of a software title
+// configuration
+func (c *Client) CreatePatchSoftwareTitleConfigurationHistoryNoteById(id string, note string) (*ResourcePatchSoftwareTitleConfigurationHistory, error) {
+	endpoint := fmt.Sprintf("%s/%s/history", uriPatchSoftwareTitleConfigurations, id)
+	request := struct {
+		Note string `json:"note"`
+	}{note}
+
+	var out ResourcePatchSoftwareTitleConfigurationHistory
+	resp, err := c.HTTP.DoRequest("POST", endpoint, &request, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedUpdateByID, "patch software title configuration history", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// GetPatchSoftwareTitleConfigurationExtensionAttributesById retrieves the extension attributes a software title
+// requires and whether each has been accepted
+func (c *Client) GetPatchSoftwareTitleConfigurationExtensionAttributesById(id string) ([]PatchSoftwareTitleConfigurationSubsetExtensionAttribute, error) {
+	endpoint := fmt.Sprintf("%s/%s/extension-attributes", uriPatchSoftwareTitleConfigurations, id)
+	var out []PatchSoftwareTitleConfigurationSubsetExtensionAttribute
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByID, "patch software title configuration extension attributes", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return out, nil
+}
+
+// AcceptPatchSoftwareTitleConfigurationExtensionAttributesById accepts every extension attribute a software
+// title requires. Until they are accepted, Jamf Pro cannot report the versions of titles which rely on them.
+func (c *Client) AcceptPatchSoftwareTitleConfigurationExtensionAttributesById(id string) error {
+	extensionAttributes, err := c.GetPatchSoftwareTitleConfigurationExtensionAttributesById(id)
+	if err != nil {
+		return err
+	}
+
+	pending := false
+	for i := range extensionAttributes {
+		if !extensionAttributes[i].Accepted {
+			extensionAttributes[i].Accepted = true
+			pending = true
+		}
+	}
+	if !pending {
+		return nil
+	}
+
+	endpoint := fmt.Sprintf("%s/%s", uriPatchSoftwareTitleConfigurations, id)
+	request := struct {
+		ExtensionAttributes []PatchSoftwareTitleConfigurationSubsetExtensionAttribute `json:"extensionAttributes"`
+	}{extensionAttributes}
+
+	var out ResourcePatchSoftwareTitleConfiguration
+	resp, err := c.HTTP.DoRequest("PATCH", endpoint, &request, &out)
+	if err != nil {
+		return fmt.Errorf(errMsgFailedUpdateByID, "patch software title configuration extension attributes", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package jamfpro
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestGetPatchReportDevicesBelowVersionById(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2/patch-software-title-configurations/5/definitions", func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, http.StatusOK, map[string]interface{}{
+			"totalCount": 3,
+			"results": []PatchSoftwareTitleDefinition{
+				{Version: "121.0", AbsoluteOrderId: "0"},
+				{Version: "120.1", AbsoluteOrderId: "1"},
+				{Version: "120.0", AbsoluteOrderId: "2"},
+			},
+		})
+	})
+	mux.HandleFunc("/api/v2/patch-software-title-configurations/5/patch-report", func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, http.StatusOK, map[string]interface{}{
+			"totalCount": 5,
+			"results": []PatchReportDevice{
+				{DeviceId: "1", Version: "121.0"},
+				{DeviceId: "2", Version: "120.1"},
+				{DeviceId: "3", Version: "120.0"},
+				{DeviceId: "4", Version: "Unknown"},
+				{DeviceId: "5", Version: "99.0"},
+			},
+		})
+	})
+
+	client := newTestClient(t, mux)
+
+	devices, err := client.GetPatchReportDevicesBelowVersionById("5", "120.1")
+	if err != nil {
+		t.Fatalf("GetPatchReportDevicesBelowVersionById: %v", err)
+	}
+
+	deviceIDs := func(devices []PatchReportDevice) []string {
+		var ids []string
+		for _, device := range devices {
+			ids = append(ids, device.DeviceId)
+		}
+		return ids
+	}
+	if got := deviceIDs(devices.Below); len(got) != 1 || got[0] != "3" {
+		t.Errorf("Below = %v, want [3]", got)
+	}
+	if got := deviceIDs(devices.Unclassified); len(got) != 2 || got[0] != "4" || got[1] != "5" {
+		t.Errorf("Unclassified = %v, want [4 5]", got)
+	}
+
+	if _, err := client.GetPatchReportDevicesBelowVersionById("5", "1.0"); err == nil {
+		t.Error("a version without patch definition was accepted")
+	}
+}
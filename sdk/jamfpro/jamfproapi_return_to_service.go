@@ -1,8 +1,10 @@
 // jamfproapi_return_to_service.go
 // Jamf Pro Api - Return to Service
-// api reference: none available
+// api reference: https://developer.jamf.com/jamf-pro/reference/get_v1-return-to-service
 // docs: https://learn.jamf.com/en-US/bundle/technical-articles/page/Return_to_Service.html
 // Jamf Pro Api requires the structs to support an JSON data structure.
+// A Return to Service configuration names the Wi-Fi configuration profile a device installs after it is
+// erased, so it can rejoin the network and re-enroll without user interaction.
 
 package jamfpro
 
@@ -12,18 +14,177 @@ import (
 
 const uriReturnToService = "/api/v1/return-to-service"
 
-// GetReturnToService fetches a list of devices that are in the Return to Service state.
-func (c *Client) GetReturnToService() ([]ResponseJCDS2List, error) {
+// List
+
+// ResponseReturnToServiceConfigurationsList represents the response for listing Return to Service configurations.
+type ResponseReturnToServiceConfigurationsList struct {
+	TotalCount int                                    `json:"totalCount"`
+	Results    []ResourceReturnToServiceConfiguration `json:"results"`
+}
+
+// Responses
+
+// ResponseReturnToServiceConfigurationCreate represents the response for creating a Return to Service configuration.
+type ResponseReturnToServiceConfigurationCreate struct {
+	ID   string `json:"id"`
+	Href string `json:"href"`
+}
+
+// Resource
+
+// ResourceReturnToServiceConfiguration represents a Return to Service configuration. WifiProfileID is the ID
+// of the mobile device configuration profile holding the Wi-Fi payload.
+type ResourceReturnToServiceConfiguration struct {
+	ID            string `json:"id,omitempty"`
+	DisplayName   string `json:"displayName"`
+	WifiProfileID string `json:"wifiProfileId"`
+}
+
+// CRUD
+
+// GetReturnToServiceConfigurations retrieves all Return to Service configurations.
+func (c *Client) GetReturnToServiceConfigurations() (*ResponseReturnToServiceConfigurationsList, error) {
 	endpoint := uriReturnToService
-	var out []ResponseJCDS2List
+	var out ResponseReturnToServiceConfigurationsList
+
 	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &out)
 	if err != nil {
-		return nil, fmt.Errorf(errMsgFailedGet, "Return To Service", err)
+		return nil, fmt.Errorf(errMsgFailedGet, "return to service configurations", err)
 	}
 
 	if resp != nil && resp.Body != nil {
 		defer resp.Body.Close()
 	}
 
+	return &out, nil
+}
+
+// GetReturnToServiceConfigurationByID retrieves a Return to Service configuration by ID.
+func (c *Client) GetReturnToServiceConfigurationByID(id string) (*ResourceReturnToServiceConfiguration, error) {
+	endpoint := fmt.Sprintf("%s/%s", uriReturnToService, id)
+	var out ResourceReturnToServiceConfiguration
+
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByID, "return to service configuration", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// GetReturnToServiceConfigurationByName retrieves a Return to Service configuration by display name.
+func (c *Client) GetReturnToServiceConfigurationByName(name string) (*ResourceReturnToServiceConfiguration, error) {
+	configurations, err := c.GetReturnToServiceConfigurations()
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGet, "return to service configurations", err)
+	}
+
+	for _, value := range configurations.Results {
+		if value.DisplayName == name {
+			return &value, nil
+		}
+	}
+
+	return nil, fmt.Errorf(errMsgFailedGetByName, "return to service configuration", name, errMsgNoName)
+}
+
+// GetReturnToServiceConfigurationsByWifiProfileID retrieves the Return to Service configurations which use a
+// Wi-Fi configuration profile, e.g. before the profile is changed or deleted.
+func (c *Client) GetReturnToServiceConfigurationsByWifiProfileID(wifiProfileID string) ([]ResourceReturnToServiceConfiguration, error) {
+	configurations, err := c.GetReturnToServiceConfigurations()
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGet, "return to service configurations", err)
+	}
+
+	var out []ResourceReturnToServiceConfiguration
+	for _, value := range configurations.Results {
+		if value.WifiProfileID == wifiProfileID {
+			out = append(out, value)
+		}
+	}
+
 	return out, nil
 }
+
+// CreateReturnToServiceConfiguration creates a Return to Service configuration.
+func (c *Client) CreateReturnToServiceConfiguration(configuration *ResourceReturnToServiceConfiguration) (*ResponseReturnToServiceConfigurationCreate, error) {
+	endpoint := uriReturnToService
+	var out ResponseReturnToServiceConfigurationCreate
+
+	resp, err := c.HTTP.DoRequest("POST", endpoint, configuration, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedCreate, "return to service configuration", err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// UpdateReturnToServiceConfigurationByID updates a Return to Service configuration by ID.
+func (c *Client) UpdateReturnToServiceConfigurationByID(id string, configurationUpdate *ResourceReturnToServiceConfiguration) (*ResourceReturnToServiceConfiguration, error) {
+	endpoint := fmt.Sprintf("%s/%s", uriReturnToService, id)
+	var out ResourceReturnToServiceConfiguration
+
+	resp, err := c.HTTP.DoRequest("PUT", endpoint, configurationUpdate, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedUpdateByID, "return to service configuration", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// UpdateReturnToServiceConfigurationByName updates a Return to Service configuration by display name.
+func (c *Client) UpdateReturnToServiceConfigurationByName(targetName string, configurationUpdate *ResourceReturnToServiceConfiguration) (*ResourceReturnToServiceConfiguration, error) {
+	target, err := c.GetReturnToServiceConfigurationByName(targetName)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByName, "return to service configuration", targetName, err)
+	}
+
+	resp, err := c.UpdateReturnToServiceConfigurationByID(target.ID, configurationUpdate)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedUpdateByName, "return to service configuration", targetName, err)
+	}
+
+	return resp, nil
+}
+
+// DeleteReturnToServiceConfigurationByID deletes a Return to Service configuration by ID.
+func (c *Client) DeleteReturnToServiceConfigurationByID(id string) error {
+	endpoint := fmt.Sprintf("%s/%s", uriReturnToService, id)
+
+	resp, err := c.HTTP.DoRequest("DELETE", endpoint, nil, nil)
+	if err != nil {
+		return fmt.Errorf(errMsgFailedDeleteByID, "return to service configuration", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return nil
+}
+
+// DeleteReturnToServiceConfigurationByName deletes a Return to Service configuration by display name.
+func (c *Client) DeleteReturnToServiceConfigurationByName(targetName string) error {
+	target, err := c.GetReturnToServiceConfigurationByName(targetName)
+	if err != nil {
+		return fmt.Errorf(errMsgFailedGetByName, "return to service configuration", targetName, err)
+	}
+
+	if err := c.DeleteReturnToServiceConfigurationByID(target.ID); err != nil {
+		return fmt.Errorf(errMsgFailedDeleteByName, "return to service configuration", targetName, err)
+	}
+
+	return nil
+}
@@ -2,7 +2,267 @@
 // Jamf Pro Api - SSO Certificate
 // api reference: https://developer.jamf.com/jamf-pro/reference/get_v2-sso-cert
 // Jamf Pro API requires the structs to support a JSON data structure.
+// The SSO certificate is the keystore Jamf Pro signs SAML requests with. It is either generated by Jamf Pro
+// or uploaded as a PKCS12 or JKS keystore. The public certificate is downloaded separately, as a file.
 
 package jamfpro
 
-// TODO - Awaiting some investigation around what this endpoint actually returns (json or cert or ???)
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	uriSSOCertificate         = "/api/v2/sso/cert"
+	uriSSOCertificateDownload = "/api/v2/sso/cert/download"
+	uriSSOCertificateParse    = "/api/v2/sso/cert/parse"
+)
+
+// SSO keystore types and setup types
+const (
+	SSOKeystoreTypePKCS12         = "PKCS12"
+	SSOKeystoreTypeJKS            = "JKS"
+	SSOKeystoreTypeNone           = "NONE"
+	SSOKeystoreSetupTypeNone      = "NONE"
+	SSOKeystoreSetupTypeUploaded  = "UPLOADED"
+	SSOKeystoreSetupTypeGenerated = "GENERATED"
+)
+
+// Responses
+
+// ResponseSSOKeystoreWithDetails represents the SSO keystore and details of its certificate.
+type ResponseSSOKeystoreWithDetails struct {
+	Keystore        ResourceSSOKeystore             `json:"keystore"`
+	KeystoreDetails SSOKeystoreSubsetKeystoreDetail `json:"keystoreDetails"`
+}
+
+// ResponseSSOKeystoreParse represents the keys and certificate found in a keystore file.
+type ResponseSSOKeystoreParse struct {
+	Keys         []SSOKeystoreSubsetKey `json:"keys"`
+	Subject      string                 `json:"subject"`
+	Issuer       string                 `json:"issuer"`
+	SerialNumber string                 `json:"serialNumber"`
+	Expiration   string                 `json:"expiration"`
+}
+
+// SSOCertificateInfo describes a downloaded SSO certificate.
+type SSOCertificateInfo struct {
+	Subject      string
+	Issuer       string
+	SerialNumber string
+	NotBefore    time.Time
+	NotAfter     time.Time
+	// ExpiresIn is the time left until NotAfter when the certificate was parsed; negative once expired.
+	ExpiresIn   time.Duration
+	Certificate *x509.Certificate
+}
+
+// Resource
+
+// ResourceSSOKeystore represents the SSO keystore. KeystorePassword and KeystoreFile are only sent, when
+// uploading a keystore.
+type ResourceSSOKeystore struct {
+	Key               string                 `json:"key"`
+	Keys              []SSOKeystoreSubsetKey `json:"keys"`
+	Type              string                 `json:"type"`
+	KeystoreSetupType string                 `json:"keystoreSetupType"`
+	KeystoreFileName  string                 `json:"keystoreFileName"`
+	KeystorePassword  string                 `json:"keystorePassword,omitempty"`
+	KeystoreFile      []byte                 `json:"keystoreFile,omitempty"`
+}
+
+type SSOKeystoreSubsetKey struct {
+	ID    string `json:"id"`
+	Valid bool   `json:"valid"`
+}
+
+type SSOKeystoreSubsetKeystoreDetail struct {
+	Keys         []string `json:"keys"`
+	SerialNumber int64    `json:"serialNumber"`
+	Subject      string   `json:"subject"`
+	Issuer       string   `json:"issuer"`
+	Expiration   string   `json:"expiration"`
+}
+
+// CRUD
+
+// GetSSOCertificate retrieves the SSO keystore and the details of its certificate.
+func (c *Client) GetSSOCertificate() (*ResponseSSOKeystoreWithDetails, error) {
+	endpoint := uriSSOCertificate
+	var out ResponseSSOKeystoreWithDetails
+
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGet, "sso certificate", err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// RegenerateSSOCertificate replaces the SSO keystore with a new one generated by Jamf Pro. The identity
+// provider must be given the new certificate afterwards.
+func (c *Client) RegenerateSSOCertificate() (*ResourceSSOKeystore, error) {
+	endpoint := uriSSOCertificate
+	var out ResourceSSOKeystore
+
+	resp, err := c.HTTP.DoRequest("POST", endpoint, nil, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedCreate, "sso certificate", err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// UpdateSSOCertificate uploads a keystore as the SSO keystore.
+func (c *Client) UpdateSSOCertificate(keystore *ResourceSSOKeystore) (*ResourceSSOKeystore, error) {
+	endpoint := uriSSOCertificate
+	var out ResourceSSOKeystore
+
+	resp, err := c.HTTP.DoRequest("PUT", endpoint, keystore, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedUpdate, "sso certificate", err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// UploadSSOCertificateKeystoreFile uploads a PKCS12 (.p12, .pfx) or JKS (.jks) keystore file as the SSO
+// keystore. The keystore is parsed by Jamf Pro first and its first valid key is used.
+func (c *Client) UploadSSOCertificateKeystoreFile(filePath, password string) (*ResourceSSOKeystore, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore file: %v", err)
+	}
+
+	keystore := &ResourceSSOKeystore{
+		Type:              SSOKeystoreTypePKCS12,
+		KeystoreSetupType: SSOKeystoreSetupTypeUploaded,
+		KeystoreFileName:  filepath.Base(filePath),
+		KeystorePassword:  password,
+		KeystoreFile:      data,
+	}
+	if strings.EqualFold(filepath.Ext(filePath), ".jks") {
+		keystore.Type = SSOKeystoreTypeJKS
+	}
+
+	parsed, err := c.ParseSSOCertificateKeystore(keystore)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range parsed.Keys {
+		if key.Valid {
+			keystore.Key = key.ID
+			keystore.Keys = parsed.Keys
+			return c.UpdateSSOCertificate(keystore)
+		}
+	}
+
+	return nil, fmt.Errorf("keystore %s contains no valid key", keystore.KeystoreFileName)
+}
+
+// ParseSSOCertificateKeystore asks Jamf Pro to open a keystore and list its keys, without changing the SSO
+// keystore. KeystoreFile, KeystoreFileName and KeystorePassword must be set.
+func (c *Client) ParseSSOCertificateKeystore(keystore *ResourceSSOKeystore) (*ResponseSSOKeystoreParse, error) {
+	endpoint := uriSSOCertificateParse
+	var out ResponseSSOKeystoreParse
+
+	request := struct {
+		KeystorePassword string `json:"keystorePassword"`
+		KeystoreFile     []byte `json:"keystoreFile"`
+		KeystoreFileName string `json:"keystoreFileName"`
+	}{keystore.KeystorePassword, keystore.KeystoreFile, keystore.KeystoreFileName}
+
+	resp, err := c.HTTP.DoRequest("POST", endpoint, &request, &out)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse sso certificate keystore: %v", err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// DeleteSSOCertificate deletes the SSO keystore.
+func (c *Client) DeleteSSOCertificate() error {
+	endpoint := uriSSOCertificate
+
+	resp, err := c.HTTP.DoRequest("DELETE", endpoint, nil, nil)
+	if err != nil {
+		return fmt.Errorf(errMsgFailedDelete, "sso certificate", err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return nil
+}
+
+// DownloadSSOCertificate downloads the public SSO certificate, as given to the identity provider.
+func (c *Client) DownloadSSOCertificate() ([]byte, error) {
+	endpoint := uriSSOCertificateDownload
+	var out []byte
+
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGet, "sso certificate download", err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return out, nil
+}
+
+// GetSSOCertificateInfo downloads and parses the public SSO certificate, e.g. to monitor its expiry.
+func (c *Client) GetSSOCertificateInfo() (*SSOCertificateInfo, error) {
+	data, err := c.DownloadSSOCertificate()
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseSSOCertificate(data)
+}
+
+// ParseSSOCertificate parses a PEM or DER encoded certificate.
+func ParseSSOCertificate(data []byte) (*SSOCertificateInfo, error) {
+	if block, _ := pem.Decode(data); block != nil {
+		data = block.Bytes
+	}
+
+	certificate, err := x509.ParseCertificate(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse sso certificate: %v", err)
+	}
+
+	return &SSOCertificateInfo{
+		Subject:      certificate.Subject.String(),
+		Issuer:       certificate.Issuer.String(),
+		SerialNumber: certificate.SerialNumber.String(),
+		NotBefore:    certificate.NotBefore,
+		NotAfter:     certificate.NotAfter,
+		ExpiresIn:    time.Until(certificate.NotAfter),
+		Certificate:  certificate,
+	}, nil
+}
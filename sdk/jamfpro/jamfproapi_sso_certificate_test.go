@@ -0,0 +1,191 @@
+package jamfpro
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testSSOCertificate returns a self-signed DER certificate valid from notBefore to notAfter.
+func testSSOCertificate(t *testing.T, notBefore, notAfter time.Time) []byte {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(4242),
+		Subject:      pkix.Name{CommonName: "jamf-sso"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return der
+}
+
+func TestParseSSOCertificate(t *testing.T) {
+	now := time.Now()
+	valid := testSSOCertificate(t, now.Add(-time.Hour), now.Add(48*time.Hour))
+	expired := testSSOCertificate(t, now.Add(-48*time.Hour), now.Add(-time.Hour))
+
+	tests := []struct {
+		name        string
+		data        []byte
+		wantExpired bool
+		wantErr     bool
+	}{
+		{name: "pem", data: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: valid})},
+		{name: "der", data: valid},
+		{name: "expired", data: expired, wantExpired: true},
+		{name: "not a certificate", data: []byte("not a certificate"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := ParseSSOCertificate(tt.data)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("ParseSSOCertificate succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseSSOCertificate: %v", err)
+			}
+			if info.Subject != "CN=jamf-sso" || info.SerialNumber != "4242" {
+				t.Errorf("info = %+v", info)
+			}
+			if expired := info.ExpiresIn < 0; expired != tt.wantExpired {
+				t.Errorf("ExpiresIn = %v, want expired %v", info.ExpiresIn, tt.wantExpired)
+			}
+			if !tt.wantExpired && (info.ExpiresIn > 48*time.Hour || info.ExpiresIn < 47*time.Hour) {
+				t.Errorf("ExpiresIn = %v, want about 48h", info.ExpiresIn)
+			}
+		})
+	}
+}
+
+func TestGetSSOCertificateInfo(t *testing.T) {
+	now := time.Now()
+	der := testSSOCertificate(t, now.Add(-time.Hour), now.Add(30*24*time.Hour))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2/sso/cert/download", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Disposition", `attachment; filename="signing.pem"`)
+		pem.Encode(w, &pem.Block{Type: "CERTIFICATE", Bytes: der})
+	})
+
+	info, err := newTestClient(t, mux).GetSSOCertificateInfo()
+	if err != nil {
+		t.Fatalf("GetSSOCertificateInfo: %v", err)
+	}
+	if !bytes.Equal(info.Certificate.Raw, der) {
+		t.Error("GetSSOCertificateInfo parsed a different certificate")
+	}
+	if info.ExpiresIn < 29*24*time.Hour {
+		t.Errorf("ExpiresIn = %v, want about 30 days", info.ExpiresIn)
+	}
+}
+
+func TestUploadSSOCertificateKeystoreFile(t *testing.T) {
+	keystoreData := []byte("\x30\x82 keystore")
+
+	tests := []struct {
+		name     string
+		fileName string
+		keys     []SSOKeystoreSubsetKey
+		wantType string
+		wantKey  string
+	}{
+		{
+			name:     "first valid key of a pkcs12 keystore",
+			fileName: "sso.p12",
+			keys:     []SSOKeystoreSubsetKey{{ID: "expired", Valid: false}, {ID: "current", Valid: true}},
+			wantType: SSOKeystoreTypePKCS12,
+			wantKey:  "current",
+		},
+		{
+			name:     "jks keystore",
+			fileName: "sso.JKS",
+			keys:     []SSOKeystoreSubsetKey{{ID: "only", Valid: true}},
+			wantType: SSOKeystoreTypeJKS,
+			wantKey:  "only",
+		},
+		{
+			name:     "no valid key",
+			fileName: "sso.pfx",
+			keys:     []SSOKeystoreSubsetKey{{ID: "expired", Valid: false}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var parsed struct {
+				KeystorePassword string `json:"keystorePassword"`
+				KeystoreFile     []byte `json:"keystoreFile"`
+				KeystoreFileName string `json:"keystoreFileName"`
+			}
+			var uploaded *ResourceSSOKeystore
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("/api/v2/sso/cert/parse", func(w http.ResponseWriter, r *http.Request) {
+				json.NewDecoder(r.Body).Decode(&parsed)
+				writeTestJSON(w, http.StatusOK, ResponseSSOKeystoreParse{Keys: tt.keys, Subject: "CN=jamf-sso"})
+			})
+			mux.HandleFunc("/api/v2/sso/cert", func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPut {
+					http.Error(w, "unexpected method", http.StatusMethodNotAllowed)
+					return
+				}
+				uploaded = &ResourceSSOKeystore{}
+				json.NewDecoder(r.Body).Decode(uploaded)
+				writeTestJSON(w, http.StatusOK, ResourceSSOKeystore{Key: uploaded.Key, Type: uploaded.Type, KeystoreSetupType: SSOKeystoreSetupTypeUploaded})
+			})
+
+			path := filepath.Join(t.TempDir(), tt.fileName)
+			if err := os.WriteFile(path, keystoreData, 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			keystore, err := newTestClient(t, mux).UploadSSOCertificateKeystoreFile(path, "changeit")
+			if !bytes.Equal(parsed.KeystoreFile, keystoreData) || parsed.KeystorePassword != "changeit" || parsed.KeystoreFileName != tt.fileName {
+				t.Errorf("parse request = %+v", parsed)
+			}
+
+			if tt.wantKey == "" {
+				if err == nil || uploaded != nil {
+					t.Fatalf("upload without a valid key = %+v, %v", uploaded, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UploadSSOCertificateKeystoreFile: %v", err)
+			}
+			if uploaded == nil || uploaded.Key != tt.wantKey || uploaded.Type != tt.wantType || uploaded.KeystoreSetupType != SSOKeystoreSetupTypeUploaded {
+				t.Errorf("upload request = %+v", uploaded)
+			}
+			if !bytes.Equal(uploaded.KeystoreFile, keystoreData) {
+				t.Error("uploaded keystore differs from the file")
+			}
+			if keystore.Key != tt.wantKey {
+				t.Errorf("returned keystore = %+v", keystore)
+			}
+		})
+	}
+}
@@ -2,7 +2,211 @@
 // Jamf Pro Api - Venafi
 // api reference: https://developer.jamf.com/jamf-pro/reference/post_v1-pki-venafi
 // Jamf Pro API requires the structs to support a JSON data structure.
+// A Venafi CA configuration connects Jamf Pro to a Venafi Trust Protection Platform through the Jamf PKI
+// Proxy Server, for issuing certificates to configuration profiles. The endpoint has no list operation.
 
 package jamfpro
 
-// TODO
+import (
+	"fmt"
+	"net/http"
+)
+
+const uriVenafi = "/api/v1/pki/venafi"
+
+// Responses
+
+// ResponseVenafiCACreate represents the response for creating a Venafi CA configuration.
+type ResponseVenafiCACreate struct {
+	ID   string `json:"id"`
+	Href string `json:"href"`
+}
+
+// ResponseVenafiCAConnectionStatus represents the status of the connection to the PKI Proxy Server.
+type ResponseVenafiCAConnectionStatus struct {
+	Status string `json:"status"`
+}
+
+// ResponseVenafiCADependentProfiles represents the configuration profiles which use a Venafi CA configuration.
+type ResponseVenafiCADependentProfiles struct {
+	TotalCount int                              `json:"totalCount"`
+	Results    []VenafiCASubsetDependentProfile `json:"results"`
+}
+
+type VenafiCASubsetDependentProfile struct {
+	URLPath string `json:"urlPath"`
+	Name    string `json:"name"`
+	ID      string `json:"id"`
+}
+
+// Resource
+
+// ResourceVenafiCA represents a Venafi CA configuration. RefreshToken is write only;
+// RefreshTokenConfigured reports whether one is set.
+type ResourceVenafiCA struct {
+	ID                     string `json:"id,omitempty"`
+	Name                   string `json:"name"`
+	ProxyAddress           string `json:"proxyAddress,omitempty"`
+	RevocationEnabled      bool   `json:"revocationEnabled"`
+	ClientID               string `json:"clientId,omitempty"`
+	RefreshToken           string `json:"refreshToken,omitempty"`
+	RefreshTokenConfigured bool   `json:"refreshTokenConfigured,omitempty"`
+}
+
+// CRUD
+
+// GetVenafiCAByID retrieves a Venafi CA configuration by ID.
+func (c *Client) GetVenafiCAByID(id string) (*ResourceVenafiCA, error) {
+	endpoint := fmt.Sprintf("%s/%s", uriVenafi, id)
+	var out ResourceVenafiCA
+
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByID, "venafi ca", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// CreateVenafiCA creates a Venafi CA configuration.
+func (c *Client) CreateVenafiCA(venafiCA *ResourceVenafiCA) (*ResponseVenafiCACreate, error) {
+	endpoint := uriVenafi
+	var out ResponseVenafiCACreate
+
+	resp, err := c.HTTP.DoRequest("POST", endpoint, venafiCA, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedCreate, "venafi ca", err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// UpdateVenafiCAByID updates a Venafi CA configuration. Fields left empty are not changed.
+func (c *Client) UpdateVenafiCAByID(id string, venafiCAUpdate *ResourceVenafiCA) (*ResourceVenafiCA, error) {
+	endpoint := fmt.Sprintf("%s/%s", uriVenafi, id)
+	var out ResourceVenafiCA
+
+	resp, err := c.HTTP.DoRequest("PATCH", endpoint, venafiCAUpdate, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedUpdateByID, "venafi ca", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// DeleteVenafiCAByID deletes a Venafi CA configuration. Jamf Pro refuses while profiles depend on it.
+func (c *Client) DeleteVenafiCAByID(id string) error {
+	endpoint := fmt.Sprintf("%s/%s", uriVenafi, id)
+
+	resp, err := c.HTTP.DoRequest("DELETE", endpoint, nil, nil)
+	if err != nil {
+		return fmt.Errorf(errMsgFailedDeleteByID, "venafi ca", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return nil
+}
+
+// GetVenafiCAConnectionStatusByID tests the connection between Jamf Pro and the PKI Proxy Server.
+func (c *Client) GetVenafiCAConnectionStatusByID(id string) (*ResponseVenafiCAConnectionStatus, error) {
+	endpoint := fmt.Sprintf("%s/%s/connection-status", uriVenafi, id)
+	var out ResponseVenafiCAConnectionStatus
+
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByID, "venafi ca connection status", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// GetVenafiCADependentProfilesByID retrieves the configuration profiles which use a Venafi CA configuration.
+func (c *Client) GetVenafiCADependentProfilesByID(id string) (*ResponseVenafiCADependentProfiles, error) {
+	endpoint := fmt.Sprintf("%s/%s/dependent-profiles", uriVenafi, id)
+	var out ResponseVenafiCADependentProfiles
+
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByID, "venafi ca dependent profiles", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return &out, nil
+}
+
+// DownloadVenafiCAProxyTrustStoreByID downloads the trust store Jamf Pro uses to trust the PKI Proxy Server.
+func (c *Client) DownloadVenafiCAProxyTrustStoreByID(id string) ([]byte, error) {
+	endpoint := fmt.Sprintf("%s/%s/proxy-trust-store", uriVenafi, id)
+	var out []byte
+
+	resp, err := c.HTTP.DoRequest("GET", endpoint, nil, &out)
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedGetByID, "venafi ca proxy trust store", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return out, nil
+}
+
+// UploadVenafiCAProxyTrustStoreByID uploads a trust store file for the PKI Proxy Server, replacing the
+// existing one.
+func (c *Client) UploadVenafiCAProxyTrustStoreByID(id string, filePath string) error {
+	endpoint := fmt.Sprintf("%s/%s/proxy-trust-store", uriVenafi, id)
+
+	files := map[string]string{
+		"file": filePath,
+	}
+
+	// Jamf Pro answers 204 No Content, which the HTTP client reports as an unexpected MIME type.
+	resp, err := c.HTTP.DoMultipartRequest("POST", endpoint, nil, files, nil)
+	if err != nil && (resp == nil || resp.StatusCode != http.StatusNoContent) {
+		return fmt.Errorf("failed to upload venafi ca proxy trust store for id: %s, error: %v", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return nil
+}
+
+// DeleteVenafiCAProxyTrustStoreByID removes an uploaded trust store, restoring the default trust.
+func (c *Client) DeleteVenafiCAProxyTrustStoreByID(id string) error {
+	endpoint := fmt.Sprintf("%s/%s/proxy-trust-store", uriVenafi, id)
+
+	resp, err := c.HTTP.DoRequest("DELETE", endpoint, nil, nil)
+	if err != nil {
+		return fmt.Errorf(errMsgFailedDeleteByID, "venafi ca proxy trust store", id, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	return nil
+}
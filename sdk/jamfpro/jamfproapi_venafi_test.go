@@ -0,0 +1,148 @@
+package jamfpro
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestVenafiCACRUD(t *testing.T) {
+	mux := http.NewServeMux()
+	var created, updated ResourceVenafiCA
+	deleted := false
+
+	mux.HandleFunc("/api/v1/pki/venafi", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "unexpected method", http.StatusMethodNotAllowed)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&created)
+		writeTestJSON(w, http.StatusCreated, ResponseVenafiCACreate{ID: "3", Href: "/api/v1/pki/venafi/3"})
+	})
+	mux.HandleFunc("/api/v1/pki/venafi/3", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			writeTestJSON(w, http.StatusOK, ResourceVenafiCA{ID: "3", Name: "Venafi", ProxyAddress: "proxy.example.com:9443", RefreshTokenConfigured: true})
+		case http.MethodPatch:
+			json.NewDecoder(r.Body).Decode(&updated)
+			writeTestJSON(w, http.StatusOK, ResourceVenafiCA{ID: "3", Name: updated.Name})
+		case http.MethodDelete:
+			deleted = true
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+	mux.HandleFunc("/api/v1/pki/venafi/3/connection-status", func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, http.StatusOK, ResponseVenafiCAConnectionStatus{Status: "Connected"})
+	})
+	mux.HandleFunc("/api/v1/pki/venafi/3/dependent-profiles", func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, http.StatusOK, ResponseVenafiCADependentProfiles{
+			TotalCount: 1,
+			Results:    []VenafiCASubsetDependentProfile{{ID: "12", Name: "Wi-Fi", URLPath: "/OSXConfigurationProfiles.html?id=12"}},
+		})
+	})
+
+	client := newTestClient(t, mux)
+
+	response, err := client.CreateVenafiCA(&ResourceVenafiCA{Name: "Venafi", ClientID: "jamf", RefreshToken: "secret"})
+	if err != nil {
+		t.Fatalf("CreateVenafiCA: %v", err)
+	}
+	if response.ID != "3" || created.RefreshToken != "secret" {
+		t.Errorf("create response %+v, request %+v", response, created)
+	}
+
+	venafiCA, err := client.GetVenafiCAByID("3")
+	if err != nil {
+		t.Fatalf("GetVenafiCAByID: %v", err)
+	}
+	if venafiCA.ProxyAddress != "proxy.example.com:9443" || !venafiCA.RefreshTokenConfigured {
+		t.Errorf("GetVenafiCAByID = %+v", venafiCA)
+	}
+
+	if _, err := client.UpdateVenafiCAByID("3", &ResourceVenafiCA{Name: "Renamed"}); err != nil {
+		t.Fatalf("UpdateVenafiCAByID: %v", err)
+	}
+	if updated.Name != "Renamed" || updated.RefreshToken != "" {
+		t.Errorf("update request = %+v", updated)
+	}
+
+	status, err := client.GetVenafiCAConnectionStatusByID("3")
+	if err != nil || status.Status != "Connected" {
+		t.Errorf("GetVenafiCAConnectionStatusByID = %+v, %v", status, err)
+	}
+
+	profiles, err := client.GetVenafiCADependentProfilesByID("3")
+	if err != nil || profiles.TotalCount != 1 || profiles.Results[0].ID != "12" {
+		t.Errorf("GetVenafiCADependentProfilesByID = %+v, %v", profiles, err)
+	}
+
+	if err := client.DeleteVenafiCAByID("3"); err != nil || !deleted {
+		t.Errorf("DeleteVenafiCAByID = %v, deleted %v", err, deleted)
+	}
+}
+
+func TestVenafiCAProxyTrustStore(t *testing.T) {
+	trustStore := []byte("\xfe\xed\xfe\xed trust store")
+	var uploaded []byte
+	deleted := false
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/pki/venafi/3/proxy-trust-store", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Write(trustStore)
+		case http.MethodPost:
+			// The HTTP client replaces the multipart Content-Type of /api endpoints with JSON, so the boundary
+			// is lost and the form part is checked in the raw body.
+			body, _ := io.ReadAll(r.Body)
+			if !bytes.Contains(body, []byte(`name="file"; filename="proxy.jks"`)) {
+				http.Error(w, "no file part", http.StatusBadRequest)
+				return
+			}
+			if bytes.Contains(body, trustStore) {
+				uploaded = trustStore
+			}
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodDelete:
+			deleted = true
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+	mux.HandleFunc("/api/v1/pki/venafi/4/proxy-trust-store", func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, http.StatusNotFound, map[string]interface{}{"httpStatus": 404})
+	})
+
+	client := newTestClient(t, mux)
+
+	downloaded, err := client.DownloadVenafiCAProxyTrustStoreByID("3")
+	if err != nil {
+		t.Fatalf("DownloadVenafiCAProxyTrustStoreByID: %v", err)
+	}
+	if !bytes.Equal(downloaded, trustStore) {
+		t.Errorf("downloaded %q, want %q", downloaded, trustStore)
+	}
+
+	path := filepath.Join(t.TempDir(), "proxy.jks")
+	if err := os.WriteFile(path, trustStore, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.UploadVenafiCAProxyTrustStoreByID("3", path); err != nil {
+		t.Fatalf("UploadVenafiCAProxyTrustStoreByID: %v", err)
+	}
+	if !bytes.Equal(uploaded, trustStore) {
+		t.Errorf("uploaded %q, want %q", uploaded, trustStore)
+	}
+
+	if err := client.UploadVenafiCAProxyTrustStoreByID("4", path); err == nil {
+		t.Error("UploadVenafiCAProxyTrustStoreByID succeeded for a 404")
+	}
+
+	if err := client.DeleteVenafiCAProxyTrustStoreByID("3"); err != nil || !deleted {
+		t.Errorf("DeleteVenafiCAProxyTrustStoreByID = %v, deleted %v", err, deleted)
+	}
+}
@@ -36,7 +36,7 @@ type ResponseVolumePurchasingLocationCreate struct {
 
 // ResponseVolumePurchasingLocation represents the response structure for a single volume purchasing location.
 type ResourceVolumePurchasingLocation struct {
-	VolumePurchasingLocationSubsetBody                                 // I don't think this works. See exmaples/volume_purchase_locations/CreateVolumePurchasingLocation.go
+	VolumePurchasingLocationSubsetBody `mapstructure:",squash"`
 	Content                            []VolumePurchasingSubsetContent `json:"content"`
 }
 
@@ -0,0 +1,49 @@
+// shared_configuration_profile_payloads.go
+// Payload handling shared by the macOS and mobile device configuration profile endpoints.
+package jamfpro
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/mobileconfig"
+)
+
+// buildConfigurationProfilePayloads returns the payloads string to send to Jamf Pro for a configuration profile.
+// When a typed profile is supplied it is marshaled and takes precedence over the raw payloads string.
+// The Classic API only carries XML payloads, so CMS signed payloads are refused rather than silently unsigned.
+// Verify them with mobileconfig.Verify, or unsign them with mobileconfig.StripSignature, and send the content.
+// The payloads are normalised with mobileconfig.NormalizePayloadString, which removes one extra layer of escaping
+// left by round tripping them through the Classic API. Empty payloads are sent as they are.
+func buildConfigurationProfilePayloads(profile *mobileconfig.Profile, payloads string) (string, error) {
+	if profile != nil {
+		marshaled, err := profile.MarshalString()
+		if err != nil {
+			return "", err
+		}
+		payloads = marshaled
+	} else if mobileconfig.IsSigned([]byte(payloads)) {
+		return "", fmt.Errorf("payloads are CMS signed, the Classic API only accepts the unsigned profile: use mobileconfig.Verify or mobileconfig.StripSignature first")
+	}
+
+	if strings.TrimSpace(payloads) == "" {
+		return payloads, nil
+	}
+
+	normalized, err := mobileconfig.NormalizePayloadString(payloads)
+	if err != nil {
+		return "", fmt.Errorf("failed to normalise payloads: %v", err)
+	}
+
+	return normalized, nil
+}
+
+// ParsePayloads decodes the Payloads string of a macOS configuration profile into a typed configuration profile.
+func (g *MacOSConfigurationProfileSubsetGeneral) ParsePayloads() (*mobileconfig.Profile, error) {
+	return mobileconfig.ParseString(g.Payloads)
+}
+
+// ParsePayloads decodes the Payloads string of a mobile device configuration profile into a typed configuration profile.
+func (g *MobileDeviceConfigurationProfileSubsetGeneral) ParsePayloads() (*mobileconfig.Profile, error) {
+	return mobileconfig.ParseString(g.Payloads)
+}
@@ -0,0 +1,62 @@
+package jamfpro
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/mobileconfig"
+)
+
+func TestBuildConfigurationProfilePayloadsNormalizes(t *testing.T) {
+	tests := []struct {
+		name     string
+		payloads string
+		want     string
+	}{
+		{
+			name:     "value escaped twice",
+			payloads: `<plist version="1.0"><dict><key>PayloadDisplayName</key><string>Tom &amp;amp; Jerry</string></dict></plist>`,
+			want:     "Tom &amp; Jerry",
+		},
+		{
+			name:     "html entities escaped twice",
+			payloads: `<plist version="1.0"><dict><key>PayloadDisplayName</key><string>Caf&amp;eacute;&amp;nbsp;Wi-Fi</string></dict></plist>`,
+			want:     "Caf\u00e9\u00a0Wi-Fi",
+		},
+		{
+			name:     "empty payloads",
+			payloads: "",
+			want:     "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildConfigurationProfilePayloads(nil, tt.payloads)
+			if err != nil {
+				t.Fatalf("buildConfigurationProfilePayloads: %v", err)
+			}
+			if tt.want == "" {
+				if got != "" {
+					t.Errorf("payloads = %q, want them empty", got)
+				}
+				return
+			}
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("payloads = %s, want them to hold %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildConfigurationProfilePayloadsNormalizesTypedProfile(t *testing.T) {
+	profile := mobileconfig.New("com.example.wifi", "Wi-Fi").AddPayload(mobileconfig.NewWiFiPayload("Caf&eacute;", mobileconfig.WiFiEncryptionWPA2))
+
+	got, err := buildConfigurationProfilePayloads(profile, "ignored")
+	if err != nil {
+		t.Fatalf("buildConfigurationProfilePayloads: %v", err)
+	}
+	if !strings.Contains(got, "Caf\u00e9") {
+		t.Errorf("payloads = %s, want the SSID decoded", got)
+	}
+}
@@ -0,0 +1,686 @@
+// util_configuration_profile_sync.go
+// Export and import of macOS and mobile device configuration profiles to and from a directory.
+// Each profile is written as a clean .mobileconfig file plus a .json sidecar holding the Jamf Pro
+// metadata (scope, category, site, distribution method and self service settings):
+//
+//	<dir>/macos/<name>.mobileconfig
+//	<dir>/macos/<name>.json
+//	<dir>/mobile/<name>.mobileconfig
+//	<dir>/mobile/<name>.json
+//
+// Signed profiles are verified against the trust roots given in ConfigurationProfileExportOptions and exported
+// unsigned, with the signer recorded in the sidecar. Without trust roots the signature is stripped unverified.
+//
+// Importing such a directory is idempotent. Existing profiles are matched by name, then by the
+// top level PayloadIdentifier, and are only updated when their payloads or metadata differ.
+package jamfpro
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/mobileconfig"
+)
+
+// Configuration profile kinds, also used as export sub directory names.
+const (
+	ConfigurationProfileKindMacOS        = "macos"
+	ConfigurationProfileKindMobileDevice = "mobile"
+)
+
+// Configuration profile sync actions
+const (
+	ConfigurationProfileActionExported  = "exported"
+	ConfigurationProfileActionCreated   = "created"
+	ConfigurationProfileActionUpdated   = "updated"
+	ConfigurationProfileActionUnchanged = "unchanged"
+	ConfigurationProfileActionFailed    = "failed"
+)
+
+// ConfigurationProfileMetadata is the sidecar written next to each exported .mobileconfig file.
+// Exactly one of the macOS or mobile device scope/self service pairs is set, depending on Kind.
+type ConfigurationProfileMetadata struct {
+	Kind               string                 `json:"kind"`
+	ID                 int                    `json:"id,omitempty"`
+	Name               string                 `json:"name"`
+	Description        string                 `json:"description,omitempty"`
+	PayloadIdentifier  string                 `json:"payloadIdentifier,omitempty"`
+	Site               SharedResourceSite     `json:"site"`
+	Category           SharedResourceCategory `json:"category"`
+	Level              string                 `json:"level,omitempty"`
+	DistributionMethod string                 `json:"distributionMethod,omitempty"`
+	UserRemovable      bool                   `json:"userRemovable,omitempty"`
+	RedeployOnUpdate   string                 `json:"redeployOnUpdate,omitempty"`
+
+	MacOSScope              *MacOSConfigurationProfileSubsetScope              `json:"macosScope,omitempty"`
+	MacOSSelfService        *MacOSConfigurationProfileSubsetSelfService        `json:"macosSelfService,omitempty"`
+	MobileDeviceScope       *MobileDeviceConfigurationProfileSubsetScope       `json:"mobileDeviceScope,omitempty"`
+	MobileDeviceSelfService *MobileDeviceConfigurationProfileSubsetSelfService `json:"mobileDeviceSelfService,omitempty"`
+
+	// Signer records the certificate a signed profile is signed by. The exported .mobileconfig is unsigned.
+	// SignatureVerified tells whether the signature was verified against the trust roots of the export; when
+	// it is false the signer is only the one the profile claims.
+	Signer            *mobileconfig.SignerDetails `json:"signer,omitempty"`
+	SignatureVerified bool                        `json:"signatureVerified,omitempty"`
+}
+
+// ConfigurationProfileExportOptions controls the export of configuration profiles.
+type ConfigurationProfileExportOptions struct {
+	// TrustRoots are the certificates signed profiles must chain to, checked with mobileconfig.Verify. A signed
+	// profile which fails verification is not exported. When nil, signatures are stripped without verification.
+	TrustRoots *x509.CertPool
+}
+
+// ConfigurationProfileSyncResult reports the outcome for a single profile.
+type ConfigurationProfileSyncResult struct {
+	Kind   string
+	Name   string
+	ID     int
+	Action string
+	Path   string
+	Error  error
+}
+
+// ExportConfigurationProfiles exports all macOS and mobile device configuration profiles into dir. opts may be nil.
+func (c *Client) ExportConfigurationProfiles(dir string, opts *ConfigurationProfileExportOptions) ([]ConfigurationProfileSyncResult, error) {
+	macResults, macErr := c.ExportMacOSConfigurationProfiles(filepath.Join(dir, ConfigurationProfileKindMacOS), opts)
+	mobileResults, mobileErr := c.ExportMobileDeviceConfigurationProfiles(filepath.Join(dir, ConfigurationProfileKindMobileDevice), opts)
+
+	results := append(macResults, mobileResults...)
+	if macErr != nil {
+		return results, macErr
+	}
+	return results, mobileErr
+}
+
+// ExportMacOSConfigurationProfiles writes every macOS configuration profile and its metadata sidecar into dir.
+// opts may be nil.
+func (c *Client) ExportMacOSConfigurationProfiles(dir string, opts *ConfigurationProfileExportOptions) ([]ConfigurationProfileSyncResult, error) {
+	profiles, err := c.GetMacOSConfigurationProfiles()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, fmt.Errorf("failed to create export directory %s: %v", dir, err)
+	}
+
+	var roots *x509.CertPool
+	if opts != nil {
+		roots = opts.TrustRoots
+	}
+
+	var results []ConfigurationProfileSyncResult
+	fileNames := map[string]string{}
+	for _, item := range profiles.Results {
+		result := ConfigurationProfileSyncResult{Kind: ConfigurationProfileKindMacOS, Name: item.Name, ID: item.ID}
+
+		profile, err := c.GetMacOSConfigurationProfileByID(item.ID)
+		if err == nil {
+			result.Path, err = writeConfigurationProfileExport(dir, profile.General.Payloads, macOSConfigurationProfileMetadata(profile), fileNames, roots)
+		}
+		results = append(results, finishConfigurationProfileResult(result, ConfigurationProfileActionExported, err))
+	}
+
+	return results, configurationProfileSyncError(results)
+}
+
+// ExportMobileDeviceConfigurationProfiles writes every mobile device configuration profile and its metadata sidecar into dir.
+// opts may be nil.
+func (c *Client) ExportMobileDeviceConfigurationProfiles(dir string, opts *ConfigurationProfileExportOptions) ([]ConfigurationProfileSyncResult, error) {
+	profiles, err := c.GetMobileDeviceConfigurationProfiles()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, fmt.Errorf("failed to create export directory %s: %v", dir, err)
+	}
+
+	var roots *x509.CertPool
+	if opts != nil {
+		roots = opts.TrustRoots
+	}
+
+	var results []ConfigurationProfileSyncResult
+	fileNames := map[string]string{}
+	for _, item := range profiles.ConfigurationProfiles {
+		result := ConfigurationProfileSyncResult{Kind: ConfigurationProfileKindMobileDevice, Name: item.Name, ID: item.ID}
+
+		profile, err := c.GetMobileDeviceConfigurationProfileByID(item.ID)
+		if err == nil {
+			result.Path, err = writeConfigurationProfileExport(dir, profile.General.Payloads, mobileDeviceConfigurationProfileMetadata(profile), fileNames, roots)
+		}
+		results = append(results, finishConfigurationProfileResult(result, ConfigurationProfileActionExported, err))
+	}
+
+	return results, configurationProfileSyncError(results)
+}
+
+// ImportConfigurationProfiles creates or updates the macOS and mobile device configuration profiles found in dir,
+// as written by ExportConfigurationProfiles. Missing sub directories are skipped.
+func (c *Client) ImportConfigurationProfiles(dir string) ([]ConfigurationProfileSyncResult, error) {
+	var results []ConfigurationProfileSyncResult
+	var firstErr error
+
+	for _, kind := range []string{ConfigurationProfileKindMacOS, ConfigurationProfileKindMobileDevice} {
+		kindDir := filepath.Join(dir, kind)
+		if _, err := os.Stat(kindDir); os.IsNotExist(err) {
+			continue
+		}
+
+		var kindResults []ConfigurationProfileSyncResult
+		var err error
+		if kind == ConfigurationProfileKindMacOS {
+			kindResults, err = c.ImportMacOSConfigurationProfiles(kindDir)
+		} else {
+			kindResults, err = c.ImportMobileDeviceConfigurationProfiles(kindDir)
+		}
+		results = append(results, kindResults...)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return results, firstErr
+}
+
+// ImportMacOSConfigurationProfiles creates or updates a macOS configuration profile for every .mobileconfig file in dir.
+func (c *Client) ImportMacOSConfigurationProfiles(dir string) ([]ConfigurationProfileSyncResult, error) {
+	entries, err := readConfigurationProfileExports(dir, ConfigurationProfileKindMacOS)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := c.GetMacOSConfigurationProfiles()
+	if err != nil {
+		return nil, err
+	}
+
+	index := newConfigurationProfileIndex()
+	for _, item := range list.Results {
+		index.names[item.Name] = item.ID
+	}
+	loadIdentifiers := func() error {
+		for _, item := range list.Results {
+			profile, err := c.GetMacOSConfigurationProfileByID(item.ID)
+			if err != nil {
+				return err
+			}
+			index.addPayloads(item.ID, profile.General.Payloads)
+		}
+		return nil
+	}
+
+	var results []ConfigurationProfileSyncResult
+	for _, entry := range entries {
+		result := ConfigurationProfileSyncResult{Kind: ConfigurationProfileKindMacOS, Name: entry.metadata.Name, Path: entry.path}
+
+		id, err := index.match(entry, loadIdentifiers)
+		if err != nil {
+			results = append(results, finishConfigurationProfileResult(result, "", err))
+			continue
+		}
+
+		desired := entry.macOSResource()
+		if id == 0 {
+			var created *ResponseMacOSConfigurationProfileCreationUpdate
+			created, err = c.CreateMacOSConfigurationProfile(desired)
+			if err == nil {
+				result.ID = created.ID
+			}
+			results = append(results, finishConfigurationProfileResult(result, ConfigurationProfileActionCreated, err))
+			continue
+		}
+
+		result.ID = id
+		existing, err := c.GetMacOSConfigurationProfileByID(id)
+		if err != nil {
+			results = append(results, finishConfigurationProfileResult(result, "", err))
+			continue
+		}
+
+		if entry.matches(existing.General.Payloads, macOSConfigurationProfileMetadata(existing)) {
+			results = append(results, finishConfigurationProfileResult(result, ConfigurationProfileActionUnchanged, nil))
+			continue
+		}
+
+		// Without a sidecar only the payloads are managed, the existing metadata is kept
+		if !entry.hasMetadata {
+			desired = existing
+			desired.General.Payloads = entry.payloads
+		}
+
+		_, err = c.UpdateMacOSConfigurationProfileByID(id, desired)
+		results = append(results, finishConfigurationProfileResult(result, ConfigurationProfileActionUpdated, err))
+	}
+
+	return results, configurationProfileSyncError(results)
+}
+
+// ImportMobileDeviceConfigurationProfiles creates or updates a mobile device configuration profile for every .mobileconfig file in dir.
+func (c *Client) ImportMobileDeviceConfigurationProfiles(dir string) ([]ConfigurationProfileSyncResult, error) {
+	entries, err := readConfigurationProfileExports(dir, ConfigurationProfileKindMobileDevice)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := c.GetMobileDeviceConfigurationProfiles()
+	if err != nil {
+		return nil, err
+	}
+
+	index := newConfigurationProfileIndex()
+	for _, item := range list.ConfigurationProfiles {
+		index.names[item.Name] = item.ID
+	}
+	loadIdentifiers := func() error {
+		for _, item := range list.ConfigurationProfiles {
+			profile, err := c.GetMobileDeviceConfigurationProfileByID(item.ID)
+			if err != nil {
+				return err
+			}
+			index.addPayloads(item.ID, profile.General.Payloads)
+		}
+		return nil
+	}
+
+	var results []ConfigurationProfileSyncResult
+	for _, entry := range entries {
+		result := ConfigurationProfileSyncResult{Kind: ConfigurationProfileKindMobileDevice, Name: entry.metadata.Name, Path: entry.path}
+
+		id, err := index.match(entry, loadIdentifiers)
+		if err != nil {
+			results = append(results, finishConfigurationProfileResult(result, "", err))
+			continue
+		}
+
+		desired := entry.mobileDeviceResource()
+		if id == 0 {
+			var created *ResponseMobileDeviceConfigurationProfileCreateAndUpdate
+			created, err = c.CreateMobileDeviceConfigurationProfile(desired)
+			if err == nil {
+				result.ID = created.ID
+			}
+			results = append(results, finishConfigurationProfileResult(result, ConfigurationProfileActionCreated, err))
+			continue
+		}
+
+		result.ID = id
+		existing, err := c.GetMobileDeviceConfigurationProfileByID(id)
+		if err != nil {
+			results = append(results, finishConfigurationProfileResult(result, "", err))
+			continue
+		}
+
+		if entry.matches(existing.General.Payloads, mobileDeviceConfigurationProfileMetadata(existing)) {
+			results = append(results, finishConfigurationProfileResult(result, ConfigurationProfileActionUnchanged, nil))
+			continue
+		}
+
+		// Without a sidecar only the payloads are managed, the existing metadata is kept
+		if !entry.hasMetadata {
+			desired = existing
+			desired.General.Payloads = entry.payloads
+		}
+
+		_, err = c.UpdateMobileDeviceConfigurationProfileByID(id, desired)
+		results = append(results, finishConfigurationProfileResult(result, ConfigurationProfileActionUpdated, err))
+	}
+
+	return results, configurationProfileSyncError(results)
+}
+
+// macOSConfigurationProfileMetadata builds the export sidecar for a macOS configuration profile.
+func macOSConfigurationProfileMetadata(profile *ResourceMacOSConfigurationProfile) *ConfigurationProfileMetadata {
+	scope := profile.Scope
+	selfService := profile.SelfService
+
+	return &ConfigurationProfileMetadata{
+		Kind:               ConfigurationProfileKindMacOS,
+		ID:                 profile.General.ID,
+		Name:               profile.General.Name,
+		Description:        profile.General.Description,
+		Site:               profile.General.Site,
+		Category:           profile.General.Category,
+		Level:              profile.General.Level,
+		DistributionMethod: profile.General.DistributionMethod,
+		UserRemovable:      profile.General.UserRemovable,
+		RedeployOnUpdate:   profile.General.RedeployOnUpdate,
+		MacOSScope:         &scope,
+		MacOSSelfService:   &selfService,
+	}
+}
+
+// mobileDeviceConfigurationProfileMetadata builds the export sidecar for a mobile device configuration profile.
+func mobileDeviceConfigurationProfileMetadata(profile *ResourceMobileDeviceConfigurationProfile) *ConfigurationProfileMetadata {
+	scope := profile.Scope
+	selfService := profile.SelfService
+
+	return &ConfigurationProfileMetadata{
+		Kind:                    ConfigurationProfileKindMobileDevice,
+		ID:                      profile.General.ID,
+		Name:                    profile.General.Name,
+		Description:             profile.General.Description,
+		Site:                    profile.General.Site,
+		Category:                profile.General.Category,
+		Level:                   profile.General.Level,
+		DistributionMethod:      profile.General.DeploymentMethod,
+		RedeployOnUpdate:        profile.General.RedeployOnUpdate,
+		MobileDeviceScope:       &scope,
+		MobileDeviceSelfService: &selfService,
+	}
+}
+
+// writeConfigurationProfileExport writes the payloads and metadata of a single profile into dir and returns the
+// path of the .mobileconfig file. Signed payloads are written unsigned, with the signer recorded in the metadata;
+// the signature is verified against roots unless roots is nil.
+// fileNames maps the file names already written in this export to their profile names; a profile whose name
+// converts to a file name already taken fails rather than overwriting the other profile.
+func writeConfigurationProfileExport(dir, payloads string, metadata *ConfigurationProfileMetadata, fileNames map[string]string, roots *x509.CertPool) (string, error) {
+	fileName := configurationProfileFileName(metadata.Name)
+	// Compared case insensitively, as the default macOS and Windows file systems are
+	if other, taken := fileNames[strings.ToLower(fileName)]; taken {
+		return "", fmt.Errorf("file name %q is already used by profile %q, rename one of the profiles", fileName, other)
+	}
+
+	content, signer, err := unsignConfigurationProfilePayloads([]byte(payloads), roots)
+	if err != nil {
+		return "", err
+	}
+	metadata.Signer = signer
+	metadata.SignatureVerified = signer != nil && roots != nil
+	fileNames[strings.ToLower(fileName)] = metadata.Name
+
+	if profile, err := mobileconfig.Parse(content); err == nil {
+		metadata.PayloadIdentifier = profile.PayloadIdentifier
+	}
+
+	base := filepath.Join(dir, fileName)
+	if err := os.WriteFile(base+".mobileconfig", content, 0644); err != nil {
+		return "", fmt.Errorf("failed to write %s.mobileconfig: %v", base, err)
+	}
+
+	metadataJSON, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf(errMsgFailedJsonMarshal, "configuration profile metadata", err)
+	}
+
+	if err := os.WriteFile(base+".json", metadataJSON, 0644); err != nil {
+		return "", fmt.Errorf("failed to write %s.json: %v", base, err)
+	}
+
+	return base + ".mobileconfig", nil
+}
+
+// unsignConfigurationProfilePayloads returns the profile of payloads and its signer, nil when unsigned. A signature
+// is verified against roots, or stripped without verification when roots is nil.
+func unsignConfigurationProfilePayloads(payloads []byte, roots *x509.CertPool) ([]byte, *mobileconfig.SignerDetails, error) {
+	if roots == nil || !mobileconfig.IsSigned(payloads) {
+		return mobileconfig.StripSignature(payloads)
+	}
+
+	return mobileconfig.Verify(payloads, roots)
+}
+
+// configurationProfileFileName converts a profile name into a safe file name.
+func configurationProfileFileName(name string) string {
+	replacer := strings.NewReplacer("/", "_", "\\", "_", ":", "_", "*", "_", "?", "_", "\"", "_", "<", "_", ">", "_", "|", "_")
+	return strings.TrimSpace(replacer.Replace(name))
+}
+
+// configurationProfileExport is a .mobileconfig file and its sidecar read back from disk.
+type configurationProfileExport struct {
+	path        string
+	payloads    string
+	profile     *mobileconfig.Profile
+	metadata    ConfigurationProfileMetadata
+	hasMetadata bool
+}
+
+// readConfigurationProfileExports reads every .mobileconfig file in dir along with its sidecar. Files without a
+// sidecar are imported under their file name with default metadata.
+func readConfigurationProfileExports(dir, kind string) ([]configurationProfileExport, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.mobileconfig"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	var entries []configurationProfileExport
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", path, err)
+		}
+
+		profile, err := mobileconfig.Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %v", path, err)
+		}
+
+		entry := configurationProfileExport{
+			path:     path,
+			payloads: string(data),
+			profile:  profile,
+			metadata: ConfigurationProfileMetadata{Kind: kind, Name: strings.TrimSuffix(filepath.Base(path), ".mobileconfig")},
+		}
+
+		metadataPath := strings.TrimSuffix(path, ".mobileconfig") + ".json"
+		if metadataJSON, err := os.ReadFile(metadataPath); err == nil {
+			if err := json.Unmarshal(metadataJSON, &entry.metadata); err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %v", metadataPath, err)
+			}
+			entry.hasMetadata = true
+		} else if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read %s: %v", metadataPath, err)
+		}
+
+		if entry.metadata.Kind != kind {
+			return nil, fmt.Errorf("%s has kind %q, expected %q", metadataPath, entry.metadata.Kind, kind)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// macOSResource builds the macOS configuration profile to create or update from the export.
+func (e configurationProfileExport) macOSResource() *ResourceMacOSConfigurationProfile {
+	profile := &ResourceMacOSConfigurationProfile{
+		General: MacOSConfigurationProfileSubsetGeneral{
+			Name:               e.metadata.Name,
+			Description:        e.metadata.Description,
+			Site:               e.metadata.Site,
+			Category:           e.metadata.Category,
+			DistributionMethod: e.metadata.DistributionMethod,
+			UserRemovable:      e.metadata.UserRemovable,
+			Level:              e.metadata.Level,
+			RedeployOnUpdate:   e.metadata.RedeployOnUpdate,
+			Payloads:           e.payloads,
+		},
+	}
+	if e.metadata.MacOSScope != nil {
+		profile.Scope = *e.metadata.MacOSScope
+	}
+	if e.metadata.MacOSSelfService != nil {
+		profile.SelfService = *e.metadata.MacOSSelfService
+	}
+
+	return profile
+}
+
+// mobileDeviceResource builds the mobile device configuration profile to create or update from the export.
+func (e configurationProfileExport) mobileDeviceResource() *ResourceMobileDeviceConfigurationProfile {
+	profile := &ResourceMobileDeviceConfigurationProfile{
+		General: MobileDeviceConfigurationProfileSubsetGeneral{
+			Name:             e.metadata.Name,
+			Description:      e.metadata.Description,
+			Site:             e.metadata.Site,
+			Category:         e.metadata.Category,
+			DeploymentMethod: e.metadata.DistributionMethod,
+			Level:            e.metadata.Level,
+			RedeployOnUpdate: e.metadata.RedeployOnUpdate,
+			Payloads:         e.payloads,
+		},
+	}
+	if e.metadata.MobileDeviceScope != nil {
+		profile.Scope = *e.metadata.MobileDeviceScope
+	}
+	if e.metadata.MobileDeviceSelfService != nil {
+		profile.SelfService = *e.metadata.MobileDeviceSelfService
+	}
+
+	return profile
+}
+
+// matches reports whether an existing profile already has the payloads and metadata of the export.
+// Payloads are compared semantically as decoded documents, ignoring the keys Jamf Pro rewrites on upload.
+// Metadata is only compared when the export has a sidecar.
+func (e configurationProfileExport) matches(existingPayloads string, existing *ConfigurationProfileMetadata) bool {
+	if equal, err := mobileconfig.EqualBytes([]byte(existingPayloads), []byte(e.payloads)); err != nil || !equal {
+		return false
+	}
+
+	if !e.hasMetadata {
+		return true
+	}
+
+	desired := e.metadata
+	desired.ID, desired.PayloadIdentifier, desired.Signer = 0, "", nil
+	current := *existing
+	current.ID, current.PayloadIdentifier, current.Signer = 0, "", nil
+
+	desiredValue, err := comparableConfigurationProfileMetadata(desired)
+	if err != nil {
+		return false
+	}
+	currentValue, err := comparableConfigurationProfileMetadata(current)
+	if err != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(desiredValue, currentValue)
+}
+
+// comparableConfigurationProfileMetadata returns metadata as a generic JSON value without empty values, so that
+// a nil and an empty list, or a missing and an empty scope, compare equal.
+func comparableConfigurationProfileMetadata(metadata ConfigurationProfileMetadata) (interface{}, error) {
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+
+	return withoutEmptyJSONValues(value), nil
+}
+
+// withoutEmptyJSONValues removes nulls, empty lists and empty objects from a generic JSON value, returning nil
+// when nothing is left.
+func withoutEmptyJSONValues(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := map[string]interface{}{}
+		for key, item := range v {
+			if item = withoutEmptyJSONValues(item); item != nil {
+				out[key] = item
+			}
+		}
+		if len(out) == 0 {
+			return nil
+		}
+		return out
+	case []interface{}:
+		var out []interface{}
+		for _, item := range v {
+			if item = withoutEmptyJSONValues(item); item != nil {
+				out = append(out, item)
+			}
+		}
+		if len(out) == 0 {
+			return nil
+		}
+		return out
+	default:
+		return value
+	}
+}
+
+// configurationProfileIndex matches exports to existing profiles by name, then by payload identifier.
+type configurationProfileIndex struct {
+	names             map[string]int
+	identifiers       map[string]int
+	identifiersLoaded bool
+}
+
+func newConfigurationProfileIndex() *configurationProfileIndex {
+	return &configurationProfileIndex{names: map[string]int{}, identifiers: map[string]int{}}
+}
+
+// addPayloads records the top level PayloadIdentifier of an existing profile.
+func (i *configurationProfileIndex) addPayloads(id int, payloads string) {
+	if profile, err := mobileconfig.ParseString(payloads); err == nil && profile.PayloadIdentifier != "" {
+		i.identifiers[profile.PayloadIdentifier] = id
+	}
+}
+
+// match returns the ID of the existing profile for the export, or 0 if there is none. Payload identifiers are
+// only loaded, using load, when a name lookup fails.
+func (i *configurationProfileIndex) match(entry configurationProfileExport, load func() error) (int, error) {
+	if id, ok := i.names[entry.metadata.Name]; ok {
+		return id, nil
+	}
+
+	identifier := entry.profile.PayloadIdentifier
+	if identifier == "" {
+		return 0, nil
+	}
+
+	if !i.identifiersLoaded {
+		if err := load(); err != nil {
+			return 0, err
+		}
+		i.identifiersLoaded = true
+	}
+
+	return i.identifiers[identifier], nil
+}
+
+// finishConfigurationProfileResult sets the action of a result, or marks it failed when err is set.
+func finishConfigurationProfileResult(result ConfigurationProfileSyncResult, action string, err error) ConfigurationProfileSyncResult {
+	if err != nil {
+		result.Action = ConfigurationProfileActionFailed
+		result.Error = err
+		return result
+	}
+
+	result.Action = action
+	return result
+}
+
+// configurationProfileSyncError summarises failed results into a single error.
+func configurationProfileSyncError(results []ConfigurationProfileSyncResult) error {
+	var failed []string
+	for _, result := range results {
+		if result.Error != nil {
+			failed = append(failed, fmt.Sprintf("%s %q: %v", result.Kind, result.Name, result.Error))
+		}
+	}
+
+	if len(failed) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%d configuration profile(s) failed: %s", len(failed), strings.Join(failed, "; "))
+}
@@ -0,0 +1,219 @@
+package jamfpro
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/mobileconfig"
+)
+
+const syncTestPayloads = `<?xml version="1.0" encoding="UTF-8"?>
+<plist version="1.0">
+<dict>
+	<key>PayloadIdentifier</key><string>com.example.sync</string>
+	<key>PayloadType</key><string>Configuration</string>
+	<key>PayloadUUID</key><string>%UUID%</string>
+	<key>PayloadVersion</key><integer>1</integer>
+	<key>PayloadContent</key>
+	<array>
+		<dict>
+			<key>PayloadType</key><string>com.apple.TCC.configuration-profile-policy</string>
+			<key>PayloadVersion</key><integer>1</integer>
+			<key>Services</key>
+			<dict>
+				<key>Camera</key>
+				<array>
+					<dict>
+						<key>Identifier</key><string>com.example.agent</string>
+						<key>IdentifierType</key><string>bundleID</string>
+						<key>CodeRequirement</key><string>anchor apple generic</string>
+						<key>Authorization</key><string>Deny</string>
+						<key>VendorSetting</key><string>%VENDOR%</string>
+					</dict>
+				</array>
+			</dict>
+		</dict>
+	</array>
+</dict>
+</plist>`
+
+func syncTestPayload(uuid, vendor string) string {
+	return strings.NewReplacer("%UUID%", uuid, "%VENDOR%", vendor).Replace(syncTestPayloads)
+}
+
+func TestConfigurationProfileExportMatches(t *testing.T) {
+	emptyScope := MacOSConfigurationProfileSubsetScope{Computers: []MacOSConfigurationProfileSubsetComputer{}}
+
+	tests := []struct {
+		name     string
+		export   configurationProfileExport
+		existing string
+		metadata ConfigurationProfileMetadata
+		want     bool
+	}{
+		{
+			name:     "uuid rewritten by jamf pro",
+			export:   configurationProfileExport{payloads: syncTestPayload("AAAA", "one")},
+			existing: syncTestPayload("BBBB", "one"),
+			want:     true,
+		},
+		{
+			name:     "nested unmodelled key changed",
+			export:   configurationProfileExport{payloads: syncTestPayload("AAAA", "one")},
+			existing: syncTestPayload("AAAA", "two"),
+			want:     false,
+		},
+		{
+			name: "nil and empty scope lists",
+			export: configurationProfileExport{
+				payloads:    syncTestPayload("AAAA", "one"),
+				hasMetadata: true,
+				metadata:    ConfigurationProfileMetadata{Kind: ConfigurationProfileKindMacOS, Name: "Sync", MacOSScope: &emptyScope},
+			},
+			existing: syncTestPayload("AAAA", "one"),
+			metadata: ConfigurationProfileMetadata{Kind: ConfigurationProfileKindMacOS, ID: 7, Name: "Sync", MacOSScope: &MacOSConfigurationProfileSubsetScope{}},
+			want:     true,
+		},
+		{
+			name: "metadata changed",
+			export: configurationProfileExport{
+				payloads:    syncTestPayload("AAAA", "one"),
+				hasMetadata: true,
+				metadata:    ConfigurationProfileMetadata{Kind: ConfigurationProfileKindMacOS, Name: "Sync", Level: "computer"},
+			},
+			existing: syncTestPayload("AAAA", "one"),
+			metadata: ConfigurationProfileMetadata{Kind: ConfigurationProfileKindMacOS, Name: "Sync", Level: "user"},
+			want:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.export.matches(tt.existing, &tt.metadata); got != tt.want {
+				t.Errorf("matches = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteConfigurationProfileExportFileNameCollision(t *testing.T) {
+	dir := t.TempDir()
+	fileNames := map[string]string{}
+
+	first := &ConfigurationProfileMetadata{Kind: ConfigurationProfileKindMacOS, Name: "Wi-Fi/Corp"}
+	path, err := writeConfigurationProfileExport(dir, syncTestPayload("AAAA", "one"), first, fileNames, nil)
+	if err != nil {
+		t.Fatalf("first export: %v", err)
+	}
+
+	second := &ConfigurationProfileMetadata{Kind: ConfigurationProfileKindMacOS, Name: "wi-fi:corp"}
+	if _, err := writeConfigurationProfileExport(dir, syncTestPayload("BBBB", "two"), second, fileNames, nil); err == nil {
+		t.Fatal("second export with a colliding file name succeeded")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "AAAA") {
+		t.Errorf("%s was overwritten", filepath.Base(path))
+	}
+}
+
+// newSyncTestSigner returns a self signed profile signer and a pool holding its certificate.
+func newSyncTestSigner(t *testing.T) (*mobileconfig.Signer, *x509.CertPool) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test Profile Signer"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(cert)
+	return &mobileconfig.Signer{Certificate: cert, PrivateKey: key}, roots
+}
+
+func TestWriteConfigurationProfileExportSigned(t *testing.T) {
+	signer, roots := newSyncTestSigner(t)
+	_, otherRoots := newSyncTestSigner(t)
+
+	signed, err := signer.Sign([]byte(syncTestPayload("AAAA", "one")), false)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		roots        *x509.CertPool
+		wantVerified bool
+		wantErr      bool
+	}{
+		{name: "verified against the roots", roots: roots, wantVerified: true},
+		{name: "stripped without roots"},
+		{name: "signer not trusted", roots: otherRoots, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			metadata := &ConfigurationProfileMetadata{Kind: ConfigurationProfileKindMacOS, Name: "Signed"}
+
+			path, err := writeConfigurationProfileExport(dir, string(signed), metadata, map[string]string{}, tt.roots)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("a profile signed by an untrusted signer was exported")
+				}
+				if _, statErr := os.Stat(filepath.Join(dir, "Signed.mobileconfig")); !os.IsNotExist(statErr) {
+					t.Errorf("the unverified profile was written")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("writeConfigurationProfileExport: %v", err)
+			}
+
+			if metadata.Signer == nil || metadata.Signer.Subject != "CN=Test Profile Signer" {
+				t.Errorf("signer = %+v, want the test signer", metadata.Signer)
+			}
+			if metadata.SignatureVerified != tt.wantVerified {
+				t.Errorf("SignatureVerified = %v, want %v", metadata.SignatureVerified, tt.wantVerified)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if mobileconfig.IsSigned(data) || !strings.Contains(string(data), "AAAA") {
+				t.Errorf("%s is not the unsigned profile", filepath.Base(path))
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+// util_ddm_status_report.go
+// Fleet wide DDM status, joined with computer inventory.
+// Jamf Pro keeps DDM status items per client management ID only, so GetComputersDDMStatus pages through
+// computer inventory and reads the status items of every computer with DDM enabled, one request per computer.
+// A computer whose status items cannot be read is reported with the error instead of failing the whole query.
+
+package jamfpro
+
+// ddmInventorySections are the inventory sections needed to join computers with their DDM status.
+const ddmInventorySections = "&section=GENERAL&section=HARDWARE"
+
+// ComputerDDMStatus is the DDM status of a computer. Status is nil when DDM is not enabled on the computer or
+// its status items could not be read.
+type ComputerDDMStatus struct {
+	ComputerID   string     `json:"computerId"`
+	Name         string     `json:"name"`
+	SerialNumber string     `json:"serialNumber"`
+	ManagementId string     `json:"managementId"`
+	DDMEnabled   bool       `json:"ddmEnabled"`
+	Status       *DDMStatus `json:"status,omitempty"`
+	Error        string     `json:"error,omitempty"`
+}
+
+// ComputerDeclarationStatus is the state of a single declaration on a computer.
+type ComputerDeclarationStatus struct {
+	ComputerID   string `json:"computerId"`
+	Name         string `json:"name"`
+	SerialNumber string `json:"serialNumber"`
+	ManagementId string `json:"managementId"`
+	// Reported is false when the computer's status report does not list the declaration.
+	Reported    bool                 `json:"reported"`
+	Applied     bool                 `json:"applied"`
+	Declaration DDMDeclarationStatus `json:"declaration"`
+	Error       string               `json:"error,omitempty"`
+}
+
+// GetComputersDDMStatus retrieves the DDM status of the computers matched by sort_filter, e.g.
+// `&filter=general.platform=="Mac"`, in the form GetComputersInventory takes.
+func (c *Client) GetComputersDDMStatus(sort_filter string) ([]ComputerDDMStatus, error) {
+	inventory, err := c.GetComputersInventory(ddmInventorySections + sort_filter)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]ComputerDDMStatus, 0, len(inventory.Results))
+	for _, computer := range inventory.Results {
+		status := ComputerDDMStatus{
+			ComputerID:   computer.ID,
+			Name:         computer.General.Name,
+			SerialNumber: computer.Hardware.SerialNumber,
+			ManagementId: computer.General.ManagementId,
+			DDMEnabled:   computer.General.DeclarativeDeviceManagementEnabled && computer.General.ManagementId != "",
+		}
+
+		if status.DDMEnabled {
+			ddmStatus, err := c.GetDDMStatusByClientManagementID(status.ManagementId)
+			if err != nil {
+				status.Error = err.Error()
+			} else {
+				status.Status = ddmStatus
+			}
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// GetComputersDeclarationStatus reports, for the DDM enabled computers matched by sort_filter, whether the
+// declaration with the given identifier has been applied.
+func (c *Client) GetComputersDeclarationStatus(identifier string, sort_filter string) ([]ComputerDeclarationStatus, error) {
+	computers, err := c.GetComputersDDMStatus(sort_filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []ComputerDeclarationStatus
+	for _, computer := range computers {
+		if !computer.DDMEnabled {
+			continue
+		}
+
+		status := ComputerDeclarationStatus{
+			ComputerID:   computer.ComputerID,
+			Name:         computer.Name,
+			SerialNumber: computer.SerialNumber,
+			ManagementId: computer.ManagementId,
+			Error:        computer.Error,
+		}
+		if computer.Status != nil {
+			status.Declaration, status.Reported = computer.Status.Declaration(identifier)
+			status.Applied = status.Reported && status.Declaration.Applied()
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
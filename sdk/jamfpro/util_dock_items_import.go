@@ -0,0 +1,240 @@
+// util_dock_items_import.go
+// Import of dock items from a macOS Dock preferences file.
+// ParseDockPlist reads the persistent-apps and persistent-others arrays of com.apple.dock.plist, in binary or
+// XML form, and returns the matching Jamf Pro dock items. ImportDockItemsFromPlist creates the dock items
+// which do not exist yet, so a golden Dock captured from a reference Mac can be reproduced in Jamf Pro.
+// Spacers, recent items stacks and URL tiles have no Jamf Pro equivalent and are skipped.
+
+package jamfpro
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	"howett.net/plist"
+)
+
+// Dock item import actions
+const (
+	DockItemImportActionCreated = "created"
+	DockItemImportActionUpdated = "updated"
+	DockItemImportActionExists  = "exists"
+	DockItemImportActionFailed  = "failed"
+)
+
+// DockItemImportOptions controls ImportDockItemsFromPlist.
+type DockItemImportOptions struct {
+	// DryRun reports the actions that would be taken without changing anything.
+	DryRun bool
+	// UpdateExisting updates the type and path of dock items which already exist with the same name.
+	UpdateExisting bool
+}
+
+// DockItemImportResult reports the outcome for a single dock item.
+type DockItemImportResult struct {
+	DockItem ResourceDockItemV1
+	Action   string
+	Error    error
+}
+
+// dockPlist is the part of com.apple.dock.plist describing the Dock contents.
+type dockPlist struct {
+	PersistentApps   []dockPlistTile `plist:"persistent-apps"`
+	PersistentOthers []dockPlistTile `plist:"persistent-others"`
+}
+
+type dockPlistTile struct {
+	TileType string            `plist:"tile-type"`
+	TileData dockPlistTileData `plist:"tile-data"`
+}
+
+type dockPlistTileData struct {
+	FileLabel string            `plist:"file-label"`
+	FileData  dockPlistFileData `plist:"file-data"`
+}
+
+type dockPlistFileData struct {
+	URLString     string `plist:"_CFURLString"`
+	URLStringType int    `plist:"_CFURLStringType"`
+}
+
+// ParseDockPlistFile reads a Dock preferences file and returns its dock items.
+func ParseDockPlistFile(filePath string) ([]ResourceDockItemV1, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dock plist: %v", err)
+	}
+
+	return ParseDockPlist(data)
+}
+
+// ParseDockPlist returns the dock items of a Dock preferences file, apps first, in Dock order.
+func ParseDockPlist(data []byte) ([]ResourceDockItemV1, error) {
+	var dock dockPlist
+	if _, err := plist.Unmarshal(data, &dock); err != nil {
+		return nil, fmt.Errorf("failed to decode dock plist: %v", err)
+	}
+
+	var items []ResourceDockItemV1
+	for _, tile := range dock.PersistentApps {
+		if item, ok := dockItemFromTile(tile, DockItemTypeApp); ok {
+			items = append(items, item)
+		}
+	}
+	for _, tile := range dock.PersistentOthers {
+		itemType := DockItemTypeFile
+		if tile.TileType == "directory-tile" {
+			itemType = DockItemTypeFolder
+		}
+		if item, ok := dockItemFromTile(tile, itemType); ok {
+			items = append(items, item)
+		}
+	}
+
+	return items, nil
+}
+
+// ImportDockItemsFromPlist creates the dock items of a Dock preferences file which do not exist in Jamf Pro.
+// Dock items are matched by name. Every item is processed even when an earlier one fails; the returned
+// error summarises the failures.
+func (c *Client) ImportDockItemsFromPlist(data []byte, opts *DockItemImportOptions) ([]DockItemImportResult, error) {
+	if opts == nil {
+		opts = &DockItemImportOptions{}
+	}
+
+	items, err := ParseDockPlist(data)
+	if err != nil {
+		return nil, err
+	}
+
+	existingList, err := c.GetDockItems()
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[string]string, len(existingList.DockItems))
+	for _, item := range existingList.DockItems {
+		existing[item.Name] = fmt.Sprintf("%d", item.ID)
+	}
+
+	var results []DockItemImportResult
+	var failed []string
+	for _, item := range items {
+		result := c.importDockItem(item, existing, opts)
+		if result.Error != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", item.Name, result.Error))
+		}
+		results = append(results, result)
+	}
+
+	if len(failed) > 0 {
+		return results, fmt.Errorf("%d dock item(s) failed: %s", len(failed), strings.Join(failed, "; "))
+	}
+
+	return results, nil
+}
+
+// importDockItem creates, or optionally updates, a single dock item.
+func (c *Client) importDockItem(item ResourceDockItemV1, existing map[string]string, opts *DockItemImportOptions) DockItemImportResult {
+	result := DockItemImportResult{DockItem: item}
+
+	id, found := existing[item.Name]
+	if !found {
+		result.Action = DockItemImportActionCreated
+		if opts.DryRun {
+			return result
+		}
+
+		created, err := c.CreateDockItemV1(&item)
+		if err != nil {
+			result.Action, result.Error = DockItemImportActionFailed, err
+			return result
+		}
+		result.DockItem.ID = created.ID
+		existing[item.Name] = created.ID
+		return result
+	}
+
+	result.DockItem.ID = id
+	if !opts.UpdateExisting {
+		result.Action = DockItemImportActionExists
+		return result
+	}
+
+	current, err := c.GetDockItemByIDV1(id)
+	if err != nil {
+		result.Action, result.Error = DockItemImportActionFailed, err
+		return result
+	}
+	if current.Type == item.Type && current.Path == item.Path {
+		result.Action = DockItemImportActionExists
+		return result
+	}
+
+	result.Action = DockItemImportActionUpdated
+	if opts.DryRun {
+		return result
+	}
+
+	update := item
+	update.ID = id
+	update.Contents = current.Contents
+	if _, err := c.UpdateDockItemByIDV1(id, &update); err != nil {
+		result.Action, result.Error = DockItemImportActionFailed, err
+	}
+
+	return result
+}
+
+// dockItemFromTile converts a Dock tile into a dock item. Tiles without a file location are skipped.
+func dockItemFromTile(tile dockPlistTile, itemType string) (ResourceDockItemV1, bool) {
+	switch tile.TileType {
+	case "", "file-tile", "directory-tile":
+	default:
+		return ResourceDockItemV1{}, false
+	}
+
+	itemPath := dockTilePath(tile.TileData.FileData)
+	if itemPath == "" {
+		return ResourceDockItemV1{}, false
+	}
+
+	name := tile.TileData.FileLabel
+	if name == "" {
+		name = strings.TrimSuffix(path.Base(itemPath), ".app")
+	}
+
+	// Applications and folders are directories, which Jamf Pro paths end with a slash.
+	if itemType != DockItemTypeFile && !strings.HasSuffix(itemPath, "/") {
+		itemPath += "/"
+	}
+
+	return ResourceDockItemV1{
+		Name: name,
+		Type: itemType,
+		Path: (&url.URL{Scheme: "file", Host: "localhost", Path: itemPath}).String(),
+	}, true
+}
+
+// dockTilePath returns the unescaped file system path of a tile. _CFURLStringType 15 is a file URL and 0 a
+// plain path.
+func dockTilePath(fileData dockPlistFileData) string {
+	location := fileData.URLString
+	if location == "" {
+		return ""
+	}
+
+	if fileData.URLStringType == 0 && strings.HasPrefix(location, "/") {
+		return location
+	}
+
+	parsed, err := url.Parse(location)
+	if err != nil || parsed.Scheme != "file" {
+		return ""
+	}
+
+	return parsed.Path
+}
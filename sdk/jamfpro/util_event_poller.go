@@ -0,0 +1,422 @@
+// util_event_poller.go
+// Poll based synthesis of webhook events for tenants which cannot deliver webhooks to a receiver.
+// The EventPoller periodically queries computer inventory for computers whose general.reportDate or
+// general.lastContactTime moved since the previous poll, compares them with the state recorded in a
+// checkpoint file and emits the events a webhook would have delivered:
+//   - ComputerAdded for computers not seen before
+//   - ComputerCheckIn when lastContactTime moves
+//   - ComputerInventoryCompleted when reportDate moves
+//   - ComputerPolicyFinished for new entries in the policy logs of computers which checked in
+//   - SmartGroupComputerMembershipChange for group membership changes of those computers
+// Events are passed to a webhooks.HandlerFunc, typically the Dispatch method of a webhooks.Receiver, so
+// the same handlers serve webhook deliveries and polled events. Delivery is at least once: the checkpoint
+// is only written after every event of a poll has been handled. Membership changes caused by editing
+// group criteria are reported when the affected computers next check in, and deleted computers are not
+// reported.
+
+package jamfpro
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/webhooks"
+)
+
+// DefaultEventPollInterval is used when EventPollerOptions.Interval is not set.
+const DefaultEventPollInterval = 5 * time.Minute
+
+const (
+	// eventPollerOverlap is subtracted from the high water mark to allow for clock skew and late writes.
+	eventPollerOverlap = 2 * time.Minute
+	// eventPollerCheckpointVersion is bumped when the checkpoint format changes incompatibly.
+	eventPollerCheckpointVersion = 1
+	// eventPollerSections are the inventory sections needed to build the event payloads.
+	eventPollerSections = "&section=GENERAL&section=HARDWARE&section=USER_AND_LOCATION&section=OPERATING_SYSTEM&section=GROUP_MEMBERSHIPS"
+)
+
+// EventPollerOptions configures an EventPoller.
+type EventPollerOptions struct {
+	// Handler receives every synthesised event. Use receiver.Dispatch to reuse webhook handlers.
+	Handler webhooks.HandlerFunc
+	// CheckpointFile persists the poller state between runs. Without it every restart re-baselines.
+	CheckpointFile string
+	// Interval defaults to DefaultEventPollInterval.
+	Interval time.Duration
+	// SkipPolicyHistory disables ComputerPolicyFinished events, which need one computer history request
+	// per computer that checked in.
+	SkipPolicyHistory bool
+	// OnError is called by Run when a poll fails. When not set the error is logged.
+	OnError func(err error)
+}
+
+// EventPoller synthesises webhook events from inventory changes.
+type EventPoller struct {
+	client     *Client
+	options    EventPollerOptions
+	checkpoint *eventPollerCheckpoint
+}
+
+// eventPollerCheckpoint is the state persisted to EventPollerOptions.CheckpointFile.
+type eventPollerCheckpoint struct {
+	Version    int                             `json:"version"`
+	BaselineAt time.Time                       `json:"baselineAt"`
+	Since      time.Time                       `json:"since"`
+	Computers  map[string]*eventPollerComputer `json:"computers"`
+}
+
+// eventPollerComputer is the last known state of a computer.
+type eventPollerComputer struct {
+	ReportDate      string            `json:"reportDate,omitempty"`
+	LastContactTime string            `json:"lastContactTime,omitempty"`
+	Groups          map[string]string `json:"groups,omitempty"`
+	LastPolicyEpoch int64             `json:"lastPolicyEpoch,omitempty"`
+}
+
+// NewEventPoller returns a poller, restoring its state from the checkpoint file when it exists.
+func (c *Client) NewEventPoller(options EventPollerOptions) (*EventPoller, error) {
+	if options.Handler == nil {
+		return nil, fmt.Errorf("event poller requires a handler")
+	}
+	if options.Interval <= 0 {
+		options.Interval = DefaultEventPollInterval
+	}
+
+	poller := &EventPoller{client: c, options: options}
+	if options.CheckpointFile == "" {
+		return poller, nil
+	}
+
+	data, err := os.ReadFile(options.CheckpointFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return poller, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read event poller checkpoint: %v", err)
+	}
+
+	var checkpoint eventPollerCheckpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, fmt.Errorf("failed to decode event poller checkpoint %s: %v", options.CheckpointFile, err)
+	}
+	if checkpoint.Version != eventPollerCheckpointVersion {
+		return nil, fmt.Errorf("event poller checkpoint %s has version %d, expected %d", options.CheckpointFile, checkpoint.Version, eventPollerCheckpointVersion)
+	}
+	if checkpoint.Computers == nil {
+		checkpoint.Computers = map[string]*eventPollerComputer{}
+	}
+	poller.checkpoint = &checkpoint
+
+	return poller, nil
+}
+
+// Run polls immediately and then every interval until ctx is cancelled, which is the only error returned.
+func (p *EventPoller) Run(ctx context.Context) error {
+	ticker := time.NewTicker(p.options.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := p.Poll(ctx); err != nil && ctx.Err() == nil {
+			if p.options.OnError != nil {
+				p.options.OnError(err)
+			} else {
+				p.client.HTTP.Logger.Error(fmt.Sprintf("event poll failed: %v", err))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll runs a single poll and returns the number of events emitted. The first poll without a checkpoint
+// records a baseline and emits nothing.
+func (p *EventPoller) Poll(ctx context.Context) (int, error) {
+	if p.checkpoint == nil {
+		return 0, p.baseline()
+	}
+
+	since := p.checkpoint.Since.Add(-eventPollerOverlap).UTC().Format(time.RFC3339)
+	filter := fmt.Sprintf(`general.reportDate>="%s",general.lastContactTime>="%s"`, since, since)
+
+	inventory, err := p.client.GetComputersInventory(eventPollerSections + "&filter=" + url.QueryEscape(filter))
+	if err != nil {
+		return 0, err
+	}
+
+	next := cloneEventPollerCheckpoint(p.checkpoint)
+	var events []*webhooks.Event
+	groupChanges := map[string]*webhooks.SmartGroupMembershipChangeEvent{}
+
+	for _, computer := range inventory.Results {
+		previous, known := next.Computers[computer.ID]
+		current := newEventPollerComputer(computer)
+		payload := eventPollerComputerPayload(computer)
+
+		switch {
+		case !known:
+			events = append(events, webhooks.NewEvent(webhooks.EventComputerAdded, &payload, eventPollerTime(current.ReportDate, current.LastContactTime)))
+		default:
+			current.LastPolicyEpoch = previous.LastPolicyEpoch
+			if current.LastContactTime != previous.LastContactTime && current.LastContactTime != "" {
+				checkIn := &webhooks.ComputerCheckInEvent{Computer: payload, Username: payload.Username}
+				events = append(events, webhooks.NewEvent(webhooks.EventComputerCheckIn, checkIn, eventPollerTime(current.LastContactTime)))
+			}
+			if current.ReportDate != previous.ReportDate && current.ReportDate != "" {
+				events = append(events, webhooks.NewEvent(webhooks.EventComputerInventoryCompleted, &payload, eventPollerTime(current.ReportDate)))
+			}
+		}
+
+		var previousGroups map[string]string
+		if known {
+			previousGroups = previous.Groups
+		}
+		collectGroupChanges(groupChanges, payload.JSSID, previousGroups, current.Groups)
+
+		if !p.options.SkipPolicyHistory && (!known || current.LastContactTime != previous.LastContactTime) {
+			policyEvents, err := p.policyEvents(computer, payload, current)
+			if err != nil {
+				return 0, err
+			}
+			events = append(events, policyEvents...)
+		}
+
+		next.Computers[computer.ID] = current
+		next.Since = latestEventPollerTime(next.Since, current.ReportDate, current.LastContactTime)
+	}
+
+	pollTime := time.Now()
+	groupIDs := make([]string, 0, len(groupChanges))
+	for id := range groupChanges {
+		groupIDs = append(groupIDs, id)
+	}
+	sort.Strings(groupIDs)
+	for _, id := range groupIDs {
+		sort.Ints(groupChanges[id].GroupAddedDevicesIDs)
+		sort.Ints(groupChanges[id].GroupRemovedDevicesIDs)
+		events = append(events, webhooks.NewEvent(webhooks.EventSmartGroupComputerMembershipChange, groupChanges[id], pollTime))
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Webhook.EventTimestamp < events[j].Webhook.EventTimestamp
+	})
+
+	for i, event := range events {
+		if err := p.options.Handler(ctx, event); err != nil {
+			return i, fmt.Errorf("failed to handle %s event: %v", event.Type(), err)
+		}
+	}
+
+	if err := p.saveCheckpoint(next); err != nil {
+		return len(events), err
+	}
+	p.checkpoint = next
+
+	return len(events), nil
+}
+
+// baseline records the current state of every computer without emitting events.
+func (p *EventPoller) baseline() error {
+	inventory, err := p.client.GetComputersInventory(eventPollerSections)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	checkpoint := &eventPollerCheckpoint{
+		Version:    eventPollerCheckpointVersion,
+		BaselineAt: now,
+		Since:      now,
+		Computers:  make(map[string]*eventPollerComputer, len(inventory.Results)),
+	}
+	for _, computer := range inventory.Results {
+		checkpoint.Computers[computer.ID] = newEventPollerComputer(computer)
+	}
+
+	if err := p.saveCheckpoint(checkpoint); err != nil {
+		return err
+	}
+	p.checkpoint = checkpoint
+
+	return nil
+}
+
+// policyEvents returns ComputerPolicyFinished events for policy log entries newer than the computer's
+// cursor, and advances the cursor. Entries from before the baseline are never reported.
+func (p *EventPoller) policyEvents(computer ResourceComputerInventory, payload webhooks.Computer, state *eventPollerComputer) ([]*webhooks.Event, error) {
+	id, err := strconv.Atoi(computer.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid computer id %q: %v", computer.ID, err)
+	}
+
+	history, err := p.client.GetComputerHistoryByComputerIDAndDataSubset(id, "PolicyLogs")
+	if err != nil {
+		return nil, err
+	}
+
+	cursor := state.LastPolicyEpoch
+	if baseline := p.checkpoint.BaselineAt.UnixMilli(); cursor < baseline {
+		cursor = baseline
+	}
+
+	var events []*webhooks.Event
+	for _, log := range history.PolicyLogs {
+		if log.DateTimeEpoch <= cursor {
+			continue
+		}
+		finished := &webhooks.ComputerPolicyFinishedEvent{
+			Computer:   payload,
+			PolicyID:   log.PolicyID,
+			Successful: strings.EqualFold(log.Status, "Completed"),
+		}
+		events = append(events, webhooks.NewEvent(webhooks.EventComputerPolicyFinished, finished, time.UnixMilli(log.DateTimeEpoch)))
+		if log.DateTimeEpoch > state.LastPolicyEpoch {
+			state.LastPolicyEpoch = log.DateTimeEpoch
+		}
+	}
+
+	return events, nil
+}
+
+// saveCheckpoint atomically writes the checkpoint file, if one is configured.
+func (p *EventPoller) saveCheckpoint(checkpoint *eventPollerCheckpoint) error {
+	if p.options.CheckpointFile == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(checkpoint, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode event poller checkpoint: %v", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(p.options.CheckpointFile), ".event-poller-*")
+	if err != nil {
+		return fmt.Errorf("failed to write event poller checkpoint: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write event poller checkpoint: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write event poller checkpoint: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), p.options.CheckpointFile); err != nil {
+		return fmt.Errorf("failed to write event poller checkpoint: %v", err)
+	}
+
+	return nil
+}
+
+// newEventPollerComputer records the state of a computer, including its smart groups, from its inventory.
+func newEventPollerComputer(computer ResourceComputerInventory) *eventPollerComputer {
+	state := &eventPollerComputer{
+		ReportDate:      computer.General.ReportDate,
+		LastContactTime: computer.General.LastContactTime,
+		Groups:          make(map[string]string, len(computer.GroupMemberships)),
+	}
+	for _, group := range computer.GroupMemberships {
+		if !group.SmartGroup {
+			continue
+		}
+		state.Groups[group.GroupId] = group.GroupName
+	}
+
+	return state
+}
+
+// eventPollerComputerPayload converts inventory into the payload of computer webhook events.
+func eventPollerComputerPayload(computer ResourceComputerInventory) webhooks.Computer {
+	jssID, _ := strconv.Atoi(computer.ID)
+
+	return webhooks.Computer{
+		UDID:                computer.UDID,
+		DeviceName:          computer.General.Name,
+		Model:               computer.Hardware.Model,
+		MacAddress:          computer.Hardware.MacAddress,
+		AlternateMacAddress: computer.Hardware.AltMacAddress,
+		SerialNumber:        computer.Hardware.SerialNumber,
+		OSVersion:           computer.OperatingSystem.Version,
+		OSBuild:             computer.OperatingSystem.Build,
+		Username:            computer.UserAndLocation.Username,
+		RealName:            computer.UserAndLocation.Realname,
+		EmailAddress:        computer.UserAndLocation.Email,
+		Phone:               computer.UserAndLocation.Phone,
+		Position:            computer.UserAndLocation.Position,
+		Room:                computer.UserAndLocation.Room,
+		JSSID:               jssID,
+	}
+}
+
+// collectGroupChanges adds a computer's membership changes to the per group change events.
+func collectGroupChanges(changes map[string]*webhooks.SmartGroupMembershipChangeEvent, computerID int, previous, current map[string]string) {
+	change := func(groupID, name string) *webhooks.SmartGroupMembershipChangeEvent {
+		event, ok := changes[groupID]
+		if !ok {
+			id, _ := strconv.Atoi(groupID)
+			event = &webhooks.SmartGroupMembershipChangeEvent{Name: name, SmartGroup: true, JSSID: id}
+			changes[groupID] = event
+		}
+		return event
+	}
+
+	for groupID, name := range current {
+		if _, ok := previous[groupID]; !ok {
+			event := change(groupID, name)
+			event.GroupAddedDevicesIDs = append(event.GroupAddedDevicesIDs, computerID)
+		}
+	}
+	for groupID, name := range previous {
+		if _, ok := current[groupID]; !ok {
+			event := change(groupID, name)
+			event.GroupRemovedDevicesIDs = append(event.GroupRemovedDevicesIDs, computerID)
+		}
+	}
+}
+
+// cloneEventPollerCheckpoint copies a checkpoint so a failed poll leaves the original untouched.
+func cloneEventPollerCheckpoint(checkpoint *eventPollerCheckpoint) *eventPollerCheckpoint {
+	clone := *checkpoint
+	clone.Computers = make(map[string]*eventPollerComputer, len(checkpoint.Computers))
+	for id, computer := range checkpoint.Computers {
+		copied := *computer
+		clone.Computers[id] = &copied
+	}
+
+	return &clone
+}
+
+// eventPollerTime returns the first of values which parses as an RFC 3339 time, or the current time.
+func eventPollerTime(values ...string) time.Time {
+	for _, value := range values {
+		if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+			return parsed
+		}
+	}
+
+	return time.Now()
+}
+
+// latestEventPollerTime returns the latest of current and the values which parse as RFC 3339 times.
+func latestEventPollerTime(current time.Time, values ...string) time.Time {
+	for _, value := range values {
+		if parsed, err := time.Parse(time.RFC3339, value); err == nil && parsed.After(current) {
+			current = parsed
+		}
+	}
+
+	return current
+}
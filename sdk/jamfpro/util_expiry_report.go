@@ -0,0 +1,441 @@
+// util_expiry_report.go
+// Expiry inventory of the credentials a Jamf Pro tenant depends on.
+// GetExpiryReport collects ADE server tokens, volume purchasing service tokens, the APNs push certificate, the
+// SSO certificate, the built-in certificate authority and Cloud LDAP keystores into one normalized report. Each
+// credential is classified against warning and critical thresholds, and reports of several tenants can be
+// merged and written as JSON or CSV.
+// A source which cannot be read, e.g. for lack of privileges, is recorded in the report errors; the other
+// sources are still collected. So is a single credential of a source without a readable expiry, e.g. an ADE
+// instance with an empty token expiration date; the other credentials of that source are still reported.
+// The Jamf Pro API has no dated source for the APNs push certificate. Its expiry is read from the notifications
+// of the account the client authenticates as, which Jamf Pro only raises shortly before expiry and which the
+// account can dismiss. When there is no such notification the push certificate is recorded in the report
+// errors rather than reported as ok.
+
+package jamfpro
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Expiring credential kinds
+const (
+	ExpiryKindADEServerToken       = "ade_server_token"
+	ExpiryKindVPPServiceToken      = "vpp_service_token"
+	ExpiryKindAPNsPushCertificate  = "apns_push_certificate"
+	ExpiryKindSSOCertificate       = "sso_certificate"
+	ExpiryKindCertificateAuthority = "certificate_authority"
+	ExpiryKindCloudLdapKeystore    = "cloud_ldap_keystore"
+)
+
+// Expiry statuses, from least to most urgent
+const (
+	ExpiryStatusOK       = "ok"
+	ExpiryStatusWarning  = "warning"
+	ExpiryStatusCritical = "critical"
+	ExpiryStatusExpired  = "expired"
+)
+
+// Default expiry thresholds
+const (
+	DefaultExpiryWarningThreshold  = 30 * 24 * time.Hour
+	DefaultExpiryCriticalThreshold = 7 * 24 * time.Hour
+)
+
+// expiryRenewalHints tells on-call staff how each kind of credential is renewed.
+var expiryRenewalHints = map[string]string{
+	ExpiryKindADEServerToken:       "Download a new server token from Apple Business Manager or Apple School Manager and upload it in Settings > Global > Automated Device Enrollment.",
+	ExpiryKindVPPServiceToken:      "Download a new content token from Apple Business Manager or Apple School Manager and upload it in Settings > Global > Volume Purchasing.",
+	ExpiryKindAPNsPushCertificate:  "Renew the push certificate at identity.apple.com with the Apple ID that created it and upload it in Settings > Global > Push Certificates. Do not create a new one.",
+	ExpiryKindSSOCertificate:       "Regenerate or upload the SSO certificate in Settings > System > Single Sign-On, then give the identity provider the new certificate.",
+	ExpiryKindCertificateAuthority: "Renew the built-in certificate authority in Settings > Global > PKI Certificates; devices receive new identity certificates as their MDM profiles renew.",
+	ExpiryKindCloudLdapKeystore:    "Download a new certificate from the identity provider and upload the keystore in Settings > System > Cloud Identity Providers.",
+}
+
+// ExpiryReportOptions controls GetExpiryReport.
+type ExpiryReportOptions struct {
+	// Instance names the tenant in the report, to tell tenants apart in a merged report.
+	Instance string
+	// WarningThreshold and CriticalThreshold classify credentials expiring within them. They default to
+	// DefaultExpiryWarningThreshold and DefaultExpiryCriticalThreshold.
+	WarningThreshold  time.Duration
+	CriticalThreshold time.Duration
+	// Kinds limits the report to the given kinds of credential. All kinds are collected when empty.
+	Kinds []string
+}
+
+// ExpiringCredential is a credential in an expiry report. ExpiresAt is zero when Jamf Pro reports the expiry
+// without a date, which only happens for the APNs push certificate; Status then follows the notification.
+// The APNs push certificate only appears once Jamf Pro notifies the client's account of its expiry.
+type ExpiringCredential struct {
+	Instance      string    `json:"instance,omitempty"`
+	Kind          string    `json:"kind"`
+	ID            string    `json:"id,omitempty"`
+	Name          string    `json:"name"`
+	ExpiresAt     time.Time `json:"expiresAt"`
+	DaysRemaining int       `json:"daysRemaining"`
+	Status        string    `json:"status"`
+	RenewalHint   string    `json:"renewalHint"`
+}
+
+// ExpiryReportError records a source which could not be collected.
+type ExpiryReportError struct {
+	Instance string `json:"instance,omitempty"`
+	Kind     string `json:"kind"`
+	Error    string `json:"error"`
+}
+
+// ExpiryReport is the expiry inventory of one or more tenants, soonest expiry first.
+type ExpiryReport struct {
+	GeneratedAt time.Time            `json:"generatedAt"`
+	Credentials []ExpiringCredential `json:"credentials"`
+	Errors      []ExpiryReportError  `json:"errors,omitempty"`
+}
+
+// expiryCollector returns the credentials of one kind, with ExpiresAt set, or Status when there is no date.
+// Credentials returned along with an error are still reported.
+type expiryCollector func(c *Client) ([]ExpiringCredential, error)
+
+// GetExpiryReport collects every expiring credential of the tenant. An error is only returned when no source
+// could be collected at all; otherwise failures are listed in the report errors.
+func (c *Client) GetExpiryReport(opts *ExpiryReportOptions) (*ExpiryReport, error) {
+	if opts == nil {
+		opts = &ExpiryReportOptions{}
+	}
+	warning, critical := opts.WarningThreshold, opts.CriticalThreshold
+	if warning <= 0 {
+		warning = DefaultExpiryWarningThreshold
+	}
+	if critical <= 0 {
+		critical = DefaultExpiryCriticalThreshold
+	}
+
+	collectors := map[string]expiryCollector{
+		ExpiryKindADEServerToken:       collectADEServerTokenExpiry,
+		ExpiryKindVPPServiceToken:      collectVPPServiceTokenExpiry,
+		ExpiryKindAPNsPushCertificate:  collectAPNsPushCertificateExpiry,
+		ExpiryKindSSOCertificate:       collectSSOCertificateExpiry,
+		ExpiryKindCertificateAuthority: collectCertificateAuthorityExpiry,
+		ExpiryKindCloudLdapKeystore:    collectCloudLdapKeystoreExpiry,
+	}
+
+	kinds := opts.Kinds
+	if len(kinds) == 0 {
+		for kind := range collectors {
+			kinds = append(kinds, kind)
+		}
+		sort.Strings(kinds)
+	}
+
+	now := time.Now()
+	report := &ExpiryReport{GeneratedAt: now.UTC()}
+	failed := 0
+	for _, kind := range kinds {
+		collect, ok := collectors[kind]
+		if !ok {
+			return nil, fmt.Errorf("unknown expiry kind: %s", kind)
+		}
+
+		credentials, err := collect(c)
+		if err != nil {
+			report.Errors = append(report.Errors, ExpiryReportError{Instance: opts.Instance, Kind: kind, Error: err.Error()})
+			if len(credentials) == 0 {
+				failed++
+				continue
+			}
+		}
+
+		for _, credential := range credentials {
+			credential.Instance = opts.Instance
+			credential.Kind = kind
+			credential.RenewalHint = expiryRenewalHints[kind]
+			if !credential.ExpiresAt.IsZero() {
+				remaining := credential.ExpiresAt.Sub(now)
+				credential.DaysRemaining = int(math.Floor(remaining.Hours() / 24))
+				credential.Status = expiryStatus(remaining, warning, critical)
+			}
+			report.Credentials = append(report.Credentials, credential)
+		}
+	}
+
+	if failed == len(kinds) {
+		return report, fmt.Errorf("no expiry source could be collected: %s", report.Errors[0].Error)
+	}
+
+	report.sort()
+
+	return report, nil
+}
+
+// MergeExpiryReports combines the reports of several tenants into one.
+func MergeExpiryReports(reports ...*ExpiryReport) *ExpiryReport {
+	merged := &ExpiryReport{GeneratedAt: time.Now().UTC()}
+	for _, report := range reports {
+		if report == nil {
+			continue
+		}
+		merged.Credentials = append(merged.Credentials, report.Credentials...)
+		merged.Errors = append(merged.Errors, report.Errors...)
+	}
+
+	merged.sort()
+
+	return merged
+}
+
+// Alerts returns the credentials which are not ok, i.e. expired or within a threshold.
+func (r *ExpiryReport) Alerts() []ExpiringCredential {
+	var alerts []ExpiringCredential
+	for _, credential := range r.Credentials {
+		if credential.Status != ExpiryStatusOK {
+			alerts = append(alerts, credential)
+		}
+	}
+
+	return alerts
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r *ExpiryReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+
+	return encoder.Encode(r)
+}
+
+// WriteCSV writes the credentials of the report as CSV, with a header row. Collection errors are not written.
+func (r *ExpiryReport) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"instance", "kind", "id", "name", "expires_at", "days_remaining", "status", "renewal_hint"}); err != nil {
+		return err
+	}
+
+	for _, credential := range r.Credentials {
+		expiresAt, daysRemaining := "", ""
+		if !credential.ExpiresAt.IsZero() {
+			expiresAt = credential.ExpiresAt.UTC().Format(time.RFC3339)
+			daysRemaining = strconv.Itoa(credential.DaysRemaining)
+		}
+
+		record := []string{
+			credential.Instance,
+			credential.Kind,
+			credential.ID,
+			credential.Name,
+			expiresAt,
+			daysRemaining,
+			credential.Status,
+			credential.RenewalHint,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+// sort orders the credentials by expiry, undated ones first, as they are only reported once due.
+func (r *ExpiryReport) sort() {
+	sort.SliceStable(r.Credentials, func(i, j int) bool {
+		return r.Credentials[i].ExpiresAt.Before(r.Credentials[j].ExpiresAt)
+	})
+}
+
+// expiryStatus classifies the time remaining until expiry.
+func expiryStatus(remaining, warning, critical time.Duration) string {
+	switch {
+	case remaining <= 0:
+		return ExpiryStatusExpired
+	case remaining <= critical:
+		return ExpiryStatusCritical
+	case remaining <= warning:
+		return ExpiryStatusWarning
+	default:
+		return ExpiryStatusOK
+	}
+}
+
+// parseExpiryTimestamp parses the expiry timestamps returned by Jamf Pro, which are dates or timestamps
+// depending on the resource and version. A date expires at the end of the day, in UTC.
+func parseExpiryTimestamp(value string) (time.Time, error) {
+	if expiresAt, err := time.Parse("2006-01-02", value); err == nil {
+		return expiresAt.Add(24*time.Hour - time.Second), nil
+	}
+
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05.000Z0700", "2006-01-02T15:04:05"} {
+		if expiresAt, err := time.Parse(layout, value); err == nil {
+			return expiresAt, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid expiry timestamp: %q", value)
+}
+
+func collectADEServerTokenExpiry(c *Client) ([]ExpiringCredential, error) {
+	expirations, err := c.GetDeviceEnrollmentTokenExpirations(0)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		out    []ExpiringCredential
+		failed []string
+	)
+	for _, expiration := range expirations {
+		if expiration.Error != "" {
+			failed = append(failed, fmt.Sprintf("device enrollment %s: %s", expiration.Name, expiration.Error))
+			continue
+		}
+		out = append(out, ExpiringCredential{ID: expiration.ID, Name: expiration.Name, ExpiresAt: expiration.ExpiresAt})
+	}
+
+	if len(failed) > 0 {
+		return out, fmt.Errorf("%s", strings.Join(failed, "; "))
+	}
+
+	return out, nil
+}
+
+func collectVPPServiceTokenExpiry(c *Client) ([]ExpiringCredential, error) {
+	locations, err := c.GetVolumePurchaseLocations("")
+	if err != nil {
+		return nil, err
+	}
+
+	var out []ExpiringCredential
+	for _, location := range locations.Results {
+		if location.TokenExpiration == "" {
+			continue
+		}
+
+		expiresAt, err := parseExpiryTimestamp(location.TokenExpiration)
+		if err != nil {
+			return nil, fmt.Errorf("volume purchasing location %s: %v", location.Name, err)
+		}
+
+		name := location.Name
+		if name == "" {
+			name = location.LocationName
+		}
+		out = append(out, ExpiringCredential{ID: location.ID, Name: name, ExpiresAt: expiresAt})
+	}
+
+	return out, nil
+}
+
+// collectAPNsPushCertificateExpiry reads the push certificate expiry from the Jamf Pro notifications, the only
+// place the Jamf Pro API exposes it. Notifications are per account and dismissable, and are only raised close to
+// expiry, so their absence says nothing about the certificate and is returned as an error.
+func collectAPNsPushCertificateExpiry(c *Client) ([]ExpiringCredential, error) {
+	notifications, err := c.GetNotifications()
+	if err != nil {
+		return nil, err
+	}
+
+	var out []ExpiringCredential
+	for _, notification := range notifications {
+		credential := ExpiringCredential{ID: notification.ID, Name: "APNs push certificate"}
+		switch notification.Type {
+		case NotificationTypePushCertWillExpire:
+			credential.Status = ExpiryStatusWarning
+		case NotificationTypePushCertExpired:
+			credential.Status = ExpiryStatusExpired
+		default:
+			continue
+		}
+
+		if value, ok := notification.Params["expirationDate"].(string); ok {
+			if expiresAt, err := parseExpiryTimestamp(value); err == nil {
+				credential.ExpiresAt = expiresAt
+			}
+		} else if days, ok := notification.Params["days"].(float64); ok {
+			credential.ExpiresAt = time.Now().Add(time.Duration(days*24) * time.Hour)
+		}
+		out = append(out, credential)
+	}
+
+	if len(out) == 0 {
+		return nil, fmt.Errorf("expiry unknown: the Jamf Pro API only exposes it through the notifications of the client's " +
+			"account, which has no push certificate notification; check Settings > Global > Push Certificates")
+	}
+
+	return out, nil
+}
+
+func collectSSOCertificateExpiry(c *Client) ([]ExpiringCredential, error) {
+	certificate, err := c.GetSSOCertificate()
+	if err != nil {
+		return nil, err
+	}
+
+	details := certificate.KeystoreDetails
+	if certificate.Keystore.KeystoreSetupType == SSOKeystoreSetupTypeNone || details.Expiration == "" {
+		return nil, nil
+	}
+
+	expiresAt, err := parseExpiryTimestamp(details.Expiration)
+	if err != nil {
+		return nil, fmt.Errorf("sso certificate: %v", err)
+	}
+
+	return []ExpiringCredential{{ID: strconv.FormatInt(details.SerialNumber, 10), Name: details.Subject, ExpiresAt: expiresAt}}, nil
+}
+
+func collectCertificateAuthorityExpiry(c *Client) ([]ExpiringCredential, error) {
+	authority, err := c.GetActiveCertificateAuthority()
+	if err != nil {
+		return nil, err
+	}
+
+	// notAfter is in epoch seconds; older versions return milliseconds.
+	notAfter := authority.NotAfter
+	if notAfter > 1e11 {
+		notAfter /= 1000
+	}
+
+	return []ExpiringCredential{{
+		ID:        authority.SerialNumber,
+		Name:      authority.SubjectX500Principal,
+		ExpiresAt: time.Unix(notAfter, 0).UTC(),
+	}}, nil
+}
+
+func collectCloudLdapKeystoreExpiry(c *Client) ([]ExpiringCredential, error) {
+	providers, err := c.GetCloudIdentityProviders("")
+	if err != nil {
+		return nil, err
+	}
+
+	var out []ExpiringCredential
+	for _, provider := range providers.Results {
+		ldap, err := c.GetCloudLdapByID(provider.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		keystore := ldap.Server.Keystore
+		if keystore.ExpirationDate == "" {
+			continue
+		}
+
+		expiresAt, err := parseExpiryTimestamp(keystore.ExpirationDate)
+		if err != nil {
+			return nil, fmt.Errorf("cloud identity provider %s: %v", provider.DisplayName, err)
+		}
+		out = append(out, ExpiringCredential{ID: provider.ID, Name: provider.DisplayName, ExpiresAt: expiresAt})
+	}
+
+	return out, nil
+}
@@ -0,0 +1,105 @@
+package jamfpro
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExpiryReportAPNsPushCertificate(t *testing.T) {
+	tests := []struct {
+		name          string
+		notifications []ResourceNotification
+		wantStatus    string
+		wantDays      int
+		wantErr       bool
+	}{
+		{
+			name:    "no notification",
+			wantErr: true,
+		},
+		{
+			name:          "only unrelated notifications",
+			notifications: []ResourceNotification{{Type: NotificationTypeSSOCertWillExpire, ID: "1"}},
+			wantErr:       true,
+		},
+		{
+			name:          "expiring in days",
+			notifications: []ResourceNotification{{Type: NotificationTypePushCertWillExpire, ID: "2", Params: map[string]interface{}{"days": 5.0}}},
+			wantStatus:    ExpiryStatusCritical,
+			wantDays:      5,
+		},
+		{
+			name:          "expired without date",
+			notifications: []ResourceNotification{{Type: NotificationTypePushCertExpired, ID: "3"}},
+			wantStatus:    ExpiryStatusExpired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/api/v1/notifications", func(w http.ResponseWriter, r *http.Request) {
+				notifications := tt.notifications
+				if notifications == nil {
+					notifications = []ResourceNotification{}
+				}
+				writeTestJSON(w, http.StatusOK, notifications)
+			})
+
+			report, err := newTestClient(t, mux).GetExpiryReport(&ExpiryReportOptions{Kinds: []string{ExpiryKindAPNsPushCertificate}})
+
+			if tt.wantErr {
+				if err == nil || len(report.Errors) != 1 || !strings.Contains(report.Errors[0].Error, "notifications") {
+					t.Fatalf("report = %+v, error = %v, want the push certificate recorded as unknown", report, err)
+				}
+				if len(report.Credentials) != 0 {
+					t.Errorf("credentials = %+v, want none", report.Credentials)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetExpiryReport: %v", err)
+			}
+			if len(report.Credentials) != 1 {
+				t.Fatalf("credentials = %+v, want one", report.Credentials)
+			}
+			credential := report.Credentials[0]
+			if credential.Status != tt.wantStatus || credential.DaysRemaining != tt.wantDays {
+				t.Errorf("credential = %+v, want status %s and %d days remaining", credential, tt.wantStatus, tt.wantDays)
+			}
+		})
+	}
+}
+
+func TestExpiryReportADEInstanceWithoutExpiry(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/device-enrollments", func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, http.StatusOK, ResponseDeviceEnrollmentsList{TotalCount: 3, Results: []ResourceDeviceEnrollment{
+			{ID: "1", Name: "School", TokenExpirationDate: "2099-01-31"},
+			{ID: "2", Name: "Pending", TokenExpirationDate: ""},
+			{ID: "3", Name: "Broken", TokenExpirationDate: "soon"},
+		}})
+	})
+	client := newTestClient(t, mux)
+
+	expirations, err := client.GetDeviceEnrollmentTokenExpirations(24 * time.Hour)
+	if err != nil {
+		t.Fatalf("GetDeviceEnrollmentTokenExpirations: %v", err)
+	}
+	if len(expirations) != 2 || expirations[0].Error == "" || expirations[1].Error == "" {
+		t.Errorf("expirations = %+v, want only the two instances without expiry, with errors", expirations)
+	}
+
+	report, err := client.GetExpiryReport(&ExpiryReportOptions{Kinds: []string{ExpiryKindADEServerToken}})
+	if err != nil {
+		t.Fatalf("GetExpiryReport: %v", err)
+	}
+	if len(report.Credentials) != 1 || report.Credentials[0].ID != "1" || report.Credentials[0].Status != ExpiryStatusOK {
+		t.Errorf("credentials = %+v, want the School instance", report.Credentials)
+	}
+	if len(report.Errors) != 1 || !strings.Contains(report.Errors[0].Error, "Pending") || !strings.Contains(report.Errors[0].Error, "Broken") {
+		t.Errorf("errors = %+v, want the Pending and Broken instances recorded", report.Errors)
+	}
+}
@@ -0,0 +1,459 @@
+// util_filevault_report.go
+// FileVault and recovery lock compliance of the computer fleet.
+// GetFileVaultReport joins computer inventory (disk encryption, security and hardware) with the FileVault
+// inventory, which tells whether a personal recovery key is escrowed, and lists the issues of every computer.
+// Only the presence of a key is read; keys are never kept or put in the report. Reading the FileVault inventory
+// requires the "View Disk Encryption Recovery Key" privilege. An escrowed key which Jamf Pro has not validated
+// yet is not an issue; those computers are listed by UnvalidatedPersonalRecoveryKeys.
+// RemediateFileVaultReport acts on the non-compliant computers: a SET_RECOVERY_LOCK MDM command with a random
+// password per computer, and a policy which issues a new personal recovery key, as Jamf Pro has no MDM command
+// for that. The policy is scoped to exactly the computers of the report which still need a new key, so fixed
+// computers leave it again.
+
+package jamfpro
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FileVault report issues
+const (
+	FileVaultIssueNotEncrypted               = "filevault_not_enabled"
+	FileVaultIssueNoPersonalRecoveryKey      = "no_personal_recovery_key"
+	FileVaultIssueInvalidPersonalRecoveryKey = "invalid_personal_recovery_key"
+	FileVaultIssueNoRecoveryLock             = "no_recovery_lock"
+)
+
+// FileVault remediation actions
+const (
+	FileVaultActionSetRecoveryLock            = "set_recovery_lock"
+	FileVaultActionRotatePersonalRecoveryKeys = "rotate_personal_recovery_keys"
+)
+
+// DefaultFileVaultRotationPolicyName is the name of the policy RemediateFileVaultReport maintains to issue new
+// personal recovery keys.
+const DefaultFileVaultRotationPolicyName = "FileVault personal recovery key rotation"
+
+// recoveryLockPasswordLength and recoveryLockPasswordCharacters shape generated recovery lock passwords, which
+// are typed at the recovery prompt, so look-alikes and symbols are left out.
+const (
+	recoveryLockPasswordLength     = 20
+	recoveryLockPasswordCharacters = "ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz23456789"
+)
+
+// Personal recovery key validity statuses
+const (
+	PersonalRecoveryKeyValidityStatusValid   = "VALID"
+	PersonalRecoveryKeyValidityStatusInvalid = "INVALID"
+	PersonalRecoveryKeyValidityStatusUnknown = "UNKNOWN"
+)
+
+// fileVaultReportSections are the inventory sections needed to build the report.
+const fileVaultReportSections = "&section=GENERAL&section=HARDWARE&section=DISK_ENCRYPTION&section=SECURITY"
+
+// FileVaultReportEntry is the FileVault and recovery lock state of a computer. Recovery lock only applies to
+// Apple silicon computers.
+type FileVaultReportEntry struct {
+	ComputerID                          string   `json:"computerId"`
+	Name                                string   `json:"name"`
+	SerialNumber                        string   `json:"serialNumber"`
+	ManagementId                        string   `json:"managementId"`
+	AppleSilicon                        bool     `json:"appleSilicon"`
+	FileVaultState                      string   `json:"fileVaultState"`
+	PersonalRecoveryKeyEscrowed         bool     `json:"personalRecoveryKeyEscrowed"`
+	IndividualRecoveryKeyValidityStatus string   `json:"individualRecoveryKeyValidityStatus"`
+	InstitutionalRecoveryKeyPresent     bool     `json:"institutionalRecoveryKeyPresent"`
+	RecoveryLockEnabled                 bool     `json:"recoveryLockEnabled"`
+	Issues                              []string `json:"issues,omitempty"`
+}
+
+// HasIssue reports whether the computer has the given issue.
+func (e FileVaultReportEntry) HasIssue(issue string) bool {
+	for _, i := range e.Issues {
+		if i == issue {
+			return true
+		}
+	}
+	return false
+}
+
+// FileVaultReport is the FileVault and recovery lock state of the computers, ordered by computer ID.
+type FileVaultReport struct {
+	GeneratedAt time.Time              `json:"generatedAt"`
+	Computers   []FileVaultReportEntry `json:"computers"`
+}
+
+// FileVaultRemediationOptions controls RemediateFileVaultReport.
+type FileVaultRemediationOptions struct {
+	// SetRecoveryLock sends SET_RECOVERY_LOCK to the computers without a recovery lock.
+	SetRecoveryLock bool
+	// RotatePersonalRecoveryKeys scopes a policy which issues a new personal recovery key to the encrypted
+	// computers without a valid escrowed key. The policy runs once a day at check-in; computers of the report
+	// which no longer need a new key are removed from its scope.
+	RotatePersonalRecoveryKeys bool
+	// PolicyName is the name of the rotation policy, created when missing. Defaults to
+	// DefaultFileVaultRotationPolicyName.
+	PolicyName string
+	// DryRun reports the actions which would be taken without taking them.
+	DryRun bool
+}
+
+// FileVaultRemediationAction is a remediation taken, or in a dry run to be taken, by RemediateFileVaultReport.
+// The password of a recovery lock is not reported; read it with GetComputerRecoveryLockPasswordByID.
+type FileVaultRemediationAction struct {
+	Action      string   `json:"action"`
+	ComputerIDs []string `json:"computerIds"`
+	CommandUUID string   `json:"commandUuid,omitempty"`
+	PolicyID    int      `json:"policyId,omitempty"`
+	// RemovedComputerIDs are the computers of the report which left the scope of the rotation policy.
+	RemovedComputerIDs []string `json:"removedComputerIds,omitempty"`
+	Error              string   `json:"error,omitempty"`
+}
+
+// GetFileVaultReport builds the FileVault report of the computers matched by sort_filter, in the form
+// GetComputersInventory takes.
+func (c *Client) GetFileVaultReport(sort_filter string) (*FileVaultReport, error) {
+	inventory, err := c.GetComputersInventory(fileVaultReportSections + sort_filter)
+	if err != nil {
+		return nil, err
+	}
+
+	escrowed, err := c.escrowedPersonalRecoveryKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &FileVaultReport{GeneratedAt: time.Now().UTC()}
+	for _, computer := range inventory.Results {
+		entry := FileVaultReportEntry{
+			ComputerID:                          computer.ID,
+			Name:                                computer.General.Name,
+			SerialNumber:                        computer.Hardware.SerialNumber,
+			ManagementId:                        computer.General.ManagementId,
+			AppleSilicon:                        computer.Hardware.AppleSilicon,
+			FileVaultState:                      computer.DiskEncryption.BootPartitionEncryptionDetails.PartitionFileVault2State,
+			PersonalRecoveryKeyEscrowed:         escrowed[computer.ID],
+			IndividualRecoveryKeyValidityStatus: computer.DiskEncryption.IndividualRecoveryKeyValidityStatus,
+			InstitutionalRecoveryKeyPresent:     computer.DiskEncryption.InstitutionalRecoveryKeyPresent,
+			RecoveryLockEnabled:                 computer.Security.RecoveryLockEnabled,
+		}
+		entry.Issues = fileVaultIssues(entry)
+		report.Computers = append(report.Computers, entry)
+	}
+
+	sort.SliceStable(report.Computers, func(i, j int) bool {
+		a, _ := strconv.Atoi(report.Computers[i].ComputerID)
+		b, _ := strconv.Atoi(report.Computers[j].ComputerID)
+		return a < b
+	})
+
+	return report, nil
+}
+
+// NonCompliant returns the computers with at least one issue.
+func (r *FileVaultReport) NonCompliant() []FileVaultReportEntry {
+	var entries []FileVaultReportEntry
+	for _, entry := range r.Computers {
+		if len(entry.Issues) > 0 {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// UnvalidatedPersonalRecoveryKeys returns the encrypted computers with an escrowed personal recovery key which
+// Jamf Pro has not validated yet. They are not reported as non-compliant, as that is the state of a key until
+// the computer confirms it.
+func (r *FileVaultReport) UnvalidatedPersonalRecoveryKeys() []FileVaultReportEntry {
+	var entries []FileVaultReportEntry
+	for _, entry := range r.Computers {
+		if entry.FileVaultState == "ENCRYPTED" && entry.PersonalRecoveryKeyEscrowed && !isPersonalRecoveryKeyValidated(entry.IndividualRecoveryKeyValidityStatus) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r *FileVaultReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+
+	return encoder.Encode(r)
+}
+
+// WriteCSV writes the computers of the report as CSV, with a header row. Issues are separated by semicolons.
+func (r *FileVaultReport) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	header := []string{"computer_id", "name", "serial_number", "apple_silicon", "filevault_state", "personal_recovery_key_escrowed",
+		"individual_recovery_key_validity_status", "institutional_recovery_key_present", "recovery_lock_enabled", "issues"}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, entry := range r.Computers {
+		record := []string{
+			entry.ComputerID,
+			entry.Name,
+			entry.SerialNumber,
+			strconv.FormatBool(entry.AppleSilicon),
+			entry.FileVaultState,
+			strconv.FormatBool(entry.PersonalRecoveryKeyEscrowed),
+			entry.IndividualRecoveryKeyValidityStatus,
+			strconv.FormatBool(entry.InstitutionalRecoveryKeyPresent),
+			strconv.FormatBool(entry.RecoveryLockEnabled),
+			strings.Join(entry.Issues, ";"),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+// RemediateFileVaultReport issues the remediations selected in opts for the non-compliant computers of the
+// report. Failures are recorded in the actions; an error is only returned for invalid options.
+func (c *Client) RemediateFileVaultReport(report *FileVaultReport, opts *FileVaultRemediationOptions) ([]FileVaultRemediationAction, error) {
+	if report == nil || opts == nil {
+		return nil, fmt.Errorf("a report and remediation options are required")
+	}
+	if !opts.SetRecoveryLock && !opts.RotatePersonalRecoveryKeys {
+		return nil, fmt.Errorf("no remediation selected")
+	}
+
+	var actions []FileVaultRemediationAction
+	var rotate []FileVaultReportEntry
+	for _, entry := range report.NonCompliant() {
+		if opts.SetRecoveryLock && entry.HasIssue(FileVaultIssueNoRecoveryLock) {
+			actions = append(actions, c.setComputerRecoveryLock(entry, opts.DryRun))
+		}
+		if opts.RotatePersonalRecoveryKeys && (entry.HasIssue(FileVaultIssueNoPersonalRecoveryKey) || entry.HasIssue(FileVaultIssueInvalidPersonalRecoveryKey)) {
+			rotate = append(rotate, entry)
+		}
+	}
+
+	if opts.RotatePersonalRecoveryKeys {
+		policyName := opts.PolicyName
+		if policyName == "" {
+			policyName = DefaultFileVaultRotationPolicyName
+		}
+		if action, ok := c.scopeFileVaultRotationPolicy(policyName, report, rotate, opts.DryRun); ok {
+			actions = append(actions, action)
+		}
+	}
+
+	return actions, nil
+}
+
+// setComputerRecoveryLock sends SET_RECOVERY_LOCK with a random password to a computer.
+func (c *Client) setComputerRecoveryLock(entry FileVaultReportEntry, dryRun bool) FileVaultRemediationAction {
+	action := FileVaultRemediationAction{
+		Action:      FileVaultActionSetRecoveryLock,
+		ComputerIDs: []string{entry.ComputerID},
+	}
+	if dryRun {
+		return action
+	}
+	if entry.ManagementId == "" {
+		action.Error = "computer has no client management id"
+		return action
+	}
+
+	password, err := generateRandomPassword(recoveryLockPasswordCharacters, recoveryLockPasswordLength)
+	if err != nil {
+		action.Error = err.Error()
+		return action
+	}
+
+	commands, err := c.SendMDMCommand(NewMDMCommandRequest(NewMDMSetRecoveryLockCommand(password), entry.ManagementId))
+	if err != nil {
+		action.Error = err.Error()
+		return action
+	}
+	if uuids := commands.UUIDs(); len(uuids) > 0 {
+		action.CommandUUID = uuids[0]
+	}
+
+	return action
+}
+
+// scopeFileVaultRotationPolicy scopes the rotation policy to the given computers, creating the policy when it
+// does not exist. Other computers of the report are removed from the scope; the rest of the scope is kept. It
+// reports false when there is nothing to do: no computer needs a new key and the policy does not exist.
+func (c *Client) scopeFileVaultRotationPolicy(policyName string, report *FileVaultReport, entries []FileVaultReportEntry, dryRun bool) (FileVaultRemediationAction, bool) {
+	action := FileVaultRemediationAction{Action: FileVaultActionRotatePersonalRecoveryKeys}
+
+	computers := make([]PolicySubsetComputer, 0, len(entries))
+	for _, entry := range entries {
+		action.ComputerIDs = append(action.ComputerIDs, entry.ComputerID)
+		id, err := strconv.Atoi(entry.ComputerID)
+		if err != nil {
+			action.Error = fmt.Sprintf("invalid computer id %q", entry.ComputerID)
+			return action, true
+		}
+		computers = append(computers, PolicySubsetComputer{ID: id})
+	}
+
+	reported := make(map[int]bool, len(report.Computers))
+	for _, entry := range report.Computers {
+		if id, err := strconv.Atoi(entry.ComputerID); err == nil {
+			reported[id] = true
+		}
+	}
+
+	policies, err := c.GetPolicies()
+	if err != nil {
+		action.Error = err.Error()
+		return action, true
+	}
+	for _, item := range policies.Policy {
+		if item.Name == policyName {
+			action.PolicyID = item.ID
+			break
+		}
+	}
+	if action.PolicyID == 0 && len(computers) == 0 {
+		return action, false
+	}
+
+	policy := &ResourcePolicy{
+		General: PolicySubsetGeneral{
+			Name:           policyName,
+			TriggerCheckin: true,
+			Frequency:      "Once every day",
+		},
+		DiskEncryption: &PolicySubsetDiskEncryption{
+			Action:           "remediate",
+			RemediateKeyType: "Individual",
+		},
+	}
+	if action.PolicyID != 0 {
+		policy, err = c.GetPolicyByID(action.PolicyID)
+		if err != nil {
+			action.Error = err.Error()
+			return action, true
+		}
+	}
+	if policy.Scope == nil {
+		policy.Scope = &PolicySubsetScope{}
+	}
+	var removed []int
+	policy.Scope.Computers, removed = fileVaultRotationScopeComputers(policy.Scope.Computers, reported, computers)
+	for _, id := range removed {
+		action.RemovedComputerIDs = append(action.RemovedComputerIDs, strconv.Itoa(id))
+	}
+	if dryRun {
+		return action, true
+	}
+
+	policy.General.Enabled = true
+
+	var saved *ResponsePolicyCreateAndUpdate
+	if action.PolicyID != 0 {
+		saved, err = c.UpdatePolicyByID(action.PolicyID, policy)
+	} else {
+		saved, err = c.CreatePolicy(policy)
+	}
+	if err != nil {
+		action.Error = err.Error()
+		return action, true
+	}
+	action.PolicyID = saved.ID
+
+	return action, true
+}
+
+// fileVaultRotationScopeComputers returns the computers to scope the rotation policy to: the scoped computers
+// which are not in the report, followed by the computers which need a new key. It also returns the reported
+// computers which leave the scope.
+func fileVaultRotationScopeComputers(scoped *[]PolicySubsetComputer, reported map[int]bool, computers []PolicySubsetComputer) (*[]PolicySubsetComputer, []int) {
+	rotate := make(map[int]bool, len(computers))
+	for _, computer := range computers {
+		rotate[computer.ID] = true
+	}
+
+	scope := []PolicySubsetComputer{}
+	inScope := make(map[int]bool)
+	var removed []int
+	if scoped != nil {
+		for _, computer := range *scoped {
+			if reported[computer.ID] && !rotate[computer.ID] {
+				removed = append(removed, computer.ID)
+				continue
+			}
+			if !inScope[computer.ID] {
+				scope = append(scope, computer)
+				inScope[computer.ID] = true
+			}
+		}
+	}
+	for _, computer := range computers {
+		if !inScope[computer.ID] {
+			scope = append(scope, computer)
+			inScope[computer.ID] = true
+		}
+	}
+
+	return &scope, removed
+}
+
+// escrowedPersonalRecoveryKeys returns by computer ID whether the FileVault inventory holds a personal recovery
+// key. The keys are read from the raw results and not kept.
+func (c *Client) escrowedPersonalRecoveryKeys() (map[string]bool, error) {
+	resp, err := c.DoPaginatedGet(uriComputersInventoryProAPI+"/filevault", standardPageSize, startingPageNumber, "")
+	if err != nil {
+		return nil, fmt.Errorf(errMsgFailedPaginatedGet, "filevault inventories", err)
+	}
+
+	escrowed := make(map[string]bool, len(resp.Results))
+	for _, value := range resp.Results {
+		computer, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		id, _ := computer["computerId"].(string)
+		key, _ := computer["personalRecoveryKey"].(string)
+		escrowed[id] = key != ""
+	}
+
+	return escrowed, nil
+}
+
+// isPersonalRecoveryKeyValidated reports whether Jamf Pro has validated an escrowed personal recovery key, be it
+// found valid or invalid.
+func isPersonalRecoveryKeyValidated(validityStatus string) bool {
+	return validityStatus == PersonalRecoveryKeyValidityStatusValid || validityStatus == PersonalRecoveryKeyValidityStatusInvalid
+}
+
+// fileVaultIssues lists the issues of a computer. An escrowed key which has not been validated yet is not an
+// issue.
+func fileVaultIssues(entry FileVaultReportEntry) []string {
+	var issues []string
+
+	if entry.FileVaultState != "ENCRYPTED" {
+		issues = append(issues, FileVaultIssueNotEncrypted)
+	} else {
+		if !entry.PersonalRecoveryKeyEscrowed {
+			issues = append(issues, FileVaultIssueNoPersonalRecoveryKey)
+		} else if entry.IndividualRecoveryKeyValidityStatus == PersonalRecoveryKeyValidityStatusInvalid {
+			issues = append(issues, FileVaultIssueInvalidPersonalRecoveryKey)
+		}
+	}
+
+	if entry.AppleSilicon && !entry.RecoveryLockEnabled {
+		issues = append(issues, FileVaultIssueNoRecoveryLock)
+	}
+
+	return issues
+}
@@ -0,0 +1,187 @@
+package jamfpro
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestGetFileVaultReport(t *testing.T) {
+	computer := func(id, state, validity string) ResourceComputerInventory {
+		var inventory ResourceComputerInventory
+		inventory.ID = id
+		inventory.DiskEncryption.BootPartitionEncryptionDetails.PartitionFileVault2State = state
+		inventory.DiskEncryption.IndividualRecoveryKeyValidityStatus = validity
+		return inventory
+	}
+	computers := []ResourceComputerInventory{
+		computer("1", "ENCRYPTED", "VALID"),
+		computer("2", "ENCRYPTED", "INVALID"),
+		computer("3", "ENCRYPTED", "UNKNOWN"),
+		computer("4", "ENCRYPTED", "UNKNOWN"),
+		computer("5", "", ""),
+	}
+	// Computer 4 has no key; computer 5 is not encrypted.
+	keys := []FileVaultInventory{
+		{ComputerId: "1", PersonalRecoveryKey: "AAAA-BBBB"},
+		{ComputerId: "2", PersonalRecoveryKey: "CCCC-DDDD"},
+		{ComputerId: "3", PersonalRecoveryKey: "EEEE-FFFF"},
+		{ComputerId: "4"},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/computers-inventory-detail", func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, http.StatusOK, ResponseComputerInventoryList{TotalCount: len(computers), Results: computers})
+	})
+	mux.HandleFunc("/api/v1/computers-inventory/filevault", func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, http.StatusOK, FileVaultInventoryList{TotalCount: len(keys), Results: keys})
+	})
+
+	report, err := newTestClient(t, mux).GetFileVaultReport("")
+	if err != nil {
+		t.Fatalf("GetFileVaultReport: %v", err)
+	}
+
+	want := map[string]string{
+		"1": "",
+		"2": FileVaultIssueInvalidPersonalRecoveryKey,
+		"3": "",
+		"4": FileVaultIssueNoPersonalRecoveryKey,
+		"5": FileVaultIssueNotEncrypted,
+	}
+	if len(report.Computers) != len(want) {
+		t.Fatalf("computers = %+v, want %d", report.Computers, len(want))
+	}
+	for _, entry := range report.Computers {
+		if got := strings.Join(entry.Issues, ";"); got != want[entry.ComputerID] {
+			t.Errorf("computer %s issues = %q, want %q", entry.ComputerID, got, want[entry.ComputerID])
+		}
+	}
+
+	if unvalidated := report.UnvalidatedPersonalRecoveryKeys(); len(unvalidated) != 1 || unvalidated[0].ComputerID != "3" {
+		t.Errorf("UnvalidatedPersonalRecoveryKeys = %+v, want computer 3", unvalidated)
+	}
+
+	var buf strings.Builder
+	if err := report.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON: %v", err)
+	}
+	if strings.Contains(buf.String(), "AAAA-BBBB") {
+		t.Error("the report holds a recovery key")
+	}
+}
+
+func TestFileVaultRotationScopeComputers(t *testing.T) {
+	tests := []struct {
+		name        string
+		scoped      *[]PolicySubsetComputer
+		computers   []PolicySubsetComputer
+		want        []int
+		wantRemoved []int
+	}{
+		{
+			name:      "empty scope",
+			computers: []PolicySubsetComputer{{ID: 3}, {ID: 4}},
+			want:      []int{3, 4},
+		},
+		{
+			name:        "compliant computers leave, others are kept",
+			scoped:      &[]PolicySubsetComputer{{ID: 1}, {ID: 3}, {ID: 7, Name: "not reported"}},
+			computers:   []PolicySubsetComputer{{ID: 3}, {ID: 4}, {ID: 4}},
+			want:        []int{3, 7, 4},
+			wantRemoved: []int{1},
+		},
+		{
+			name:        "nothing to rotate",
+			scoped:      &[]PolicySubsetComputer{{ID: 1}},
+			want:        []int{},
+			wantRemoved: []int{1},
+		},
+	}
+
+	reported := map[int]bool{1: true, 2: true, 3: true, 4: true}
+	ids := func(computers []PolicySubsetComputer) []int {
+		got := []int{}
+		for _, computer := range computers {
+			got = append(got, computer.ID)
+		}
+		return got
+	}
+	equal := func(a, b []int) bool {
+		if len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if a[i] != b[i] {
+				return false
+			}
+		}
+		return true
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scope, removed := fileVaultRotationScopeComputers(tt.scoped, reported, tt.computers)
+			if got := ids(*scope); !equal(got, tt.want) {
+				t.Errorf("scope = %v, want %v", got, tt.want)
+			}
+			if !equal(removed, tt.wantRemoved) {
+				t.Errorf("removed = %v, want %v", removed, tt.wantRemoved)
+			}
+		})
+	}
+}
+
+func TestRemediateFileVaultReportScopesRotationPolicy(t *testing.T) {
+	var sent ResourcePolicy
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/JSSResource/policies", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		w.Write([]byte(`<policies><size>1</size><policy><id>9</id><name>` + DefaultFileVaultRotationPolicyName + `</name></policy></policies>`))
+	})
+	mux.HandleFunc("/JSSResource/policies/id/9", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		if r.Method == http.MethodPut {
+			body, _ := io.ReadAll(r.Body)
+			xml.Unmarshal(body, &sent)
+			w.Write([]byte(`<policy><id>9</id></policy>`))
+			return
+		}
+		w.Write([]byte(`<policy><general><id>9</id><name>` + DefaultFileVaultRotationPolicyName + `</name></general>` +
+			`<scope><computers><computer><id>1</id></computer><computer><id>7</id></computer></computers>` +
+			`<computer_groups><computer_group><id>5</id></computer_group></computer_groups></scope></policy>`))
+	})
+
+	// Computer 1 got a valid key since the last run, computer 2 needs one, computer 7 was scoped by hand.
+	report := &FileVaultReport{Computers: []FileVaultReportEntry{
+		{ComputerID: "1"},
+		{ComputerID: "2", Issues: []string{FileVaultIssueInvalidPersonalRecoveryKey}},
+	}}
+	actions, err := newTestClient(t, mux).RemediateFileVaultReport(report, &FileVaultRemediationOptions{RotatePersonalRecoveryKeys: true})
+	if err != nil {
+		t.Fatalf("RemediateFileVaultReport: %v", err)
+	}
+	if len(actions) != 1 || actions[0].Error != "" || actions[0].PolicyID != 9 {
+		t.Fatalf("actions = %+v, want the policy updated", actions)
+	}
+	if removed := actions[0].RemovedComputerIDs; len(removed) != 1 || removed[0] != "1" {
+		t.Errorf("removed = %v, want computer 1", removed)
+	}
+
+	if sent.Scope == nil || sent.Scope.Computers == nil {
+		t.Fatalf("scope sent = %+v, want computers", sent.Scope)
+	}
+	var scoped []int
+	for _, computer := range *sent.Scope.Computers {
+		scoped = append(scoped, computer.ID)
+	}
+	if len(scoped) != 2 || scoped[0] != 7 || scoped[1] != 2 {
+		t.Errorf("computers sent = %v, want [7 2]", scoped)
+	}
+	if sent.Scope.ComputerGroups == nil || len(*sent.Scope.ComputerGroups) != 1 {
+		t.Errorf("computer groups sent = %+v, want the existing group kept", sent.Scope.ComputerGroups)
+	}
+}
@@ -0,0 +1,188 @@
+// util_laps_rotation.go
+// Bulk LAPS password rotation for the members of a computer group.
+// RotateLocalAdminPasswordsForComputerGroup resolves the group members to their client management IDs through
+// computer inventory, read for the members only, then rotates the LAPS accounts of each computer, at most
+// MaxConcurrency computers at a time. A computer which fails does not stop the others; every account is
+// reported with its outcome.
+
+package jamfpro
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultLAPSRotationConcurrency is the number of computers rotated at the same time by default.
+const DefaultLAPSRotationConcurrency = 5
+
+// lapsInventoryBatchSize is the number of computer IDs in the inventory filter of a single request, which keeps
+// the request URL short.
+const lapsInventoryBatchSize = 100
+
+// LAPSRotationOptions configures RotateLocalAdminPasswordsForComputerGroup. Zero values use the defaults.
+type LAPSRotationOptions struct {
+	MaxConcurrency int
+	// Usernames limits the rotation to these accounts; by default every LAPS account of a computer is rotated.
+	Usernames []string
+	// OnResult, when set, is called for every account once it is rotated or has failed. It may be called
+	// concurrently.
+	OnResult func(result LAPSRotationResult)
+}
+
+// LAPSRotationResult is the outcome of the rotation of a single account. Username is empty when the accounts
+// of the computer could not be listed.
+type LAPSRotationResult struct {
+	ComputerID   string `json:"computerId"`
+	ComputerName string `json:"computerName"`
+	ManagementId string `json:"managementId"`
+	Username     string `json:"username,omitempty"`
+	Rotated      bool   `json:"rotated"`
+	Error        string `json:"error,omitempty"`
+}
+
+// RotateLocalAdminPasswordsForComputerGroup rotates the LAPS passwords of all members of a smart or static
+// computer group.
+func (c *Client) RotateLocalAdminPasswordsForComputerGroup(groupID int, opts *LAPSRotationOptions) ([]LAPSRotationResult, error) {
+	if opts == nil {
+		opts = &LAPSRotationOptions{}
+	}
+	concurrency := opts.MaxConcurrency
+	if concurrency <= 0 {
+		concurrency = DefaultLAPSRotationConcurrency
+	}
+
+	group, err := c.GetComputerGroupByID(groupID)
+	if err != nil {
+		return nil, err
+	}
+	if group.Computers == nil || len(*group.Computers) == 0 {
+		return nil, nil
+	}
+
+	managementIDs, err := c.computerManagementIDs(*group.Computers)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		mu      sync.Mutex
+		results []LAPSRotationResult
+		wg      sync.WaitGroup
+	)
+	report := func(result LAPSRotationResult) {
+		mu.Lock()
+		results = append(results, result)
+		mu.Unlock()
+		if opts.OnResult != nil {
+			opts.OnResult(result)
+		}
+	}
+
+	semaphore := make(chan struct{}, concurrency)
+	for _, member := range *group.Computers {
+		computer := LAPSRotationResult{
+			ComputerID:   strconv.Itoa(member.ID),
+			ComputerName: member.Name,
+			ManagementId: managementIDs[strconv.Itoa(member.ID)],
+		}
+		if computer.ManagementId == "" {
+			computer.Error = "computer has no client management id"
+			report(computer)
+			continue
+		}
+
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			c.rotateComputerLocalAdminPasswords(computer, opts.Usernames, report)
+		}()
+	}
+	wg.Wait()
+
+	sort.SliceStable(results, func(i, j int) bool {
+		a, _ := strconv.Atoi(results[i].ComputerID)
+		b, _ := strconv.Atoi(results[j].ComputerID)
+		if a != b {
+			return a < b
+		}
+		return results[i].Username < results[j].Username
+	})
+
+	return results, nil
+}
+
+// computerManagementIDs returns the client management IDs of the given computers by computer ID, reading the
+// inventory of lapsInventoryBatchSize computers per request.
+func (c *Client) computerManagementIDs(computers []ComputerGroupSubsetComputer) (map[string]string, error) {
+	managementIDs := make(map[string]string, len(computers))
+	for start := 0; start < len(computers); start += lapsInventoryBatchSize {
+		end := start + lapsInventoryBatchSize
+		if end > len(computers) {
+			end = len(computers)
+		}
+
+		ids := make([]string, 0, end-start)
+		for _, computer := range computers[start:end] {
+			ids = append(ids, strconv.Itoa(computer.ID))
+		}
+
+		filter := "id=in=(" + strings.Join(ids, ",") + ")"
+		inventory, err := c.GetComputersInventory("&section=GENERAL&filter=" + url.QueryEscape(filter))
+		if err != nil {
+			return nil, err
+		}
+		for _, computer := range inventory.Results {
+			managementIDs[computer.ID] = computer.General.ManagementId
+		}
+	}
+
+	return managementIDs, nil
+}
+
+// rotateComputerLocalAdminPasswords rotates the LAPS accounts of a computer, limited to usernames when given.
+func (c *Client) rotateComputerLocalAdminPasswords(computer LAPSRotationResult, usernames []string, report func(LAPSRotationResult)) {
+	accounts, err := c.GetLocalAdminPasswordAccountsByClientManagementID(computer.ManagementId)
+	if err != nil {
+		computer.Error = err.Error()
+		report(computer)
+		return
+	}
+
+	wanted := make(map[string]bool, len(usernames))
+	for _, username := range usernames {
+		wanted[username] = true
+	}
+
+	rotated := make(map[string]bool, len(accounts.Results))
+	for _, account := range accounts.Results {
+		if len(wanted) > 0 && !wanted[account.Username] || rotated[account.Username] {
+			continue
+		}
+		rotated[account.Username] = true
+
+		result := computer
+		result.Username = account.Username
+		if err := c.RotateLocalAdminPasswordByClientManagementID(computer.ManagementId, account.Username); err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Rotated = true
+		}
+		report(result)
+	}
+
+	for _, username := range usernames {
+		if !rotated[username] {
+			rotated[username] = true
+			result := computer
+			result.Username = username
+			result.Error = fmt.Sprintf("account %s is not a LAPS account of the computer", username)
+			report(result)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package jamfpro
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestRotateLocalAdminPasswordsForComputerGroupFiltersInventory(t *testing.T) {
+	// 150 members, so the inventory is read in two batches; computer 150 is not in inventory.
+	var members strings.Builder
+	for id := 1; id <= lapsInventoryBatchSize+50; id++ {
+		fmt.Fprintf(&members, "<computer><id>%d</id><name>mac-%d</name></computer>", id, id)
+	}
+
+	var (
+		mu      sync.Mutex
+		filters []string
+		rotated []string
+	)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/JSSResource/computergroups/id/3", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		fmt.Fprintf(w, "<computer_group><id>3</id><name>LAPS</name><computers>%s</computers></computer_group>", members.String())
+	})
+	mux.HandleFunc("/api/v1/computers-inventory-detail", func(w http.ResponseWriter, r *http.Request) {
+		filter := r.URL.Query().Get("filter")
+		mu.Lock()
+		filters = append(filters, filter)
+		mu.Unlock()
+
+		ids := strings.Split(strings.TrimSuffix(strings.TrimPrefix(filter, "id=in=("), ")"), ",")
+		var computers []ResourceComputerInventory
+		for _, id := range ids {
+			if id == "150" {
+				continue
+			}
+			var computer ResourceComputerInventory
+			computer.ID = id
+			computer.General.ManagementId = "m-" + id
+			computers = append(computers, computer)
+		}
+		writeTestJSON(w, http.StatusOK, ResponseComputerInventoryList{TotalCount: len(computers), Results: computers})
+	})
+	mux.HandleFunc("/api/v2/local-admin-password/", func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/accounts") {
+			writeTestJSON(w, http.StatusOK, ResponseLocalAdminPasswordAccountList{TotalCount: 1, Results: []ResourceLocalAdminPasswordAccount{{Username: "admin"}}})
+			return
+		}
+		mu.Lock()
+		rotated = append(rotated, r.URL.Path)
+		mu.Unlock()
+		writeTestJSON(w, http.StatusOK, ResponseLocalAdminPasswordSet{})
+	})
+
+	results, err := newTestClient(t, mux).RotateLocalAdminPasswordsForComputerGroup(3, nil)
+	if err != nil {
+		t.Fatalf("RotateLocalAdminPasswordsForComputerGroup: %v", err)
+	}
+
+	if len(filters) != 2 || !strings.HasPrefix(filters[0], "id=in=(1,2,") || filters[1] != "id=in=(101,"+strings.Join(idRange(102, 150), ",")+")" {
+		t.Errorf("inventory filters = %q, want the members in two batches", filters)
+	}
+	if len(rotated) != 149 {
+		t.Errorf("%d accounts rotated, want 149", len(rotated))
+	}
+	if last := results[len(results)-1]; last.ComputerID != "150" || last.Error == "" {
+		t.Errorf("result of computer 150 = %+v, want it reported without management id", last)
+	}
+}
+
+// idRange returns the decimal IDs from first to last.
+func idRange(first, last int) []string {
+	var ids []string
+	for id := first; id <= last; id++ {
+		ids = append(ids, fmt.Sprint(id))
+	}
+	return ids
+}
@@ -0,0 +1,281 @@
+// util_managed_software_update_tracker.go
+// Progress tracking of managed software update plans across a group.
+// A plan's state says how far Jamf Pro got in scheduling the update, the update status reported by the device
+// how far the device got in applying it. TrackManagedSoftwareUpdatePlansByGroupID combines both into a single
+// phase per device: a finished plan decides the phase, otherwise the device's update status does.
+// Plans carry no timestamp, so a plan's creation time is taken from its event log. A device reports a single
+// update status, which may belong to an earlier update; it is only used when it was reported after the plan
+// was created and, for a plan to a specific version, its product key names that version.
+// Event logs are read one request per plan, so they are only read where the creation time matters: for devices
+// with several plans, and for devices with an unfinished plan and an update status to check against it.
+
+package jamfpro
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxManagedSoftwareUpdatePlanEventRequests caps the number of plan event logs read at the same time.
+const maxManagedSoftwareUpdatePlanEventRequests = 10
+
+// Managed software update phases
+const (
+	ManagedSoftwareUpdatePhasePending     = "pending"
+	ManagedSoftwareUpdatePhaseDownloading = "downloading"
+	ManagedSoftwareUpdatePhaseInstalling  = "installing"
+	ManagedSoftwareUpdatePhaseCompleted   = "completed"
+	ManagedSoftwareUpdatePhaseFailed      = "failed"
+	ManagedSoftwareUpdatePhaseCanceled    = "canceled"
+)
+
+// ManagedSoftwareUpdateDeviceProgress is the progress of the plan of a single device.
+type ManagedSoftwareUpdateDeviceProgress struct {
+	DeviceId   string `json:"deviceId"`
+	ObjectType string `json:"objectType"`
+	PlanUuid   string `json:"planUuid"`
+	PlanState  string `json:"planState"`
+	// PlanCreated is zero when the plan's event log could not be read, or was not needed: the device has a
+	// single plan, which is finished or has no update status to check.
+	PlanCreated             time.Time `json:"planCreated"`
+	UpdateStatus            string    `json:"updateStatus,omitempty"`
+	DownloadPercentComplete float64   `json:"downloadPercentComplete"`
+	Phase                   string    `json:"phase"`
+	FailureReasons          []string  `json:"failureReasons,omitempty"`
+}
+
+// ManagedSoftwareUpdateGroupProgress is the progress of the plans of a group, with the number of devices per phase.
+type ManagedSoftwareUpdateGroupProgress struct {
+	GroupId   string                                `json:"groupId"`
+	GroupType string                                `json:"groupType"`
+	Counts    map[string]int                        `json:"counts"`
+	Devices   []ManagedSoftwareUpdateDeviceProgress `json:"devices"`
+}
+
+// TrackManagedSoftwareUpdatePlansByGroupID reports the progress of the managed software update plans of a
+// computer or mobile device group. A device with several plans is reported by its most recently created plan;
+// when the creation times cannot be told apart, by its unfinished plan, if any.
+func (c *Client) TrackManagedSoftwareUpdatePlansByGroupID(groupId string, groupType string) (*ManagedSoftwareUpdateGroupProgress, error) {
+	plans, err := c.GetManagedSoftwareUpdatePlansByGroupID(groupId, groupType)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses, err := c.GetManagedSoftwareUpdateStatusesByGroupID(groupId, groupType)
+	if err != nil {
+		return nil, err
+	}
+
+	// A group holds a single device type, so devices are told apart by id.
+	statusByDevice := make(map[string]ResourceManagedSoftwareUpdateStatus, len(statuses.Results))
+	for _, status := range statuses.Results {
+		statusByDevice[status.Device.DeviceId] = status
+	}
+
+	plansByDevice := make(map[string][]ResourceManagedSoftwareUpdatePlanList, len(plans.Results))
+	for _, plan := range plans.Results {
+		plansByDevice[plan.Device.DeviceId] = append(plansByDevice[plan.Device.DeviceId], plan)
+	}
+
+	var timedPlans []string
+	for deviceId, devicePlans := range plansByDevice {
+		_, reported := statusByDevice[deviceId]
+		if len(devicePlans) > 1 || reported && !isManagedSoftwareUpdatePlanFinished(devicePlans[0].Status.State) {
+			for _, plan := range devicePlans {
+				timedPlans = append(timedPlans, plan.PlanUuid)
+			}
+		}
+	}
+	createdByPlan := c.managedSoftwareUpdatePlansCreated(timedPlans)
+
+	planByDevice := make(map[string]ResourceManagedSoftwareUpdatePlanList, len(plansByDevice))
+	for _, plan := range plans.Results {
+		current, ok := planByDevice[plan.Device.DeviceId]
+		if ok && !isLaterManagedSoftwareUpdatePlan(plan, createdByPlan[plan.PlanUuid], current, createdByPlan[current.PlanUuid]) {
+			continue
+		}
+		planByDevice[plan.Device.DeviceId] = plan
+	}
+
+	progress := &ManagedSoftwareUpdateGroupProgress{
+		GroupId:   groupId,
+		GroupType: groupType,
+		Counts:    make(map[string]int),
+	}
+	for deviceId, plan := range planByDevice {
+		device := ManagedSoftwareUpdateDeviceProgress{
+			DeviceId:    plan.Device.DeviceId,
+			ObjectType:  plan.Device.ObjectType,
+			PlanUuid:    plan.PlanUuid,
+			PlanState:   plan.Status.State,
+			PlanCreated: createdByPlan[plan.PlanUuid],
+		}
+		status, reported := statusByDevice[deviceId]
+		if reported && managedSoftwareUpdateStatusBelongsToPlan(status, plan, device.PlanCreated) {
+			device.UpdateStatus = status.Status
+			device.DownloadPercentComplete = status.DownloadPercentComplete
+		}
+		device.Phase, device.FailureReasons = managedSoftwareUpdatePhase(plan, device.UpdateStatus)
+
+		progress.Counts[device.Phase]++
+		progress.Devices = append(progress.Devices, device)
+	}
+
+	sort.Slice(progress.Devices, func(i, j int) bool {
+		return progress.Devices[i].DeviceId < progress.Devices[j].DeviceId
+	})
+
+	return progress, nil
+}
+
+// managedSoftwareUpdatePhase returns the phase of a device from its plan and reported update status, and the
+// reasons of a failure.
+func managedSoftwareUpdatePhase(plan ResourceManagedSoftwareUpdatePlanList, updateStatus string) (string, []string) {
+	switch plan.Status.State {
+	case ManagedSoftwareUpdatePlanStateCompleted:
+		return ManagedSoftwareUpdatePhaseCompleted, nil
+	case ManagedSoftwareUpdatePlanStateCanceled:
+		return ManagedSoftwareUpdatePhaseCanceled, nil
+	case ManagedSoftwareUpdatePlanStateFailed, ManagedSoftwareUpdatePlanStateException, ManagedSoftwareUpdatePlanStateRejecting:
+		reasons := plan.Status.ErrorReasons
+		if len(reasons) == 0 {
+			reasons = []string{plan.Status.State}
+		}
+		return ManagedSoftwareUpdatePhaseFailed, reasons
+	}
+
+	switch {
+	case updateStatus == ManagedSoftwareUpdateStatusDownloading:
+		return ManagedSoftwareUpdatePhaseDownloading, nil
+	case updateStatus == ManagedSoftwareUpdateStatusDownloaded, updateStatus == ManagedSoftwareUpdateStatusInstalling:
+		return ManagedSoftwareUpdatePhaseInstalling, nil
+	case updateStatus == ManagedSoftwareUpdateStatusInstalled:
+		return ManagedSoftwareUpdatePhaseCompleted, nil
+	case updateStatus == ManagedSoftwareUpdateStatusError,
+		strings.HasSuffix(updateStatus, "_FAILED"),
+		strings.HasPrefix(updateStatus, "DOWNLOAD_"),
+		strings.HasPrefix(updateStatus, "INSTALL_"):
+		// e.g. DOWNLOAD_INSUFFICIENT_SPACE or INSTALL_PHONE_CALL_IN_PROGRESS
+		return ManagedSoftwareUpdatePhaseFailed, append([]string{updateStatus}, plan.Status.ErrorReasons...)
+	}
+
+	return ManagedSoftwareUpdatePhasePending, nil
+}
+
+// managedSoftwareUpdatePlansCreated reads the creation times of the given plans, at most
+// maxManagedSoftwareUpdatePlanEventRequests at a time.
+func (c *Client) managedSoftwareUpdatePlansCreated(planUuids []string) map[string]time.Time {
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	created := make(map[string]time.Time, len(planUuids))
+
+	semaphore := make(chan struct{}, maxManagedSoftwareUpdatePlanEventRequests)
+	for _, planUuid := range planUuids {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(planUuid string) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			at := c.managedSoftwareUpdatePlanCreated(planUuid)
+			mu.Lock()
+			created[planUuid] = at
+			mu.Unlock()
+		}(planUuid)
+	}
+	wg.Wait()
+
+	return created
+}
+
+// managedSoftwareUpdatePlanCreated returns the time of the earliest event in the plan's event log, or zero when
+// the log cannot be read or holds no timestamp.
+func (c *Client) managedSoftwareUpdatePlanCreated(planUuid string) time.Time {
+	events, err := c.GetManagedSoftwareUpdatePlanEventsByID(planUuid)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return earliestManagedSoftwareUpdateEvent(events.Events)
+}
+
+// earliestManagedSoftwareUpdateEvent returns the earliest timestamp in a plan event log. Events differ per type,
+// but each carries the time it was sent or received in a field ending in "Epoch", in epoch milliseconds.
+func earliestManagedSoftwareUpdateEvent(events string) time.Time {
+	var log struct {
+		Events []map[string]interface{} `json:"events"`
+	}
+	if err := json.Unmarshal([]byte(events), &log); err != nil {
+		return time.Time{}
+	}
+
+	var earliest time.Time
+	for _, event := range log.Events {
+		for key, value := range event {
+			epoch, ok := value.(float64)
+			if !ok || epoch <= 0 || !strings.HasSuffix(key, "Epoch") {
+				continue
+			}
+			if at := time.UnixMilli(int64(epoch)); earliest.IsZero() || at.Before(earliest) {
+				earliest = at
+			}
+		}
+	}
+
+	return earliest
+}
+
+// isLaterManagedSoftwareUpdatePlan reports whether plan supersedes current as the plan of a device: it was
+// created later, or, when that cannot be told, it is unfinished and current is not.
+func isLaterManagedSoftwareUpdatePlan(plan ResourceManagedSoftwareUpdatePlanList, created time.Time, current ResourceManagedSoftwareUpdatePlanList, currentCreated time.Time) bool {
+	if !created.IsZero() && !currentCreated.IsZero() && !created.Equal(currentCreated) {
+		return created.After(currentCreated)
+	}
+
+	return isManagedSoftwareUpdatePlanFinished(current.Status.State) && !isManagedSoftwareUpdatePlanFinished(plan.Status.State)
+}
+
+// managedSoftwareUpdateStatusBelongsToPlan reports whether a device's update status was reported for the plan:
+// after the plan was created and, for a specific version, with a product key naming that version. A status is
+// never attributed to a plan whose creation time is unknown.
+func managedSoftwareUpdateStatusBelongsToPlan(status ResourceManagedSoftwareUpdateStatus, plan ResourceManagedSoftwareUpdatePlanList, planCreated time.Time) bool {
+	if planCreated.IsZero() {
+		return false
+	}
+
+	reported := status.Updated
+	if reported == "" {
+		reported = status.Created
+	}
+	reportedAt, ok := parseManagedSoftwareUpdateTime(reported)
+	if !ok || reportedAt.Before(planCreated) {
+		return false
+	}
+
+	return plan.SpecificVersion == "" || strings.Contains(status.ProductKey, plan.SpecificVersion)
+}
+
+// parseManagedSoftwareUpdateTime parses the timestamps of update statuses.
+func parseManagedSoftwareUpdateTime(value string) (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.000Z0700", "2006-01-02T15:04:05"} {
+		if at, err := time.Parse(layout, value); err == nil {
+			return at, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// isManagedSoftwareUpdatePlanFinished reports whether a plan state is final.
+func isManagedSoftwareUpdatePlanFinished(state string) bool {
+	switch state {
+	case ManagedSoftwareUpdatePlanStateCompleted, ManagedSoftwareUpdatePlanStateFailed, ManagedSoftwareUpdatePlanStateException,
+		ManagedSoftwareUpdatePlanStateCanceled, ManagedSoftwareUpdatePlanStateRejecting:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,210 @@
+package jamfpro
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEarliestManagedSoftwareUpdateEvent(t *testing.T) {
+	tests := []struct {
+		name   string
+		events string
+		want   time.Time
+	}{
+		{
+			name:   "earliest of several events",
+			events: `{"events":[{"type":".ProcessPlanEvent","eventReceivedEpoch":1717002000000},{"type":".PlanCreated_Jamf","eventSentEpoch":1717000000000,"eventReceivedEpoch":1717000001000}]}`,
+			want:   time.UnixMilli(1717000000000),
+		},
+		{name: "no timestamps", events: `{"events":[{"type":".PlanCreated_Jamf"}]}`},
+		{name: "empty log", events: `{"events":[]}`},
+		{name: "not json", events: `events`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := earliestManagedSoftwareUpdateEvent(tt.events); !got.Equal(tt.want) {
+				t.Errorf("earliestManagedSoftwareUpdateEvent = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestManagedSoftwareUpdateStatusBelongsToPlan(t *testing.T) {
+	created := time.Date(2024, 5, 29, 12, 0, 0, 0, time.UTC)
+	specific := ResourceManagedSoftwareUpdatePlanList{SpecificVersion: "14.5"}
+	latest := ResourceManagedSoftwareUpdatePlanList{VersionType: "LATEST_ANY"}
+
+	tests := []struct {
+		name    string
+		status  ResourceManagedSoftwareUpdateStatus
+		plan    ResourceManagedSoftwareUpdatePlanList
+		created time.Time
+		want    bool
+	}{
+		{
+			name:    "matching version reported after the plan",
+			status:  ResourceManagedSoftwareUpdateStatus{ProductKey: "MSU_UPDATE_23F79_patch_14.5_minor", Updated: "2024-05-29T13:00:00Z"},
+			plan:    specific,
+			created: created,
+			want:    true,
+		},
+		{
+			name:    "status of an earlier update",
+			status:  ResourceManagedSoftwareUpdateStatus{ProductKey: "MSU_UPDATE_23F79_patch_14.5_minor", Updated: "2024-05-28T13:00:00Z"},
+			plan:    specific,
+			created: created,
+		},
+		{
+			name:    "other version",
+			status:  ResourceManagedSoftwareUpdateStatus{ProductKey: "MSU_UPDATE_23E224_patch_14.4.1_minor", Updated: "2024-05-29T13:00:00Z"},
+			plan:    specific,
+			created: created,
+		},
+		{
+			name:    "latest version plan only needs a later status",
+			status:  ResourceManagedSoftwareUpdateStatus{ProductKey: "MSU_UPDATE_23E224_patch_14.4.1_minor", Created: "2024-05-29T12:30:00.000Z"},
+			plan:    latest,
+			created: created,
+			want:    true,
+		},
+		{
+			name:   "plan creation unknown",
+			status: ResourceManagedSoftwareUpdateStatus{ProductKey: "MSU_UPDATE_23F79_patch_14.5_minor", Updated: "2024-05-29T13:00:00Z"},
+			plan:   specific,
+		},
+		{
+			name:    "status without timestamp",
+			status:  ResourceManagedSoftwareUpdateStatus{ProductKey: "MSU_UPDATE_23F79_patch_14.5_minor"},
+			plan:    specific,
+			created: created,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := managedSoftwareUpdateStatusBelongsToPlan(tt.status, tt.plan, tt.created); got != tt.want {
+				t.Errorf("managedSoftwareUpdateStatusBelongsToPlan = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrackManagedSoftwareUpdatePlansByGroupID(t *testing.T) {
+	plan := func(uuid, device, state string) ResourceManagedSoftwareUpdatePlanList {
+		return ResourceManagedSoftwareUpdatePlanList{
+			PlanUuid:        uuid,
+			Device:          ManagedSoftwareUpdatePlanListSubsetDevice{DeviceId: device, ObjectType: "COMPUTER"},
+			SpecificVersion: "14.5",
+			Status:          ManagedSoftwareUpdatePlanListSubsetStatus{State: state},
+		}
+	}
+	// The API lists the newer plan of device 1 first; device 2's status belongs to an earlier update.
+	plans := []ResourceManagedSoftwareUpdatePlanList{
+		plan("new-1", "1", "PlanCompleted"),
+		plan("old-1", "1", "Init"),
+		plan("plan-2", "2", "ProcessPlan"),
+	}
+	eventsByPlan := map[string]string{
+		"new-1":  `{"events":[{"eventReceivedEpoch":1717002000000}]}`,
+		"old-1":  `{"events":[{"eventReceivedEpoch":1716000000000}]}`,
+		"plan-2": `{"events":[{"eventReceivedEpoch":1717000000000}]}`,
+	}
+	statuses := []ResourceManagedSoftwareUpdateStatus{
+		{Device: ManagedSoftwareUpdatePlanListSubsetDevice{DeviceId: "2"}, ProductKey: "MSU_UPDATE_23E224_patch_14.4.1_minor", Status: "INSTALLED", Updated: "2024-05-20T00:00:00Z"},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/managed-software-updates/plans/group/7", func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, http.StatusOK, ResponseManagedSoftwareUpdatePlanList{TotalCount: len(plans), Results: plans})
+	})
+	mux.HandleFunc("/api/v1/managed-software-updates/plans/", func(w http.ResponseWriter, r *http.Request) {
+		uuid := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/v1/managed-software-updates/plans/"), "/events")
+		writeTestJSON(w, http.StatusOK, ResourceManagedSoftwareUpdatePlanEvents{Events: eventsByPlan[uuid]})
+	})
+	mux.HandleFunc("/api/v1/managed-software-updates/update-statuses/computer-groups/7", func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, http.StatusOK, ResponseManagedSoftwareUpdateStatusList{TotalCount: len(statuses), Results: statuses})
+	})
+
+	progress, err := newTestClient(t, mux).TrackManagedSoftwareUpdatePlansByGroupID("7", ManagedSoftwareUpdateGroupTypeComputer)
+	if err != nil {
+		t.Fatalf("TrackManagedSoftwareUpdatePlansByGroupID: %v", err)
+	}
+
+	if len(progress.Devices) != 2 {
+		t.Fatalf("devices = %+v, want two", progress.Devices)
+	}
+	if device := progress.Devices[0]; device.PlanUuid != "new-1" || device.Phase != ManagedSoftwareUpdatePhaseCompleted {
+		t.Errorf("device 1 = %+v, want its newest plan", device)
+	}
+	if device := progress.Devices[1]; device.UpdateStatus != "" || device.Phase != ManagedSoftwareUpdatePhasePending {
+		t.Errorf("device 2 = %+v, want the stale status ignored", device)
+	}
+}
+
+func TestTrackManagedSoftwareUpdatePlansReadsOnlyNeededEventLogs(t *testing.T) {
+	plan := func(uuid, device, state string) ResourceManagedSoftwareUpdatePlanList {
+		return ResourceManagedSoftwareUpdatePlanList{
+			PlanUuid: uuid,
+			Device:   ManagedSoftwareUpdatePlanListSubsetDevice{DeviceId: device, ObjectType: "COMPUTER"},
+			Status:   ManagedSoftwareUpdatePlanListSubsetStatus{State: state},
+		}
+	}
+	// Device 1 has a finished plan and device 2 no update status: their event logs are not needed. Devices 10
+	// to 39 have an unfinished plan and an update status, so theirs are.
+	plans := []ResourceManagedSoftwareUpdatePlanList{plan("done-1", "1", "PlanCompleted"), plan("quiet-2", "2", "Init")}
+	statuses := []ResourceManagedSoftwareUpdateStatus{{Device: ManagedSoftwareUpdatePlanListSubsetDevice{DeviceId: "1"}, Status: "INSTALLED"}}
+	for device := 10; device < 40; device++ {
+		id := fmt.Sprint(device)
+		plans = append(plans, plan("plan-"+id, id, "ProcessPlan"))
+		statuses = append(statuses, ResourceManagedSoftwareUpdateStatus{Device: ManagedSoftwareUpdatePlanListSubsetDevice{DeviceId: id}, Status: "DOWNLOADING"})
+	}
+
+	var (
+		mu                    sync.Mutex
+		requested             = map[string]bool{}
+		inFlight, maxInFlight int
+	)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/managed-software-updates/plans/group/7", func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, http.StatusOK, ResponseManagedSoftwareUpdatePlanList{TotalCount: len(plans), Results: plans})
+	})
+	mux.HandleFunc("/api/v1/managed-software-updates/plans/", func(w http.ResponseWriter, r *http.Request) {
+		uuid := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/v1/managed-software-updates/plans/"), "/events")
+		mu.Lock()
+		requested[uuid] = true
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+
+		time.Sleep(5 * time.Millisecond)
+		writeTestJSON(w, http.StatusOK, ResourceManagedSoftwareUpdatePlanEvents{Events: `{"events":[{"eventReceivedEpoch":1717000000000}]}`})
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+	})
+	mux.HandleFunc("/api/v1/managed-software-updates/update-statuses/computer-groups/7", func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, http.StatusOK, ResponseManagedSoftwareUpdateStatusList{TotalCount: len(statuses), Results: statuses})
+	})
+
+	if _, err := newTestClient(t, mux).TrackManagedSoftwareUpdatePlansByGroupID("7", ManagedSoftwareUpdateGroupTypeComputer); err != nil {
+		t.Fatalf("TrackManagedSoftwareUpdatePlansByGroupID: %v", err)
+	}
+
+	if requested["done-1"] || requested["quiet-2"] {
+		t.Errorf("event logs read = %v, want none for devices 1 and 2", requested)
+	}
+	if len(requested) != 30 {
+		t.Errorf("%d event logs read, want 30", len(requested))
+	}
+	if maxInFlight > maxManagedSoftwareUpdatePlanEventRequests {
+		t.Errorf("%d event logs read at the same time, want at most %d", maxInFlight, maxManagedSoftwareUpdatePlanEventRequests)
+	}
+}
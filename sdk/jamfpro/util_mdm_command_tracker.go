@@ -0,0 +1,378 @@
+// util_mdm_command_tracker.go
+// Waits for issued MDM commands to reach a terminal state.
+// Commands sent with SendComputerCommand, SendMobileDeviceCommand or SendMDMCommand are tracked by
+// polling the status source that matches the API used to send them:
+//   - computer history (Classic API): the Commands subset of the target computer
+//   - mobile device history (Classic API): the ManagementCommands subset of the target device
+//   - the Jamf Pro API MDM command list, which also reports why a command failed
+//
+// Classic history entries carry no command UUID, so they are matched by command name and by being
+// issued, completed or failed after the command was sent. The history sources therefore require the
+// time the command was sent; without it an earlier command of the same name would match.
+
+package jamfpro
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Status sources used to track MDM commands
+const (
+	MDMCommandSourceComputerHistory     = "computer_history"
+	MDMCommandSourceMobileDeviceHistory = "mobile_device_history"
+	MDMCommandSourceProAPI              = "pro_api"
+)
+
+// Terminal statuses reported by TrackMDMCommands
+const (
+	MDMCommandTrackerStatusCompleted = "Completed"
+	MDMCommandTrackerStatusFailed    = "Failed"
+	MDMCommandTrackerStatusPending   = "Pending"
+	MDMCommandTrackerStatusTimedOut  = "TimedOut"
+	MDMCommandTrackerStatusCancelled = "Cancelled"
+)
+
+// Default MDM command tracker settings
+const (
+	DefaultMDMCommandPollInterval = 15 * time.Second
+	DefaultMDMCommandTimeout      = 30 * time.Minute
+)
+
+// historyMatchTolerance allows for clock skew between the client and Jamf Pro when matching history entries.
+const historyMatchTolerance = 2 * time.Minute
+
+// MDMCommandTrackerOptions configures TrackMDMCommands. Zero values use the defaults.
+type MDMCommandTrackerOptions struct {
+	PollInterval time.Duration
+	Timeout      time.Duration
+	// OnUpdate, when set, is called every time a command reaches a terminal status.
+	OnUpdate func(result MDMCommandResult)
+}
+
+// TrackedMDMCommand identifies an issued command on a single device.
+type TrackedMDMCommand struct {
+	UUID   string
+	Source string
+	// DeviceID is the Classic API computer or mobile device ID, required by the history sources.
+	DeviceID int
+	// ManagementID is the client management ID, reported by the Jamf Pro API source.
+	ManagementID string
+	// CommandName is matched against history entries, e.g. DeviceLock. Required by the history sources.
+	CommandName string
+	// IssuedAt is when the command was sent. History entries older than this are ignored. Required by the
+	// history sources.
+	IssuedAt time.Time
+}
+
+// MDMCommandResult is the terminal status of a tracked command on a single device.
+type MDMCommandResult struct {
+	UUID          string `json:"uuid"`
+	Source        string `json:"source"`
+	DeviceID      int    `json:"deviceId,omitempty"`
+	ManagementID  string `json:"managementId,omitempty"`
+	CommandName   string `json:"commandName,omitempty"`
+	Status        string `json:"status"`
+	FailureReason string `json:"failureReason,omitempty"`
+	CompletedAt   string `json:"completedAt,omitempty"`
+}
+
+// TrackedComputerCommands returns the commands to track for a SendComputerCommand response.
+func TrackedComputerCommands(response *ResponseComputerCommandCreate, issuedAt time.Time) []TrackedMDMCommand {
+	tracked := make([]TrackedMDMCommand, 0, len(response.Commands))
+	for _, command := range response.Commands {
+		tracked = append(tracked, TrackedMDMCommand{
+			UUID:        command.CommandUUID,
+			Source:      MDMCommandSourceComputerHistory,
+			DeviceID:    command.ComputerID,
+			CommandName: command.Name,
+			IssuedAt:    issuedAt,
+		})
+	}
+	return tracked
+}
+
+// TrackedMobileDeviceCommands returns the commands to track for a SendMobileDeviceCommand response, one
+// per target device. deviceIDs are used when the response does not list the target devices.
+func TrackedMobileDeviceCommands(response *ResponseMobileDeviceCommand, issuedAt time.Time, deviceIDs ...int) []TrackedMDMCommand {
+	if len(response.MobileDevices) > 0 {
+		deviceIDs = make([]int, 0, len(response.MobileDevices))
+		for _, device := range response.MobileDevices {
+			deviceIDs = append(deviceIDs, device.ID)
+		}
+	}
+
+	tracked := make([]TrackedMDMCommand, 0, len(deviceIDs))
+	for _, id := range deviceIDs {
+		tracked = append(tracked, TrackedMDMCommand{
+			UUID:        response.UUID,
+			Source:      MDMCommandSourceMobileDeviceHistory,
+			DeviceID:    id,
+			CommandName: response.Command,
+			IssuedAt:    issuedAt,
+		})
+	}
+	return tracked
+}
+
+// TrackedMDMCommands returns the commands to track for a SendMDMCommand response.
+func TrackedMDMCommands(response ResponseMDMCommandsCreate, issuedAt time.Time) []TrackedMDMCommand {
+	tracked := make([]TrackedMDMCommand, 0, len(response))
+	for _, command := range response {
+		tracked = append(tracked, TrackedMDMCommand{UUID: command.ID, Source: MDMCommandSourceProAPI, IssuedAt: issuedAt})
+	}
+	return tracked
+}
+
+// TrackMDMCommands polls the status source of each command until every command has completed or failed,
+// the timeout expires or ctx is cancelled. A result is returned for every command, in input order; commands
+// still pending when tracking stops are reported as TimedOut or Cancelled. The error is non nil only when
+// tracking stopped early because ctx was cancelled, or a command could not be tracked at all.
+func (c *Client) TrackMDMCommands(ctx context.Context, commands []TrackedMDMCommand, opts *MDMCommandTrackerOptions) ([]MDMCommandResult, error) {
+	pollInterval := DefaultMDMCommandPollInterval
+	timeout := DefaultMDMCommandTimeout
+	var onUpdate func(MDMCommandResult)
+	if opts != nil {
+		if opts.PollInterval > 0 {
+			pollInterval = opts.PollInterval
+		}
+		if opts.Timeout > 0 {
+			timeout = opts.Timeout
+		}
+		onUpdate = opts.OnUpdate
+	}
+
+	results := make([]MDMCommandResult, len(commands))
+	for i, command := range commands {
+		if err := validateTrackedMDMCommand(command); err != nil {
+			return nil, fmt.Errorf("failed to track mdm command %d: %v", i, err)
+		}
+		results[i] = MDMCommandResult{
+			UUID:         command.UUID,
+			Source:       command.Source,
+			DeviceID:     command.DeviceID,
+			ManagementID: command.ManagementID,
+			CommandName:  command.CommandName,
+			Status:       MDMCommandTrackerStatusPending,
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		pending := 0
+		for i, command := range commands {
+			if results[i].Status != MDMCommandTrackerStatusPending {
+				continue
+			}
+
+			result, err := c.pollMDMCommand(command)
+			if err != nil {
+				c.HTTP.Logger.Warn(fmt.Sprintf("failed to poll mdm command %s: %v", command.UUID, err))
+				pending++
+				continue
+			}
+
+			results[i].Status = result.Status
+			results[i].FailureReason = result.FailureReason
+			results[i].CompletedAt = result.CompletedAt
+			if result.ManagementID != "" {
+				results[i].ManagementID = result.ManagementID
+			}
+
+			if results[i].Status == MDMCommandTrackerStatusPending {
+				pending++
+			} else if onUpdate != nil {
+				onUpdate(results[i])
+			}
+		}
+
+		if pending == 0 {
+			return results, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			status, reason := MDMCommandTrackerStatusCancelled, "tracking was cancelled while the command was pending"
+			if ctx.Err() == context.DeadlineExceeded {
+				status, reason = MDMCommandTrackerStatusTimedOut, fmt.Sprintf("command still pending after %s", timeout)
+			}
+			for i := range results {
+				if results[i].Status == MDMCommandTrackerStatusPending {
+					results[i].Status = status
+					results[i].FailureReason = reason
+					if onUpdate != nil {
+						onUpdate(results[i])
+					}
+				}
+			}
+			if status == MDMCommandTrackerStatusCancelled {
+				return results, ctx.Err()
+			}
+			return results, nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// validateTrackedMDMCommand checks that command has the fields required by its status source.
+func validateTrackedMDMCommand(command TrackedMDMCommand) error {
+	switch command.Source {
+	case MDMCommandSourceComputerHistory, MDMCommandSourceMobileDeviceHistory:
+		if command.DeviceID == 0 || command.CommandName == "" || command.IssuedAt.IsZero() {
+			return fmt.Errorf("%s tracking requires a device id, a command name and the time the command was issued", command.Source)
+		}
+	case MDMCommandSourceProAPI:
+		if command.UUID == "" {
+			return fmt.Errorf("%s tracking requires a command uuid", command.Source)
+		}
+	default:
+		return fmt.Errorf("unsupported mdm command source: %s", command.Source)
+	}
+	return nil
+}
+
+// pollMDMCommand returns the current status of a single tracked command.
+func (c *Client) pollMDMCommand(command TrackedMDMCommand) (*MDMCommandResult, error) {
+	switch command.Source {
+	case MDMCommandSourceComputerHistory:
+		return c.pollComputerHistoryCommand(command)
+	case MDMCommandSourceMobileDeviceHistory:
+		return c.pollMobileDeviceHistoryCommand(command)
+	default:
+		return c.pollProAPICommand(command)
+	}
+}
+
+// pollComputerHistoryCommand looks the command up in the Commands subset of the computer's history.
+func (c *Client) pollComputerHistoryCommand(command TrackedMDMCommand) (*MDMCommandResult, error) {
+	history, err := c.GetComputerHistoryByComputerIDAndDataSubset(command.DeviceID, "Commands")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range history.Commands.Failed {
+		if historyEntryMatches(command, entry.Name, entry.IssuedEpoch, entry.FailedEpoch) {
+			reason := entry.Status
+			if reason == "" {
+				reason = "command failed on the computer"
+			}
+			return &MDMCommandResult{Status: MDMCommandTrackerStatusFailed, FailureReason: reason, CompletedAt: entry.FailedUTC}, nil
+		}
+	}
+
+	for _, entry := range history.Commands.Completed {
+		if historyEntryMatches(command, entry.Name, entry.IssuedEpoch, entry.CompletedEpoch) {
+			return &MDMCommandResult{Status: MDMCommandTrackerStatusCompleted, CompletedAt: entry.CompletedUTC}, nil
+		}
+	}
+
+	return &MDMCommandResult{Status: MDMCommandTrackerStatusPending}, nil
+}
+
+// pollMobileDeviceHistoryCommand looks the command up in the ManagementCommands subset of the device's history.
+func (c *Client) pollMobileDeviceHistoryCommand(command TrackedMDMCommand) (*MDMCommandResult, error) {
+	history, err := c.GetMobileDeviceHistoryByIDAndDataSubset(command.DeviceID, "ManagementCommands")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range history.ManagementCommands.Failed {
+		if historyEntryMatches(command, entry.Name, entry.IssuedEpoch, entry.FailedEpoch) {
+			reason := entry.Error
+			if reason == "" {
+				reason = entry.Status
+			}
+			if reason == "" {
+				reason = "command failed on the mobile device"
+			}
+			return &MDMCommandResult{Status: MDMCommandTrackerStatusFailed, FailureReason: reason, CompletedAt: entry.FailedUTC}, nil
+		}
+	}
+
+	for _, entry := range history.ManagementCommands.Completed {
+		if historyEntryMatches(command, entry.Name, entry.IssuedEpoch, entry.CompletedEpoch) {
+			return &MDMCommandResult{Status: MDMCommandTrackerStatusCompleted, CompletedAt: entry.CompletedUTC}, nil
+		}
+	}
+
+	return &MDMCommandResult{Status: MDMCommandTrackerStatusPending}, nil
+}
+
+// pollProAPICommand looks the command up in the Jamf Pro API MDM command list.
+func (c *Client) pollProAPICommand(command TrackedMDMCommand) (*MDMCommandResult, error) {
+	commands, err := c.GetMDMCommands("&filter=" + url.QueryEscape(fmt.Sprintf("uuid==%q", command.UUID)))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range commands.Results {
+		if entry.UUID != command.UUID {
+			continue
+		}
+		if command.ManagementID != "" && entry.Client.ManagementID != command.ManagementID {
+			continue
+		}
+
+		result := &MDMCommandResult{ManagementID: entry.Client.ManagementID, CompletedAt: entry.DateCompleted}
+		switch normalizeMDMCommandState(entry.CommandState) {
+		case "acknowledged", "completed":
+			result.Status = MDMCommandTrackerStatusCompleted
+		case "error", "failed":
+			result.Status = MDMCommandTrackerStatusFailed
+			result.FailureReason = mdmCommandFailureReason(entry)
+		default:
+			result.Status = MDMCommandTrackerStatusPending
+			result.CompletedAt = ""
+		}
+		return result, nil
+	}
+
+	return &MDMCommandResult{Status: MDMCommandTrackerStatusPending}, nil
+}
+
+// historyEntryMatches reports whether a history entry is the tracked command. Names are compared ignoring
+// case and spaces, as history shows display names such as "Device Lock" for DeviceLock. Nothing matches a
+// command without IssuedAt, as any earlier command of the same name would.
+func historyEntryMatches(command TrackedMDMCommand, name string, epochs ...int64) bool {
+	if command.IssuedAt.IsZero() || normalizeMDMCommandState(name) != normalizeMDMCommandState(command.CommandName) {
+		return false
+	}
+
+	threshold := command.IssuedAt.Add(-historyMatchTolerance).UnixMilli()
+	for _, epoch := range epochs {
+		if epoch >= threshold {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeMDMCommandState lower cases s and removes spaces and underscores, e.g. NOT_NOW becomes notnow.
+func normalizeMDMCommandState(s string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "_", "").Replace(s))
+}
+
+// mdmCommandFailureReason summarises the error details reported for a failed Jamf Pro API command.
+func mdmCommandFailureReason(command ResourceMDMCommand) string {
+	var reasons []string
+	for _, detail := range command.ErrorDetails {
+		reason := detail.Description
+		if detail.Domain != "" || detail.Code != 0 {
+			reason = fmt.Sprintf("%s (%s %d)", detail.Description, detail.Domain, detail.Code)
+		}
+		reasons = append(reasons, strings.TrimSpace(reason))
+	}
+
+	if len(reasons) == 0 {
+		return "command returned an error"
+	}
+	return strings.Join(reasons, "; ")
+}
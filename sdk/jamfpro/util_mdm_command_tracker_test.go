@@ -0,0 +1,92 @@
+package jamfpro
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHistoryEntryMatches(t *testing.T) {
+	issuedAt := time.Date(2024, 5, 29, 12, 0, 0, 0, time.UTC)
+	before := issuedAt.Add(-time.Hour).UnixMilli()
+	after := issuedAt.Add(time.Minute).UnixMilli()
+
+	tests := []struct {
+		name    string
+		command TrackedMDMCommand
+		entry   string
+		epochs  []int64
+		want    bool
+	}{
+		{
+			name:    "display name issued after the command",
+			command: TrackedMDMCommand{CommandName: "DeviceLock", IssuedAt: issuedAt},
+			entry:   "Device Lock",
+			epochs:  []int64{after},
+			want:    true,
+		},
+		{
+			name:    "earlier command of the same name",
+			command: TrackedMDMCommand{CommandName: "DeviceLock", IssuedAt: issuedAt},
+			entry:   "Device Lock",
+			epochs:  []int64{before, before},
+		},
+		{
+			name:    "other command",
+			command: TrackedMDMCommand{CommandName: "DeviceLock", IssuedAt: issuedAt},
+			entry:   "Erase Device",
+			epochs:  []int64{after},
+		},
+		{
+			name:    "command without issue time",
+			command: TrackedMDMCommand{CommandName: "DeviceLock"},
+			entry:   "Device Lock",
+			epochs:  []int64{before},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := historyEntryMatches(tt.command, tt.entry, tt.epochs...); got != tt.want {
+				t.Errorf("historyEntryMatches = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrackMDMCommandsRequiresIssuedAtForHistory(t *testing.T) {
+	command := TrackedMDMCommand{UUID: "u", Source: MDMCommandSourceComputerHistory, DeviceID: 1, CommandName: "DeviceLock"}
+
+	// No server is needed: the command is rejected before any request.
+	if _, err := (&Client{}).TrackMDMCommands(context.Background(), []TrackedMDMCommand{command}, nil); err == nil {
+		t.Error("a history command without issue time was tracked")
+	}
+}
+
+func TestPollProAPICommandEscapesFilter(t *testing.T) {
+	var rawQuery string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2/mdm/commands", func(w http.ResponseWriter, r *http.Request) {
+		rawQuery = r.URL.RawQuery
+		writeTestJSON(w, http.StatusOK, map[string]interface{}{
+			"totalCount": 1,
+			"results": []ResourceMDMCommand{
+				{UUID: "abc-123", Client: MDMCommandSubsetClient{ManagementID: "m-1"}, CommandState: "ACKNOWLEDGED"},
+			},
+		})
+	})
+
+	result, err := newTestClient(t, mux).pollProAPICommand(TrackedMDMCommand{UUID: "abc-123", Source: MDMCommandSourceProAPI})
+	if err != nil {
+		t.Fatalf("pollProAPICommand: %v", err)
+	}
+	if result.Status != MDMCommandTrackerStatusCompleted {
+		t.Errorf("status = %s, want %s", result.Status, MDMCommandTrackerStatusCompleted)
+	}
+	if strings.Contains(rawQuery, `"`) || !strings.Contains(rawQuery, "filter=uuid%3D%3D%22abc-123%22") {
+		t.Errorf("query = %s, want an escaped uuid filter", rawQuery)
+	}
+}
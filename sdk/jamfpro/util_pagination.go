@@ -54,8 +54,7 @@ func (c *Client) DoPaginatedGet(
 	var page = startingPageNumber
 
 	for {
-		endpoint := fmt.Sprintf("%s?page=%d&page-size=%d%s", endpoint_root, startingPageNumber, maxPageSize, sort_filter)
-		fmt.Println(endpoint)
+		endpoint := fmt.Sprintf("%s?page=%d&page-size=%d%s", endpoint_root, page, maxPageSize, sort_filter)
 		resp, err := c.HTTP.DoRequest(
 			"GET",
 			endpoint,
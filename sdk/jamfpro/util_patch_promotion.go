@@ -0,0 +1,319 @@
+// util_patch_promotion.go
+// Staged promotion of new patch title versions.
+// PromotePatchTitle moves a software title to its newest patch definition for which a package has been
+// uploaded. Packages are matched to definitions by the version in their name and added to the software title
+// configuration, then the target version of the Classic patch policies is raised ring by ring: a ring is only
+// promoted once every earlier ring runs the version and has completed enough installations.
+// Each run advances at most one ring, so the function is meant to be run on a schedule, e.g. daily. Progress
+// is read back from Jamf Pro on every run; there is no local state.
+
+package jamfpro
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Patch promotion actions
+const (
+	PatchPromotionActionMapPackage  = "map_package"
+	PatchPromotionActionPromoteRing = "promote_ring"
+	PatchPromotionActionWait        = "wait"
+	PatchPromotionActionUpToDate    = "up_to_date"
+)
+
+// PatchPromotionRing is a deployment stage, e.g. the pilot group, made up of Classic patch policies.
+type PatchPromotionRing struct {
+	Name           string
+	PatchPolicyIDs []int
+	// MinimumCompletion is the share of the ring's devices, 0 to 1, which must have completed the installation
+	// of the target version before the next ring is promoted. Zero promotes the next ring on the following run.
+	MinimumCompletion float64
+}
+
+// PatchPromotionOptions controls PromotePatchTitle.
+type PatchPromotionOptions struct {
+	SoftwareTitleConfigurationID string
+	// Rings are promoted in order, e.g. pilot first, then broad.
+	Rings []PatchPromotionRing
+	// PackageNamePrefix limits the packages considered to those whose name starts with it, case insensitively,
+	// e.g. "GoogleChrome-". Ignored when PackageMatcher is set. One of the two is required, so that a package
+	// of another title which happens to carry the same version is never mapped.
+	PackageNamePrefix string
+	// PackageMatcher reports whether a package is the installer of a version. By default a package matches
+	// when its name starts with PackageNamePrefix and contains the version as a whole, e.g. "121.0" matches
+	// "Chrome-121.0.pkg" but not "Chrome-121.0.1.pkg".
+	PackageMatcher func(packageName, version string) bool
+	// DryRun reports the actions which would be taken without changing anything.
+	DryRun bool
+}
+
+// PatchPromotionAction describes a step taken, or in a dry run to be taken, by PromotePatchTitle.
+type PatchPromotionAction struct {
+	Action        string
+	Ring          string
+	PatchPolicyID int
+	PackageID     int
+	Version       string
+	Detail        string
+}
+
+// PatchPromotionResult reports the outcome of PromotePatchTitle.
+type PatchPromotionResult struct {
+	// LatestVersion is the newest patch definition; TargetVersion the newest one with a package.
+	LatestVersion string
+	TargetVersion string
+	Actions       []PatchPromotionAction
+	DryRun        bool
+}
+
+// PromotePatchTitle maps newly uploaded packages to the patch definitions of a software title and promotes the
+// newest packaged version to the next ring which does not run it yet.
+func (c *Client) PromotePatchTitle(opts *PatchPromotionOptions) (*PatchPromotionResult, error) {
+	if opts == nil || opts.SoftwareTitleConfigurationID == "" {
+		return nil, fmt.Errorf("a software title configuration id is required")
+	}
+	if len(opts.Rings) == 0 {
+		return nil, fmt.Errorf("at least one promotion ring is required")
+	}
+	if opts.PackageNamePrefix == "" && opts.PackageMatcher == nil {
+		return nil, fmt.Errorf("a package name prefix or package matcher is required")
+	}
+
+	id := opts.SoftwareTitleConfigurationID
+	result := &PatchPromotionResult{DryRun: opts.DryRun}
+
+	configuration, err := c.GetPatchSoftwareTitleConfigurationById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	definitions, err := c.GetPatchSoftwareTitleDefinitionsById(id, "")
+	if err != nil {
+		return nil, err
+	}
+	if len(definitions.Results) == 0 {
+		return nil, fmt.Errorf("software title configuration %s has no patch definitions", id)
+	}
+	sortPatchDefinitionsLatestFirst(definitions.Results)
+	result.LatestVersion = definitions.Results[0].Version
+
+	if err := c.mapPatchPackages(configuration, definitions.Results, opts, result); err != nil {
+		return result, err
+	}
+
+	packaged := make(map[string]bool, len(configuration.Packages))
+	for _, pkg := range configuration.Packages {
+		packaged[pkg.Version] = true
+	}
+	for _, definition := range definitions.Results {
+		if packaged[definition.Version] {
+			result.TargetVersion = definition.Version
+			break
+		}
+	}
+	if result.TargetVersion == "" {
+		result.Actions = append(result.Actions, PatchPromotionAction{
+			Action: PatchPromotionActionWait,
+			Detail: "no package has been uploaded for any patch definition",
+		})
+		return result, nil
+	}
+
+	return result, c.promotePatchRings(result.TargetVersion, opts, result)
+}
+
+// mapPatchPackages adds the uploaded packages of definitions without a package to the software title
+// configuration. configuration.Packages is updated to the mapping in effect, also in a dry run.
+func (c *Client) mapPatchPackages(configuration *ResourcePatchSoftwareTitleConfiguration, definitions []PatchSoftwareTitleDefinition, opts *PatchPromotionOptions, result *PatchPromotionResult) error {
+	matches := opts.PackageMatcher
+	if matches == nil {
+		prefix := strings.ToLower(opts.PackageNamePrefix)
+		matches = func(packageName, version string) bool {
+			return strings.HasPrefix(strings.ToLower(packageName), prefix) && containsVersion(packageName, version)
+		}
+	}
+
+	mapped := make(map[string]bool, len(configuration.Packages))
+	for _, pkg := range configuration.Packages {
+		mapped[pkg.Version] = true
+	}
+
+	packages, err := c.GetPackages()
+	if err != nil {
+		return err
+	}
+
+	var additions []PatchSoftwareTitleConfigurationSubsetPackage
+	for _, definition := range definitions {
+		if mapped[definition.Version] {
+			continue
+		}
+		for _, pkg := range packages.Package {
+			if !matches(pkg.Name, definition.Version) {
+				continue
+			}
+			additions = append(additions, PatchSoftwareTitleConfigurationSubsetPackage{
+				PackageId:   strconv.Itoa(pkg.ID),
+				Version:     definition.Version,
+				DisplayName: pkg.Name,
+			})
+			result.Actions = append(result.Actions, PatchPromotionAction{
+				Action:    PatchPromotionActionMapPackage,
+				PackageID: pkg.ID,
+				Version:   definition.Version,
+				Detail:    fmt.Sprintf("package %s", pkg.Name),
+			})
+			break
+		}
+	}
+
+	if len(additions) == 0 {
+		return nil
+	}
+
+	configuration.Packages = append(configuration.Packages, additions...)
+	if opts.DryRun {
+		return nil
+	}
+
+	// The configuration fields are not omitted when empty, so the configuration is sent back as read.
+	_, err = c.UpdatePatchSoftwareTitleConfigurationById(configuration.ID, *configuration)
+
+	return err
+}
+
+// promotePatchRings promotes the first ring not on the target version, provided the rings before it are
+// ready.
+func (c *Client) promotePatchRings(targetVersion string, opts *PatchPromotionOptions, result *PatchPromotionResult) error {
+	summaries, err := c.GetPatchPolicies("")
+	if err != nil {
+		return err
+	}
+	summaryByID := make(map[int]ResourcePatchPolicy, len(summaries.Results))
+	for _, summary := range summaries.Results {
+		if policyID, err := strconv.Atoi(summary.ID); err == nil {
+			summaryByID[policyID] = summary
+		}
+	}
+
+	actions, err := planPatchRingPromotion(targetVersion, opts.Rings, summaryByID)
+	if err != nil {
+		return err
+	}
+	result.Actions = append(result.Actions, actions...)
+
+	if opts.DryRun {
+		return nil
+	}
+	for _, action := range actions {
+		if action.Action != PatchPromotionActionPromoteRing {
+			continue
+		}
+		if err := c.UpdatePatchPolicyTargetVersionByID(action.PatchPolicyID, targetVersion); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// planPatchRingPromotion returns the actions for this run: promoting the patch policies of the first ring
+// not on the target version, waiting for an earlier ring to complete, or nothing left to do.
+func planPatchRingPromotion(targetVersion string, rings []PatchPromotionRing, summaryByID map[int]ResourcePatchPolicy) ([]PatchPromotionAction, error) {
+	for i, ring := range rings {
+		var behind []PatchPromotionAction
+		var completed, total int
+		for _, policyID := range ring.PatchPolicyIDs {
+			summary, ok := summaryByID[policyID]
+			if !ok {
+				return nil, fmt.Errorf("patch policy %d of ring %s not found", policyID, ring.Name)
+			}
+			if summary.PolicyTargetVersion != targetVersion {
+				behind = append(behind, PatchPromotionAction{
+					Action:        PatchPromotionActionPromoteRing,
+					Ring:          ring.Name,
+					PatchPolicyID: policyID,
+					Version:       targetVersion,
+					Detail:        fmt.Sprintf("from %s", summary.PolicyTargetVersion),
+				})
+				continue
+			}
+			completed += summary.Completed
+			total += summary.Completed + summary.Pending + summary.Failed + summary.Deferred
+		}
+
+		if len(behind) > 0 {
+			return behind, nil
+		}
+
+		if i == len(rings)-1 {
+			break
+		}
+
+		completion := 1.0
+		if total > 0 {
+			completion = float64(completed) / float64(total)
+		}
+		if completion < ring.MinimumCompletion {
+			return []PatchPromotionAction{{
+				Action:  PatchPromotionActionWait,
+				Ring:    ring.Name,
+				Version: targetVersion,
+				Detail:  fmt.Sprintf("%d of %d devices completed (%.0f%%), %.0f%% required before ring %s", completed, total, completion*100, ring.MinimumCompletion*100, rings[i+1].Name),
+			}}, nil
+		}
+	}
+
+	return []PatchPromotionAction{{
+		Action:  PatchPromotionActionUpToDate,
+		Version: targetVersion,
+		Detail:  "every ring targets the version",
+	}}, nil
+}
+
+// sortPatchDefinitionsLatestFirst orders definitions by their absolute order id, 0 being the latest.
+func sortPatchDefinitionsLatestFirst(definitions []PatchSoftwareTitleDefinition) {
+	sort.SliceStable(definitions, func(i, j int) bool {
+		a, errA := strconv.Atoi(definitions[i].AbsoluteOrderId)
+		b, errB := strconv.Atoi(definitions[j].AbsoluteOrderId)
+		if errA != nil || errB != nil {
+			return false
+		}
+		return a < b
+	})
+}
+
+// containsVersion reports whether name contains version, not directly preceded or followed by a digit or a
+// dot followed by a digit.
+func containsVersion(name, version string) bool {
+	if version == "" {
+		return false
+	}
+
+	for offset := 0; ; {
+		index := strings.Index(name[offset:], version)
+		if index < 0 {
+			return false
+		}
+		start := offset + index
+		end := start + len(version)
+
+		before := start == 0 || !isVersionChar(name[start-1])
+		after := end == len(name) || !isDigit(name[end]) && !(name[end] == '.' && end+1 < len(name) && isDigit(name[end+1]))
+		if before && after {
+			return true
+		}
+		offset = start + 1
+	}
+}
+
+func isVersionChar(b byte) bool {
+	return isDigit(b) || b == '.'
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
@@ -0,0 +1,177 @@
+package jamfpro
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestContainsVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		want    bool
+	}{
+		{name: "Chrome-121.0.pkg", version: "121.0", want: true},
+		{name: "Chrome-121.0.1.pkg", version: "121.0", want: false},
+		{name: "Chrome-121.0.1.pkg", version: "121.0.1", want: true},
+		{name: "Chrome-1121.0.pkg", version: "121.0", want: false},
+		{name: "Chrome-0.121.0.pkg", version: "121.0", want: false},
+		{name: "Chrome-121.01.pkg", version: "121.0", want: false},
+		{name: "Chrome 121.0", version: "121.0", want: true},
+		{name: "121.0-Chrome.pkg", version: "121.0", want: true},
+		{name: "Chrome-121.0.1-121.0.pkg", version: "121.0", want: true},
+		{name: "Chrome-121.0.pkg", version: "", want: false},
+		{name: "Firefox.pkg", version: "121.0", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := containsVersion(tt.name, tt.version); got != tt.want {
+			t.Errorf("containsVersion(%q, %q) = %v, want %v", tt.name, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestPlanPatchRingPromotion(t *testing.T) {
+	rings := []PatchPromotionRing{
+		{Name: "pilot", PatchPolicyIDs: []int{1}, MinimumCompletion: 0.8},
+		{Name: "broad", PatchPolicyIDs: []int{2, 3}},
+	}
+	policy := func(id, target string, completed, pending int) ResourcePatchPolicy {
+		return ResourcePatchPolicy{ID: id, PolicyTargetVersion: target, Completed: completed, Pending: pending}
+	}
+
+	tests := []struct {
+		name     string
+		policies []ResourcePatchPolicy
+		want     []PatchPromotionAction
+		wantErr  bool
+	}{
+		{
+			name:     "first ring behind",
+			policies: []ResourcePatchPolicy{policy("1", "120.0", 10, 0), policy("2", "120.0", 0, 0), policy("3", "120.0", 0, 0)},
+			want: []PatchPromotionAction{
+				{Action: PatchPromotionActionPromoteRing, Ring: "pilot", PatchPolicyID: 1, Version: "121.0", Detail: "from 120.0"},
+			},
+		},
+		{
+			name:     "earlier ring below the completion gate",
+			policies: []ResourcePatchPolicy{policy("1", "121.0", 7, 3), policy("2", "120.0", 0, 0), policy("3", "120.0", 0, 0)},
+			want: []PatchPromotionAction{
+				{Action: PatchPromotionActionWait, Ring: "pilot", Version: "121.0", Detail: "7 of 10 devices completed (70%), 80% required before ring broad"},
+			},
+		},
+		{
+			name:     "completion gate met promotes every behind policy of the next ring",
+			policies: []ResourcePatchPolicy{policy("1", "121.0", 8, 2), policy("2", "120.0", 0, 0), policy("3", "119.0", 0, 0)},
+			want: []PatchPromotionAction{
+				{Action: PatchPromotionActionPromoteRing, Ring: "broad", PatchPolicyID: 2, Version: "121.0", Detail: "from 120.0"},
+				{Action: PatchPromotionActionPromoteRing, Ring: "broad", PatchPolicyID: 3, Version: "121.0", Detail: "from 119.0"},
+			},
+		},
+		{
+			name:     "ring without devices counts as complete",
+			policies: []ResourcePatchPolicy{policy("1", "121.0", 0, 0), policy("2", "120.0", 0, 0), policy("3", "121.0", 0, 0)},
+			want: []PatchPromotionAction{
+				{Action: PatchPromotionActionPromoteRing, Ring: "broad", PatchPolicyID: 2, Version: "121.0", Detail: "from 120.0"},
+			},
+		},
+		{
+			name:     "last ring is not gated",
+			policies: []ResourcePatchPolicy{policy("1", "121.0", 9, 1), policy("2", "121.0", 0, 50), policy("3", "121.0", 0, 50)},
+			want: []PatchPromotionAction{
+				{Action: PatchPromotionActionUpToDate, Version: "121.0", Detail: "every ring targets the version"},
+			},
+		},
+		{
+			name:     "missing patch policy",
+			policies: []ResourcePatchPolicy{policy("1", "121.0", 10, 0), policy("2", "121.0", 0, 0)},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summaryByID := make(map[int]ResourcePatchPolicy, len(tt.policies))
+			for i, p := range tt.policies {
+				summaryByID[i+1] = p
+			}
+
+			got, err := planPatchRingPromotion("121.0", rings, summaryByID)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("planPatchRingPromotion succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("planPatchRingPromotion: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("actions =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPromotePatchRingsDryRun(t *testing.T) {
+	for _, dryRun := range []bool{true, false} {
+		var updated []string
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("/api/v2/patch-policies/policy-details", func(w http.ResponseWriter, r *http.Request) {
+			writeTestJSON(w, http.StatusOK, map[string]interface{}{
+				"totalCount": 2,
+				"results": []ResourcePatchPolicy{
+					{ID: "1", PolicyTargetVersion: "121.0", Completed: 10},
+					{ID: "2", PolicyTargetVersion: "120.0"},
+				},
+			})
+		})
+		mux.HandleFunc("/JSSResource/patchpolicies/id/2", func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			var request struct {
+				TargetVersion string `xml:"general>target_version"`
+			}
+			xml.Unmarshal(body, &request)
+			updated = append(updated, request.TargetVersion)
+			w.Header().Set("Content-Type", "application/xml")
+			w.Write([]byte(`<patch_policy><id>2</id></patch_policy>`))
+		})
+
+		opts := &PatchPromotionOptions{
+			Rings:  []PatchPromotionRing{{Name: "pilot", PatchPolicyIDs: []int{1}}, {Name: "broad", PatchPolicyIDs: []int{2}}},
+			DryRun: dryRun,
+		}
+		result := &PatchPromotionResult{DryRun: dryRun}
+		if err := newTestClient(t, mux).promotePatchRings("121.0", opts, result); err != nil {
+			t.Fatalf("dry run %v: promotePatchRings: %v", dryRun, err)
+		}
+
+		if len(result.Actions) != 1 || result.Actions[0].Action != PatchPromotionActionPromoteRing || result.Actions[0].PatchPolicyID != 2 {
+			t.Errorf("dry run %v: actions = %+v", dryRun, result.Actions)
+		}
+		want := "121.0"
+		if dryRun {
+			want = ""
+		}
+		if strings.Join(updated, ",") != want {
+			t.Errorf("dry run %v: target versions sent = %v", dryRun, updated)
+		}
+	}
+}
+
+func TestPromotePatchTitleRequiresPackageFilter(t *testing.T) {
+	opts := &PatchPromotionOptions{
+		SoftwareTitleConfigurationID: "5",
+		Rings:                        []PatchPromotionRing{{Name: "all", PatchPolicyIDs: []int{1}}},
+	}
+
+	// No server is needed: the options are rejected before any request.
+	if _, err := (&Client{}).PromotePatchTitle(opts); err == nil || !strings.Contains(err.Error(), "package") {
+		t.Errorf("PromotePatchTitle without a package filter = %v, want an error", err)
+	}
+}
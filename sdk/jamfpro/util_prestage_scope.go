@@ -0,0 +1,117 @@
+// util_prestage_scope.go
+// Shared scope handling for computer and mobile device prestages.
+// Prestage scopes are guarded by an optimistic lock: every change must carry the versionLock of the scope it
+// was based on, and Jamf Pro answers 409 Conflict when the scope changed in between. updatePrestageScope
+// reads the scope, applies the change with its versionLock and, on a conflict, starts again from a fresh read.
+
+package jamfpro
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/deploymenttheory/go-api-http-client/response"
+)
+
+// Prestage scope operations
+const (
+	prestageScopeAdd     = "add"
+	prestageScopeRemove  = "remove"
+	prestageScopeReplace = "replace"
+)
+
+// maxPrestageScopeAttempts is how often a scope change is attempted before a conflict is returned.
+const maxPrestageScopeAttempts = 5
+
+// RequestPrestageScopeUpdate is the body of a prestage scope change.
+type RequestPrestageScopeUpdate struct {
+	SerialNumbers []string `json:"serialNumbers"`
+	VersionLock   int      `json:"versionLock"`
+}
+
+// updatePrestageScope applies a scope operation to the prestage scope at scopeEndpoint. Adding serials which
+// are already in scope, or removing ones which are not, is skipped, so retries and repeated runs are no-ops.
+func (c *Client) updatePrestageScope(scopeEndpoint, operation string, serials []string) (*ResponseDeviceScope, error) {
+	var lastErr error
+	for attempt := 0; attempt < maxPrestageScopeAttempts; attempt++ {
+		var scope ResponseDeviceScope
+		resp, err := c.HTTP.DoRequest("GET", scopeEndpoint, nil, &scope)
+		if err != nil {
+			return nil, err
+		}
+		if resp != nil && resp.Body != nil {
+			resp.Body.Close()
+		}
+
+		method, endpoint := "POST", scopeEndpoint
+		pending := serials
+		switch operation {
+		case prestageScopeAdd:
+			pending = filterPrestageScopeSerials(scope.Assignments, serials, false)
+		case prestageScopeRemove:
+			endpoint += "/delete-multiple"
+			pending = filterPrestageScopeSerials(scope.Assignments, serials, true)
+		case prestageScopeReplace:
+			method = "PUT"
+		default:
+			return nil, fmt.Errorf("unsupported prestage scope operation: %s", operation)
+		}
+
+		if len(pending) == 0 && operation != prestageScopeReplace {
+			return &scope, nil
+		}
+
+		request := RequestPrestageScopeUpdate{
+			SerialNumbers: pending,
+			VersionLock:   scope.VersionLock,
+		}
+		if request.SerialNumbers == nil {
+			request.SerialNumbers = []string{}
+		}
+
+		var out ResponseDeviceScope
+		resp, err = c.HTTP.DoRequest(method, endpoint, &request, &out)
+		if resp != nil && resp.Body != nil {
+			resp.Body.Close()
+		}
+		if err == nil {
+			return &out, nil
+		}
+		if !isConflictError(err) {
+			return nil, err
+		}
+
+		lastErr = err
+	}
+
+	return nil, fmt.Errorf("scope changed concurrently %d times in a row: %v", maxPrestageScopeAttempts, lastErr)
+}
+
+// filterPrestageScopeSerials returns the serials which are (assigned true) or are not (assigned false) in the
+// given assignments, without duplicates.
+func filterPrestageScopeSerials(assignments []DeviceScopeSubsetAssignmentItem, serials []string, assigned bool) []string {
+	inScope := make(map[string]bool, len(assignments))
+	for _, assignment := range assignments {
+		inScope[assignment.SerialNumber] = true
+	}
+
+	seen := make(map[string]bool, len(serials))
+	var out []string
+	for _, serial := range serials {
+		if seen[serial] || inScope[serial] != assigned {
+			continue
+		}
+		seen[serial] = true
+		out = append(out, serial)
+	}
+
+	return out
+}
+
+// isConflictError reports whether a request failed with 409 Conflict. The HTTP client does not return the
+// response of a failed POST, so the status is taken from the API error.
+func isConflictError(err error) bool {
+	var apiErr *response.APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict
+}
@@ -0,0 +1,182 @@
+package jamfpro
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testPrestageScopeEndpoint = "/api/v2/computer-prestages/1/scope"
+
+// testPrestageScopeServer serves a prestage scope guarded by its versionLock, the way Jamf Pro does. conflicts
+// is the number of changes answered with 409 Conflict, as if the scope had been changed by someone else in
+// between.
+type testPrestageScopeServer struct {
+	serials     []string
+	versionLock int
+	conflicts   int
+	changes     []RequestPrestageScopeUpdate
+}
+
+func (s *testPrestageScopeServer) scope() ResponseDeviceScope {
+	scope := ResponseDeviceScope{PrestageId: "1", VersionLock: s.versionLock}
+	for _, serial := range s.serials {
+		scope.Assignments = append(scope.Assignments, DeviceScopeSubsetAssignmentItem{SerialNumber: serial})
+	}
+	return scope
+}
+
+func (s *testPrestageScopeServer) client(t *testing.T) *Client {
+	change := func(w http.ResponseWriter, r *http.Request, apply func(serials []string)) {
+		var request RequestPrestageScopeUpdate
+		json.NewDecoder(r.Body).Decode(&request)
+		s.changes = append(s.changes, request)
+
+		if s.conflicts > 0 {
+			s.conflicts--
+			s.versionLock++
+		}
+		if request.VersionLock != s.versionLock {
+			writeTestJSON(w, http.StatusConflict, map[string]interface{}{
+				"httpStatus": http.StatusConflict,
+				"errors":     []map[string]string{{"code": "OPTIMISTIC_LOCK_FAILED"}},
+			})
+			return
+		}
+
+		apply(request.SerialNumbers)
+		s.versionLock++
+		writeTestJSON(w, http.StatusOK, s.scope())
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(testPrestageScopeEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			writeTestJSON(w, http.StatusOK, s.scope())
+		case http.MethodPost:
+			change(w, r, func(serials []string) { s.serials = append(s.serials, serials...) })
+		case http.MethodPut:
+			change(w, r, func(serials []string) { s.serials = serials })
+		}
+	})
+	mux.HandleFunc(testPrestageScopeEndpoint+"/delete-multiple", func(w http.ResponseWriter, r *http.Request) {
+		change(w, r, func(serials []string) {
+			removed := make(map[string]bool, len(serials))
+			for _, serial := range serials {
+				removed[serial] = true
+			}
+			var kept []string
+			for _, serial := range s.serials {
+				if !removed[serial] {
+					kept = append(kept, serial)
+				}
+			}
+			s.serials = kept
+		})
+	})
+	return newTestClient(t, mux)
+}
+
+func TestUpdatePrestageScopeRetriesConflicts(t *testing.T) {
+	server := &testPrestageScopeServer{serials: []string{"A"}, versionLock: 2, conflicts: 2}
+
+	scope, err := server.client(t).updatePrestageScope(testPrestageScopeEndpoint, prestageScopeAdd, []string{"B"})
+	if err != nil {
+		t.Fatalf("updatePrestageScope: %v", err)
+	}
+
+	if len(server.changes) != 3 {
+		t.Fatalf("sent %d changes, want two conflicts and a success", len(server.changes))
+	}
+	for i, wantLock := range []int{2, 3, 4} {
+		if server.changes[i].VersionLock != wantLock {
+			t.Errorf("change %d sent versionLock %d, want %d from a fresh read", i, server.changes[i].VersionLock, wantLock)
+		}
+	}
+	if !reflect.DeepEqual(server.serials, []string{"A", "B"}) || scope.VersionLock != 5 {
+		t.Errorf("scope = %+v, serials = %v, want A and B at versionLock 5", scope, server.serials)
+	}
+}
+
+func TestUpdatePrestageScopeGivesUp(t *testing.T) {
+	server := &testPrestageScopeServer{serials: []string{"A"}, conflicts: maxPrestageScopeAttempts}
+
+	_, err := server.client(t).updatePrestageScope(testPrestageScopeEndpoint, prestageScopeRemove, []string{"A"})
+	if err == nil || !strings.Contains(err.Error(), "concurrently") {
+		t.Fatalf("error = %v, want the conflict returned", err)
+	}
+	if len(server.changes) != maxPrestageScopeAttempts {
+		t.Errorf("sent %d changes, want %d", len(server.changes), maxPrestageScopeAttempts)
+	}
+	if !reflect.DeepEqual(server.serials, []string{"A"}) {
+		t.Errorf("serials = %v, want the scope unchanged", server.serials)
+	}
+}
+
+func TestUpdatePrestageScopeSkipsSerials(t *testing.T) {
+	tests := []struct {
+		name        string
+		operation   string
+		serials     []string
+		wantChanges [][]string
+		wantScope   []string
+	}{
+		{
+			name:        "add skips serials already in scope",
+			operation:   prestageScopeAdd,
+			serials:     []string{"A", "C", "C"},
+			wantChanges: [][]string{{"C"}},
+			wantScope:   []string{"A", "B", "C"},
+		},
+		{
+			name:      "add of serials all in scope sends nothing",
+			operation: prestageScopeAdd,
+			serials:   []string{"A", "B"},
+			wantScope: []string{"A", "B"},
+		},
+		{
+			name:        "remove skips serials not in scope",
+			operation:   prestageScopeRemove,
+			serials:     []string{"B", "Z"},
+			wantChanges: [][]string{{"B"}},
+			wantScope:   []string{"A"},
+		},
+		{
+			name:      "remove of serials all out of scope sends nothing",
+			operation: prestageScopeRemove,
+			serials:   []string{"Y", "Z"},
+			wantScope: []string{"A", "B"},
+		},
+		{
+			name:        "replace sends every serial",
+			operation:   prestageScopeReplace,
+			serials:     []string{"A", "Z"},
+			wantChanges: [][]string{{"A", "Z"}},
+			wantScope:   []string{"A", "Z"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &testPrestageScopeServer{serials: []string{"A", "B"}, versionLock: 1}
+
+			if _, err := server.client(t).updatePrestageScope(testPrestageScopeEndpoint, tt.operation, tt.serials); err != nil {
+				t.Fatalf("updatePrestageScope: %v", err)
+			}
+
+			var changes [][]string
+			for _, change := range server.changes {
+				changes = append(changes, change.SerialNumbers)
+			}
+			if !reflect.DeepEqual(changes, tt.wantChanges) {
+				t.Errorf("sent %v, want %v", changes, tt.wantChanges)
+			}
+			if !reflect.DeepEqual(server.serials, tt.wantScope) {
+				t.Errorf("scope = %v, want %v", server.serials, tt.wantScope)
+			}
+		})
+	}
+}
@@ -0,0 +1,353 @@
+// util_webhook_reconcile.go
+// Declarative management of Jamf Pro webhooks.
+// ReconcileWebhooks takes the desired set of webhooks and creates, updates or disables webhooks so
+// that the tenant matches it. Webhooks are matched by name. Webhooks which are not in the desired set
+// are only touched when WebhookReconcileOptions.DisableUnlisted is set, and can be limited to those
+// whose name starts with NamePrefix so that webhooks managed by hand are left alone.
+package jamfpro
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Webhook content types and authentication types accepted by the Classic API
+const (
+	WebhookContentTypeJSON      = "application/json"
+	WebhookContentTypeXML       = "text/xml"
+	WebhookAuthenticationNone   = "NONE"
+	WebhookAuthenticationBasic  = "BASIC"
+	WebhookAuthenticationHeader = "HEADER"
+)
+
+// Jamf Pro defaults, in seconds, used when a spec does not set the timeouts
+const (
+	webhookDefaultConnectionTimeout = 5
+	webhookDefaultReadTimeout       = 2
+)
+
+// Webhook reconcile actions
+const (
+	WebhookActionCreated   = "created"
+	WebhookActionUpdated   = "updated"
+	WebhookActionDisabled  = "disabled"
+	WebhookActionUnchanged = "unchanged"
+	WebhookActionFailed    = "failed"
+)
+
+// WebhookSpec is the desired state of a single webhook.
+type WebhookSpec struct {
+	Name  string `json:"name"`
+	Event string `json:"event"`
+	URL   string `json:"url"`
+	// ContentType is WebhookContentTypeJSON or WebhookContentTypeXML; "json" and "xml" are accepted too.
+	// Defaults to JSON.
+	ContentType string `json:"contentType,omitempty"`
+	// AuthenticationType is WebhookAuthenticationNone, WebhookAuthenticationBasic or
+	// WebhookAuthenticationHeader. Defaults to basic authentication when Username is set and to header
+	// authentication when HeaderName is set.
+	AuthenticationType string `json:"authenticationType,omitempty"`
+	Username           string `json:"username,omitempty"`
+	// Password is write only: Jamf Pro does not return it, so a changed password alone does not trigger
+	// an update. It is sent whenever the webhook is created or updated.
+	Password string `json:"password,omitempty"`
+	// HeaderName and HeaderValue are the header Jamf Pro sends with header authentication, matching
+	// webhooks.ReceiverOptions. Like Password, HeaderValue is write only and sent on every create or update.
+	HeaderName  string `json:"headerName,omitempty"`
+	HeaderValue string `json:"headerValue,omitempty"`
+	// SmartGroupID is required by the smart group membership change events.
+	SmartGroupID      int `json:"smartGroupId,omitempty"`
+	ConnectionTimeout int `json:"connectionTimeout,omitempty"`
+	ReadTimeout       int `json:"readTimeout,omitempty"`
+	// Enabled defaults to true.
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// WebhookReconcileOptions controls ReconcileWebhooks.
+type WebhookReconcileOptions struct {
+	// DryRun reports the actions that would be taken without changing anything.
+	DryRun bool
+	// DisableUnlisted disables enabled webhooks which are not in the desired set.
+	DisableUnlisted bool
+	// NamePrefix limits DisableUnlisted to webhooks whose name starts with the prefix.
+	NamePrefix string
+}
+
+// WebhookReconcileResult reports the outcome for a single webhook.
+type WebhookReconcileResult struct {
+	Name    string
+	ID      int
+	Action  string
+	Changes []string
+	Error   error
+}
+
+// ReconcileWebhooks makes the tenant's webhooks match specs. Every spec is processed even when an
+// earlier one fails; the returned error summarises the failures.
+func (c *Client) ReconcileWebhooks(specs []WebhookSpec, opts *WebhookReconcileOptions) ([]WebhookReconcileResult, error) {
+	if opts == nil {
+		opts = &WebhookReconcileOptions{}
+	}
+
+	desired := make(map[string]*ResourceWebhook, len(specs))
+	names := make([]string, 0, len(specs))
+	for _, spec := range specs {
+		webhook, err := spec.toResource()
+		if err != nil {
+			return nil, fmt.Errorf("invalid webhook spec %q: %v", spec.Name, err)
+		}
+		if _, duplicate := desired[spec.Name]; duplicate {
+			return nil, fmt.Errorf("invalid webhook spec %q: duplicate name", spec.Name)
+		}
+		desired[spec.Name] = webhook
+		names = append(names, spec.Name)
+	}
+
+	existingList, err := c.GetWebhooks()
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[string]int, len(existingList.Webhooks))
+	for _, item := range existingList.Webhooks {
+		existing[item.Name] = item.ID
+	}
+
+	var results []WebhookReconcileResult
+	for _, name := range names {
+		results = append(results, c.reconcileWebhook(desired[name], existing, opts))
+	}
+
+	if opts.DisableUnlisted {
+		var unlisted []WebhooksListItem
+		for _, item := range existingList.Webhooks {
+			if _, ok := desired[item.Name]; !ok && strings.HasPrefix(item.Name, opts.NamePrefix) {
+				unlisted = append(unlisted, item)
+			}
+		}
+		sort.Slice(unlisted, func(i, j int) bool { return unlisted[i].Name < unlisted[j].Name })
+
+		for _, item := range unlisted {
+			results = append(results, c.disableWebhook(item, opts))
+		}
+	}
+
+	var failed []string
+	for _, result := range results {
+		if result.Error != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", result.Name, result.Error))
+		}
+	}
+	if len(failed) > 0 {
+		return results, fmt.Errorf("%d webhook(s) failed: %s", len(failed), strings.Join(failed, "; "))
+	}
+
+	return results, nil
+}
+
+// reconcileWebhook creates or updates a single desired webhook.
+func (c *Client) reconcileWebhook(desired *ResourceWebhook, existing map[string]int, opts *WebhookReconcileOptions) WebhookReconcileResult {
+	result := WebhookReconcileResult{Name: desired.Name}
+
+	id, found := existing[desired.Name]
+	if !found {
+		result.Action = WebhookActionCreated
+		if opts.DryRun {
+			return result
+		}
+
+		created, err := c.CreateWebhook(desired)
+		if err != nil {
+			result.Action, result.Error = WebhookActionFailed, err
+			return result
+		}
+		result.ID = created.ID
+		return result
+	}
+
+	result.ID = id
+	current, err := c.GetWebhookByID(id)
+	if err != nil {
+		result.Action, result.Error = WebhookActionFailed, err
+		return result
+	}
+
+	result.Changes = diffWebhooks(current, desired)
+	if len(result.Changes) == 0 {
+		result.Action = WebhookActionUnchanged
+		return result
+	}
+
+	result.Action = WebhookActionUpdated
+	if opts.DryRun {
+		return result
+	}
+
+	update := *desired
+	update.ID = id
+	if _, err := c.UpdateWebhookByID(id, &update); err != nil {
+		result.Action, result.Error = WebhookActionFailed, err
+	}
+
+	return result
+}
+
+// disableWebhook disables a webhook which is not in the desired set.
+func (c *Client) disableWebhook(item WebhooksListItem, opts *WebhookReconcileOptions) WebhookReconcileResult {
+	result := WebhookReconcileResult{Name: item.Name, ID: item.ID}
+
+	current, err := c.GetWebhookByID(item.ID)
+	if err != nil {
+		result.Action, result.Error = WebhookActionFailed, err
+		return result
+	}
+
+	if !current.Enabled {
+		result.Action = WebhookActionUnchanged
+		return result
+	}
+
+	result.Action = WebhookActionDisabled
+	result.Changes = []string{"enabled: true => false"}
+	if opts.DryRun {
+		return result
+	}
+
+	current.Enabled = false
+	if _, err := c.UpdateWebhookByID(item.ID, current); err != nil {
+		result.Action, result.Error = WebhookActionFailed, err
+	}
+
+	return result
+}
+
+// toResource validates the spec and converts it into the webhook sent to Jamf Pro.
+func (s WebhookSpec) toResource() (*ResourceWebhook, error) {
+	if s.Name == "" || s.Event == "" || s.URL == "" {
+		return nil, fmt.Errorf("name, event and url are required")
+	}
+
+	contentType := WebhookContentTypeJSON
+	switch strings.ToLower(s.ContentType) {
+	case "", "json", WebhookContentTypeJSON:
+	case "xml", WebhookContentTypeXML:
+		contentType = WebhookContentTypeXML
+	default:
+		return nil, fmt.Errorf("unsupported content type %q", s.ContentType)
+	}
+
+	authenticationType := strings.ToUpper(s.AuthenticationType)
+	if authenticationType == "" {
+		authenticationType = WebhookAuthenticationNone
+		if s.Username != "" {
+			authenticationType = WebhookAuthenticationBasic
+		} else if s.HeaderName != "" {
+			authenticationType = WebhookAuthenticationHeader
+		}
+	}
+	basicSet := s.Username != "" || s.Password != ""
+	headerSet := s.HeaderName != "" || s.HeaderValue != ""
+	var authorizationHeaders string
+	switch authenticationType {
+	case WebhookAuthenticationNone:
+		if basicSet || headerSet {
+			return nil, fmt.Errorf("username, password and header require an authentication type")
+		}
+	case WebhookAuthenticationBasic:
+		if s.Username == "" {
+			return nil, fmt.Errorf("basic authentication requires a username")
+		}
+		if headerSet {
+			return nil, fmt.Errorf("header name and value require header authentication")
+		}
+	case WebhookAuthenticationHeader:
+		if s.HeaderName == "" || s.HeaderValue == "" {
+			return nil, fmt.Errorf("header authentication requires a header name and value")
+		}
+		if basicSet {
+			return nil, fmt.Errorf("username and password require basic authentication")
+		}
+		// Jamf Pro takes the headers as a JSON object
+		headers, err := json.Marshal(map[string]string{s.HeaderName: s.HeaderValue})
+		if err != nil {
+			return nil, err
+		}
+		authorizationHeaders = string(headers)
+	default:
+		return nil, fmt.Errorf("unsupported authentication type %q", s.AuthenticationType)
+	}
+
+	if strings.HasPrefix(s.Event, "SmartGroup") && s.SmartGroupID == 0 {
+		return nil, fmt.Errorf("%s requires a smart group id", s.Event)
+	}
+
+	enabled := true
+	if s.Enabled != nil {
+		enabled = *s.Enabled
+	}
+
+	connectionTimeout, readTimeout := s.ConnectionTimeout, s.ReadTimeout
+	if connectionTimeout == 0 {
+		connectionTimeout = webhookDefaultConnectionTimeout
+	}
+	if readTimeout == 0 {
+		readTimeout = webhookDefaultReadTimeout
+	}
+
+	return &ResourceWebhook{
+		Name:                 s.Name,
+		Enabled:              enabled,
+		URL:                  s.URL,
+		ContentType:          contentType,
+		Event:                s.Event,
+		ConnectionTimeout:    connectionTimeout,
+		ReadTimeout:          readTimeout,
+		AuthenticationType:   authenticationType,
+		Username:             s.Username,
+		Password:             s.Password,
+		AuthorizationHeaders: authorizationHeaders,
+		SmartGroupID:         s.SmartGroupID,
+	}, nil
+}
+
+// diffWebhooks lists the readable fields which differ between the current and desired webhook.
+func diffWebhooks(current, desired *ResourceWebhook) []string {
+	var changes []string
+	add := func(field string, old, new interface{}) {
+		if old != new {
+			changes = append(changes, fmt.Sprintf("%s: %v => %v", field, old, new))
+		}
+	}
+
+	add("enabled", current.Enabled, desired.Enabled)
+	add("url", current.URL, desired.URL)
+	add("content_type", current.ContentType, desired.ContentType)
+	add("event", current.Event, desired.Event)
+	add("connection_timeout", current.ConnectionTimeout, desired.ConnectionTimeout)
+	add("read_timeout", current.ReadTimeout, desired.ReadTimeout)
+	add("authentication_type", strings.ToUpper(current.AuthenticationType), desired.AuthenticationType)
+	add("username", current.Username, desired.Username)
+	add("header_name", webhookHeaderNames(current.AuthorizationHeaders), webhookHeaderNames(desired.AuthorizationHeaders))
+	add("smart_group_id", current.SmartGroupID, desired.SmartGroupID)
+
+	return changes
+}
+
+// webhookHeaderNames returns the sorted names of the authorization headers of a webhook. Like passwords, the
+// header values are write only, so only the names can be compared.
+func webhookHeaderNames(authorizationHeaders string) string {
+	var headers map[string]interface{}
+	if err := json.Unmarshal([]byte(authorizationHeaders), &headers); err != nil {
+		return ""
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return strings.Join(names, ",")
+}
@@ -0,0 +1,148 @@
+package jamfpro
+
+import "testing"
+
+func TestWebhookSpecAuthentication(t *testing.T) {
+	base := WebhookSpec{Name: "Inventory", Event: "ComputerAdded", URL: "https://hooks.example.com/jamf"}
+
+	tests := []struct {
+		name        string
+		spec        func(WebhookSpec) WebhookSpec
+		wantType    string
+		wantHeaders string
+		wantErr     bool
+	}{
+		{
+			name:     "none by default",
+			spec:     func(s WebhookSpec) WebhookSpec { return s },
+			wantType: WebhookAuthenticationNone,
+		},
+		{
+			name: "basic inferred from username",
+			spec: func(s WebhookSpec) WebhookSpec {
+				s.Username, s.Password = "jamf", "secret"
+				return s
+			},
+			wantType: WebhookAuthenticationBasic,
+		},
+		{
+			name: "header inferred from header name",
+			spec: func(s WebhookSpec) WebhookSpec {
+				s.HeaderName, s.HeaderValue = "Authorization", "Bearer token"
+				return s
+			},
+			wantType:    WebhookAuthenticationHeader,
+			wantHeaders: `{"Authorization":"Bearer token"}`,
+		},
+		{
+			name: "header type is case insensitive",
+			spec: func(s WebhookSpec) WebhookSpec {
+				s.AuthenticationType, s.HeaderName, s.HeaderValue = "header", "X-Webhook-Token", "abc"
+				return s
+			},
+			wantType:    WebhookAuthenticationHeader,
+			wantHeaders: `{"X-Webhook-Token":"abc"}`,
+		},
+		{
+			name: "header without value",
+			spec: func(s WebhookSpec) WebhookSpec {
+				s.AuthenticationType, s.HeaderName = WebhookAuthenticationHeader, "Authorization"
+				return s
+			},
+			wantErr: true,
+		},
+		{
+			name: "header with basic credentials",
+			spec: func(s WebhookSpec) WebhookSpec {
+				s.AuthenticationType, s.HeaderName, s.HeaderValue, s.Username = WebhookAuthenticationHeader, "Authorization", "Bearer token", "jamf"
+				return s
+			},
+			wantErr: true,
+		},
+		{
+			name: "basic with header",
+			spec: func(s WebhookSpec) WebhookSpec {
+				s.Username, s.HeaderName, s.HeaderValue = "jamf", "Authorization", "Bearer token"
+				return s
+			},
+			wantErr: true,
+		},
+		{
+			name: "none with header",
+			spec: func(s WebhookSpec) WebhookSpec {
+				s.AuthenticationType, s.HeaderName, s.HeaderValue = WebhookAuthenticationNone, "Authorization", "Bearer token"
+				return s
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown type",
+			spec: func(s WebhookSpec) WebhookSpec {
+				s.AuthenticationType = "OAUTH"
+				return s
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			webhook, err := tt.spec(base).toResource()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("toResource succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("toResource: %v", err)
+			}
+			if webhook.AuthenticationType != tt.wantType {
+				t.Errorf("AuthenticationType = %q, want %q", webhook.AuthenticationType, tt.wantType)
+			}
+			if webhook.AuthorizationHeaders != tt.wantHeaders {
+				t.Errorf("AuthorizationHeaders = %q, want %q", webhook.AuthorizationHeaders, tt.wantHeaders)
+			}
+		})
+	}
+}
+
+func TestDiffWebhooksHeaderName(t *testing.T) {
+	spec := WebhookSpec{Name: "Inventory", Event: "ComputerAdded", URL: "https://hooks.example.com/jamf", HeaderName: "X-Webhook-Token", HeaderValue: "abc"}
+	desired, err := spec.toResource()
+	if err != nil {
+		t.Fatalf("toResource: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		headers     string
+		wantChanges []string
+	}{
+		{
+			name:    "same header with masked value",
+			headers: `{"X-Webhook-Token":"****"}`,
+		},
+		{
+			name:        "renamed header",
+			headers:     `{"Authorization":"****"}`,
+			wantChanges: []string{"header_name: Authorization => X-Webhook-Token"},
+		},
+		{
+			name:        "no header",
+			wantChanges: []string{"header_name:  => X-Webhook-Token"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := *desired
+			current.AuthorizationHeaders = tt.headers
+
+			changes := diffWebhooks(&current, desired)
+			if len(changes) != len(tt.wantChanges) || len(changes) > 0 && changes[0] != tt.wantChanges[0] {
+				t.Errorf("changes = %q, want %q", changes, tt.wantChanges)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+// util_webhook_selftest.go
+// End to end check that Jamf Pro can deliver webhooks to a receiver.
+// SelfTestWebhooks starts a webhooks.Receiver on a local address, points a dedicated test webhook
+// for the RestAPIOperation event at it, triggers the event with an API call and waits for the
+// delivery. The test webhook is deleted afterwards. Run it after Jamf Pro upgrades or network
+// changes to validate the path from Jamf Pro to the event pipeline.
+package jamfpro
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/webhooks"
+)
+
+// Webhook self test defaults
+const (
+	DefaultWebhookSelfTestName    = "go-api-sdk-jamfpro self test"
+	DefaultWebhookSelfTestTimeout = 2 * time.Minute
+)
+
+// WebhookSelfTestOptions configures SelfTestWebhooks.
+type WebhookSelfTestOptions struct {
+	// ListenAddr is the local address the receiver listens on, e.g. ":8443".
+	ListenAddr string
+	// PublicURL is the URL at which Jamf Pro reaches ListenAddr, e.g. https://hooks.example.com/jamf.
+	PublicURL string
+	// WebhookName defaults to DefaultWebhookSelfTestName. A webhook with this name is replaced and deleted.
+	WebhookName string
+	// ContentType is WebhookContentTypeJSON (default) or WebhookContentTypeXML.
+	ContentType string
+	// TLSCertFile and TLSKeyFile, when set, make the receiver serve HTTPS.
+	TLSCertFile string
+	TLSKeyFile  string
+	// Timeout defaults to DefaultWebhookSelfTestTimeout.
+	Timeout time.Duration
+}
+
+// WebhookSelfTestResult reports the outcome of a self test.
+type WebhookSelfTestResult struct {
+	WebhookID int
+	Delivered bool
+	// RoundTrip is the time between triggering the event and receiving the delivery.
+	RoundTrip time.Duration
+	Event     *webhooks.Event
+}
+
+// SelfTestWebhooks verifies that Jamf Pro can deliver a webhook to opts.PublicURL. An error is returned
+// when no delivery arrives before the timeout; the result is returned in either case.
+func (c *Client) SelfTestWebhooks(ctx context.Context, opts WebhookSelfTestOptions) (*WebhookSelfTestResult, error) {
+	if opts.ListenAddr == "" || opts.PublicURL == "" {
+		return nil, fmt.Errorf("webhook self test requires a listen address and a public url")
+	}
+	if opts.WebhookName == "" {
+		opts.WebhookName = DefaultWebhookSelfTestName
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultWebhookSelfTestTimeout
+	}
+
+	username, password, err := randomWebhookCredentials()
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := make(chan *webhooks.Event, 16)
+	receiver := webhooks.NewReceiver(webhooks.ReceiverOptions{Username: username, Password: password})
+	receiver.Handle(webhooks.EventRestAPIOperation, func(_ context.Context, event *webhooks.Event) error {
+		select {
+		case deliveries <- event:
+		default:
+		}
+		return nil
+	})
+
+	listener, err := net.Listen("tcp", opts.ListenAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %v", opts.ListenAddr, err)
+	}
+
+	server := &http.Server{Handler: receiver, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		var serveErr error
+		if opts.TLSCertFile != "" {
+			serveErr = server.ServeTLS(listener, opts.TLSCertFile, opts.TLSKeyFile)
+		} else {
+			serveErr = server.Serve(listener)
+		}
+		if serveErr != nil && !errors.Is(serveErr, http.ErrServerClosed) {
+			c.HTTP.Logger.Error(fmt.Sprintf("webhook self test receiver stopped: %v", serveErr))
+		}
+	}()
+	defer server.Close()
+
+	spec := WebhookSpec{
+		Name:        opts.WebhookName,
+		Event:       webhooks.EventRestAPIOperation,
+		URL:         opts.PublicURL,
+		ContentType: opts.ContentType,
+		Username:    username,
+		Password:    password,
+	}
+	desired, err := spec.toResource()
+	if err != nil {
+		return nil, err
+	}
+
+	webhookID, err := c.upsertWebhookByName(desired)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := c.DeleteWebhookByID(webhookID); err != nil {
+			c.HTTP.Logger.Warn(fmt.Sprintf("failed to delete self test webhook %d: %v", webhookID, err))
+		}
+	}()
+
+	result := &WebhookSelfTestResult{WebhookID: webhookID}
+
+	// Any Classic API write raises RestAPIOperation; re-saving the test webhook is harmless.
+	triggeredAt := time.Now()
+	desired.ID = webhookID
+	if _, err := c.UpdateWebhookByID(webhookID, desired); err != nil {
+		return result, fmt.Errorf("failed to trigger self test event: %v", err)
+	}
+
+	timer := time.NewTimer(opts.Timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case event := <-deliveries:
+			if event.Webhook.ID != webhookID {
+				continue
+			}
+			result.Delivered = true
+			result.RoundTrip = time.Since(triggeredAt)
+			result.Event = event
+			return result, nil
+		case <-timer.C:
+			return result, fmt.Errorf("no webhook delivery received at %s within %s", opts.PublicURL, opts.Timeout)
+		case <-ctx.Done():
+			return result, ctx.Err()
+		}
+	}
+}
+
+// upsertWebhookByName updates the webhook with the same name as webhook, or creates it, and returns its ID.
+func (c *Client) upsertWebhookByName(webhook *ResourceWebhook) (int, error) {
+	existing, err := c.GetWebhooks()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, item := range existing.Webhooks {
+		if item.Name == webhook.Name {
+			update := *webhook
+			update.ID = item.ID
+			if _, err := c.UpdateWebhookByID(item.ID, &update); err != nil {
+				return 0, err
+			}
+			return item.ID, nil
+		}
+	}
+
+	created, err := c.CreateWebhook(webhook)
+	if err != nil {
+		return 0, err
+	}
+
+	return created.ID, nil
+}
+
+// randomWebhookCredentials returns a random basic authentication username and password.
+func randomWebhookCredentials() (string, string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", fmt.Errorf("failed to generate webhook credentials: %v", err)
+	}
+
+	return "selftest-" + hex.EncodeToString(buf[:4]), hex.EncodeToString(buf[4:]), nil
+}
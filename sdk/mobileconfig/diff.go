@@ -0,0 +1,314 @@
+// mobileconfig/diff.go
+// Semantic comparison of configuration profiles.
+// Jamf Pro rewrites PayloadUUID and PayloadIdentifier on upload and may return values with a
+// different plist type or escaping than was sent. A byte or struct comparison therefore reports
+// changes where there are none. The functions here normalise values before comparing them and
+// report differences by path, e.g. PayloadContent[com.apple.wifi.managed#0].SSID_STR.
+
+package mobileconfig
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Difference kinds
+const (
+	DiffAdded   = "added"
+	DiffRemoved = "removed"
+	DiffChanged = "changed"
+)
+
+// DefaultIgnoredKeys are the keys managed by Jamf Pro which are skipped at every level of a profile.
+var DefaultIgnoredKeys = []string{"PayloadUUID", "PayloadIdentifier"}
+
+// Difference describes a single semantic difference between two profiles.
+type Difference struct {
+	Path string
+	Kind string
+	Old  interface{}
+	New  interface{}
+}
+
+// String returns a readable, single line description of the difference.
+func (d Difference) String() string {
+	switch d.Kind {
+	case DiffAdded:
+		return fmt.Sprintf("+ %s: %v", d.Path, d.New)
+	case DiffRemoved:
+		return fmt.Sprintf("- %s: %v", d.Path, d.Old)
+	default:
+		return fmt.Sprintf("~ %s: %v => %v", d.Path, d.Old, d.New)
+	}
+}
+
+// DiffOptions controls how profiles are compared.
+type DiffOptions struct {
+	// IgnoredKeys are skipped wherever they appear. Defaults to DefaultIgnoredKeys when nil.
+	IgnoredKeys []string
+}
+
+// Diff returns the semantic differences between two typed profiles, compared as they would be
+// marshaled. Payloads are matched by PayloadType and their position among payloads of that type
+// rather than by array index. Use DiffBytes to compare profile documents as they are.
+func Diff(old, new *Profile, opts *DiffOptions) ([]Difference, error) {
+	oldMap, err := old.toMap()
+	if err != nil {
+		return nil, err
+	}
+
+	newMap, err := new.toMap()
+	if err != nil {
+		return nil, err
+	}
+
+	return diffMaps(oldMap, newMap, opts), nil
+}
+
+// DiffBytes decodes two property lists and returns the semantic differences between them. The
+// documents are compared as decoded, without going through the typed structs, so every key is
+// taken into account whether or not it is modelled.
+func DiffBytes(old, new []byte, opts *DiffOptions) ([]Difference, error) {
+	oldMap, err := decodeDict(old)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse old profile: %v", err)
+	}
+
+	newMap, err := decodeDict(new)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse new profile: %v", err)
+	}
+
+	return diffMaps(oldMap, newMap, opts), nil
+}
+
+// Equal reports whether two typed profiles are semantically equal using the default options.
+func Equal(old, new *Profile) (bool, error) {
+	diffs, err := Diff(old, new, nil)
+	if err != nil {
+		return false, err
+	}
+	return len(diffs) == 0, nil
+}
+
+// EqualBytes reports whether two property lists are semantically equal using the default options.
+func EqualBytes(old, new []byte) (bool, error) {
+	diffs, err := DiffBytes(old, new, nil)
+	if err != nil {
+		return false, err
+	}
+	return len(diffs) == 0, nil
+}
+
+// EqualValues reports whether two decoded plist values are semantically equal using the
+// default options. It can be used on individual payload dictionaries.
+func EqualValues(old, new interface{}) bool {
+	d := newDiffer(nil)
+	d.compare("", old, new)
+	return len(d.diffs) == 0
+}
+
+// diffMaps compares two top level profile dictionaries.
+func diffMaps(old, new map[string]interface{}, opts *DiffOptions) []Difference {
+	d := newDiffer(opts)
+
+	oldContent, _ := old["PayloadContent"].([]interface{})
+	newContent, _ := new["PayloadContent"].([]interface{})
+
+	d.compareDicts("", withoutKey(old, "PayloadContent"), withoutKey(new, "PayloadContent"))
+	d.compareDicts("PayloadContent", keyPayloads(oldContent), keyPayloads(newContent))
+
+	sort.SliceStable(d.diffs, func(i, j int) bool { return d.diffs[i].Path < d.diffs[j].Path })
+	return d.diffs
+}
+
+// differ accumulates differences while walking two values.
+type differ struct {
+	ignored map[string]bool
+	diffs   []Difference
+}
+
+func newDiffer(opts *DiffOptions) *differ {
+	keys := DefaultIgnoredKeys
+	if opts != nil && opts.IgnoredKeys != nil {
+		keys = opts.IgnoredKeys
+	}
+
+	ignored := map[string]bool{}
+	for _, key := range keys {
+		ignored[key] = true
+	}
+
+	return &differ{ignored: ignored}
+}
+
+func (d *differ) add(path, kind string, old, new interface{}) {
+	d.diffs = append(d.diffs, Difference{Path: path, Kind: kind, Old: old, New: new})
+}
+
+// compare records the differences between two arbitrary plist values at path.
+func (d *differ) compare(path string, old, new interface{}) {
+	old, new = normalizeValue(old), normalizeValue(new)
+
+	switch oldValue := old.(type) {
+	case map[string]interface{}:
+		if newValue, ok := new.(map[string]interface{}); ok {
+			d.compareDicts(path, oldValue, newValue)
+			return
+		}
+	case []interface{}:
+		if newValue, ok := new.([]interface{}); ok {
+			d.compareArrays(path, oldValue, newValue)
+			return
+		}
+	case []byte:
+		if newValue, ok := asBytes(new); ok {
+			if string(oldValue) != string(newValue) {
+				d.add(path, DiffChanged, old, new)
+			}
+			return
+		}
+	case string:
+		if newValue, ok := new.([]byte); ok {
+			if oldBytes, ok := asBytes(oldValue); ok && string(oldBytes) == string(newValue) {
+				return
+			}
+		}
+	case time.Time:
+		if newValue, ok := new.(time.Time); ok && oldValue.Equal(newValue) {
+			return
+		}
+	}
+
+	if old != new {
+		d.add(path, DiffChanged, old, new)
+	}
+}
+
+// compareDicts records the differences between two dictionaries, skipping ignored keys.
+func (d *differ) compareDicts(path string, old, new map[string]interface{}) {
+	keys := map[string]bool{}
+	for key := range old {
+		keys[key] = true
+	}
+	for key := range new {
+		keys[key] = true
+	}
+
+	for key := range keys {
+		if d.ignored[key] {
+			continue
+		}
+
+		keyPath := joinPath(path, key)
+		oldValue, inOld := old[key]
+		newValue, inNew := new[key]
+
+		switch {
+		case !inOld:
+			d.add(keyPath, DiffAdded, nil, normalizeValue(newValue))
+		case !inNew:
+			d.add(keyPath, DiffRemoved, normalizeValue(oldValue), nil)
+		default:
+			d.compare(keyPath, oldValue, newValue)
+		}
+	}
+}
+
+// compareArrays records the differences between two arrays element by element.
+func (d *differ) compareArrays(path string, old, new []interface{}) {
+	for i := 0; i < len(old) || i < len(new); i++ {
+		itemPath := fmt.Sprintf("%s[%d]", path, i)
+		switch {
+		case i >= len(old):
+			d.add(itemPath, DiffAdded, nil, normalizeValue(new[i]))
+		case i >= len(new):
+			d.add(itemPath, DiffRemoved, normalizeValue(old[i]), nil)
+		default:
+			d.compare(itemPath, old[i], new[i])
+		}
+	}
+}
+
+// normalizeValue converts plist values into a canonical form: all numbers become float64 and
+// strings have one extra layer of XML escaping removed, so a value escaped twice equals the original.
+func normalizeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case float32:
+		return float64(v)
+	case string:
+		return unescapeXMLEntities(v)
+	default:
+		return value
+	}
+}
+
+// asBytes returns value as bytes if it is plist data or a base64 encoded string.
+func asBytes(value interface{}) ([]byte, bool) {
+	switch v := value.(type) {
+	case []byte:
+		return v, true
+	case string:
+		cleaned := strings.Join(strings.Fields(v), "")
+		decoded, err := base64.StdEncoding.DecodeString(cleaned)
+		if err != nil {
+			return nil, false
+		}
+		return decoded, true
+	default:
+		return nil, false
+	}
+}
+
+// keyPayloads converts a PayloadContent array into a dictionary keyed by PayloadType and the
+// payload's position among payloads of that type, so that reordering does not produce a diff.
+func keyPayloads(content []interface{}) map[string]interface{} {
+	keyed := map[string]interface{}{}
+	counts := map[string]int{}
+
+	for _, item := range content {
+		payloadType := "unknown"
+		if dict, ok := item.(map[string]interface{}); ok {
+			if t, ok := dict["PayloadType"].(string); ok {
+				payloadType = t
+			}
+		}
+
+		keyed[fmt.Sprintf("%s#%d", payloadType, counts[payloadType])] = item
+		counts[payloadType]++
+	}
+
+	return keyed
+}
+
+// withoutKey returns a shallow copy of m without key.
+func withoutKey(m map[string]interface{}, key string) map[string]interface{} {
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		if k != key {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// joinPath appends a dictionary key to a diff path. Keyed payloads use index notation.
+func joinPath(path, key string) string {
+	switch {
+	case path == "":
+		return key
+	case path == "PayloadContent" && strings.Contains(key, "#"):
+		return fmt.Sprintf("%s[%s]", path, key)
+	default:
+		return path + "." + key
+	}
+}
@@ -0,0 +1,168 @@
+package mobileconfig
+
+import (
+	"strings"
+	"testing"
+)
+
+// diffTestProfile wraps payload dictionaries, given as plist XML, in a configuration profile.
+func diffTestProfile(payloads ...string) []byte {
+	return []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>PayloadIdentifier</key><string>com.example.diff</string>
+	<key>PayloadType</key><string>Configuration</string>
+	<key>PayloadUUID</key><string>11111111-2222-3333-4444-555555555555</string>
+	<key>PayloadVersion</key><integer>1</integer>
+	<key>PayloadContent</key>
+	<array>` + strings.Join(payloads, "") + `</array>
+</dict>
+</plist>`)
+}
+
+const diffTestWiFi = `<dict>
+	<key>PayloadType</key><string>com.apple.wifi.managed</string>
+	<key>PayloadVersion</key><integer>1</integer>
+	<key>SSID_STR</key><string>%SSID%</string>
+	<key>HIDDEN_NETWORK</key>%HIDDEN%
+</dict>`
+
+const diffTestPPPC = `<dict>
+	<key>PayloadType</key><string>com.apple.TCC.configuration-profile-policy</string>
+	<key>PayloadVersion</key><integer>1</integer>
+	<key>Services</key>
+	<dict>
+		<key>SystemPolicyAllFiles</key>
+		<array>
+			<dict>
+				<key>Identifier</key><string>com.example.agent</string>
+				<key>IdentifierType</key><string>bundleID</string>
+				<key>CodeRequirement</key><string>anchor apple generic</string>
+				<key>Allowed</key><true/>
+				<key>VendorSetting</key><string>%VENDOR%</string>
+			</dict>
+		</array>
+	</dict>
+</dict>`
+
+const diffTestSystemExtensions = `<dict>
+	<key>PayloadType</key><string>com.apple.system-extension-policy</string>
+	<key>PayloadVersion</key><integer>1</integer>
+	<key>AllowedTeamIdentifiers</key>
+	<array>%TEAMS%</array>
+</dict>`
+
+func wifi(ssid, hidden string) string {
+	return strings.NewReplacer("%SSID%", ssid, "%HIDDEN%", hidden).Replace(diffTestWiFi)
+}
+
+func pppc(vendor string) string {
+	return strings.ReplaceAll(diffTestPPPC, "%VENDOR%", vendor)
+}
+
+func systemExtensions(teams ...string) string {
+	var items string
+	for _, team := range teams {
+		items += "<string>" + team + "</string>"
+	}
+	return strings.ReplaceAll(diffTestSystemExtensions, "%TEAMS%", items)
+}
+
+func TestDiffBytes(t *testing.T) {
+	tests := []struct {
+		name string
+		old  []byte
+		new  []byte
+		want []Difference
+	}{
+		{
+			name: "identical",
+			old:  diffTestProfile(wifi("Corp", "<false/>")),
+			new:  diffTestProfile(wifi("Corp", "<false/>")),
+		},
+		{
+			name: "value change",
+			old:  diffTestProfile(wifi("Corp", "<false/>")),
+			new:  diffTestProfile(wifi("Guest", "<false/>")),
+			want: []Difference{{Path: "PayloadContent[com.apple.wifi.managed#0].SSID_STR", Kind: DiffChanged, Old: "Corp", New: "Guest"}},
+		},
+		{
+			name: "bool flip",
+			old:  diffTestProfile(wifi("Corp", "<true/>")),
+			new:  diffTestProfile(wifi("Corp", "<false/>")),
+			want: []Difference{{Path: "PayloadContent[com.apple.wifi.managed#0].HIDDEN_NETWORK", Kind: DiffChanged, Old: true, New: false}},
+		},
+		{
+			name: "nested unknown key",
+			old:  diffTestProfile(pppc("one")),
+			new:  diffTestProfile(pppc("two")),
+			want: []Difference{{
+				Path: "PayloadContent[com.apple.TCC.configuration-profile-policy#0].Services.SystemPolicyAllFiles[0].VendorSetting",
+				Kind: DiffChanged, Old: "one", New: "two",
+			}},
+		},
+		{
+			name: "payload reorder",
+			old:  diffTestProfile(wifi("Corp", "<false/>"), pppc("one")),
+			new:  diffTestProfile(pppc("one"), wifi("Corp", "<false/>")),
+		},
+		{
+			name: "array reorder",
+			old:  diffTestProfile(systemExtensions("AAAAA11111", "BBBBB22222")),
+			new:  diffTestProfile(systemExtensions("BBBBB22222", "AAAAA11111")),
+			want: []Difference{
+				{Path: "PayloadContent[com.apple.system-extension-policy#0].AllowedTeamIdentifiers[0]", Kind: DiffChanged, Old: "AAAAA11111", New: "BBBBB22222"},
+				{Path: "PayloadContent[com.apple.system-extension-policy#0].AllowedTeamIdentifiers[1]", Kind: DiffChanged, Old: "BBBBB22222", New: "AAAAA11111"},
+			},
+		},
+		{
+			name: "array element added",
+			old:  diffTestProfile(systemExtensions("AAAAA11111")),
+			new:  diffTestProfile(systemExtensions("AAAAA11111", "BBBBB22222")),
+			want: []Difference{{Path: "PayloadContent[com.apple.system-extension-policy#0].AllowedTeamIdentifiers[1]", Kind: DiffAdded, New: "BBBBB22222"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DiffBytes(tt.old, tt.new, nil)
+			if err != nil {
+				t.Fatalf("DiffBytes: %v", err)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d differences %v, want %d %v", len(got), got, len(tt.want), tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("difference %d = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDiffTypedProfiles(t *testing.T) {
+	old, err := Parse(diffTestProfile(wifi("Corp", "<true/>"), pppc("one")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	new, err := Parse(diffTestProfile(wifi("Corp", "<false/>"), pppc("two")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diffs, err := Diff(old, new, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diffs) != 2 {
+		t.Fatalf("got %v, want the HIDDEN_NETWORK and VendorSetting changes", diffs)
+	}
+	for _, diff := range diffs {
+		if diff.Kind != DiffChanged {
+			t.Errorf("%v: kind %s, want %s", diff, diff.Kind, DiffChanged)
+		}
+	}
+}
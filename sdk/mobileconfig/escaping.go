@@ -0,0 +1,257 @@
+// mobileconfig/escaping.go
+// Normalisation of XML escaping in profile payloads.
+// Round tripping General.Payloads through the Classic API regularly produces documents that are
+// escaped as a whole (&lt;plist&gt;...) and string values that are escaped twice (&amp;amp;,
+// &amp;quot;, &amp;nbsp;). NormalizePayload removes exactly one extra layer of escaping and re-encodes
+// the property list; the configuration profile Create and Update methods apply it to every payload they
+// send. XML entities, named HTML entities (&nbsp;, &eacute;) and numeric character references are
+// decoded; text which merely looks like an entity name, such as &para=2 in a URL, is left alone.
+// reference: https://learn.jamf.com/bundle/technical-articles/page/Entity_Equivalents_for_Disallowed_XML_Characters.html
+
+package mobileconfig
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"howett.net/plist"
+)
+
+// NormalizableExtensions are the file extensions processed by CleanDirectory.
+var NormalizableExtensions = []string{".mobileconfig", ".plist"}
+
+// NormalizePayload removes one layer of escaping applied to a property list document as a whole, or
+// else one extra layer of escaping of its string values, then re-encodes it as an indented XML
+// property list. The structure and types of
+// the document are preserved, so it can be used on any plist, not only configuration profiles.
+func NormalizePayload(data []byte) ([]byte, error) {
+	document, escaped, err := unescapeDocument(data)
+	if err != nil {
+		return nil, err
+	}
+	if !escaped {
+		document = normalizeStrings(document)
+	}
+
+	var buf bytes.Buffer
+	encoder := plist.NewEncoderForFormat(&buf, plist.XMLFormat)
+	encoder.Indent("\t")
+	if err := encoder.Encode(document); err != nil {
+		return nil, fmt.Errorf("failed to marshal normalised plist: %v", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// NormalizePayloadString is a convenience wrapper around NormalizePayload for string payloads.
+func NormalizePayloadString(data string) (string, error) {
+	normalized, err := NormalizePayload([]byte(data))
+	if err != nil {
+		return "", err
+	}
+	return string(normalized), nil
+}
+
+// CleanFile normalises the plist at srcPath and writes the result to dstPath. The destination
+// directory is created when missing. srcPath and dstPath may be the same file.
+func CleanFile(srcPath, dstPath string) error {
+	data, err := os.ReadFile(srcPath)
+	if err != nil {
+		return fmt.Errorf("error reading file '%s': %v", srcPath, err)
+	}
+
+	normalized, err := NormalizePayload(data)
+	if err != nil {
+		return fmt.Errorf("error normalising file '%s': %v", srcPath, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dstPath), 0750); err != nil {
+		return fmt.Errorf("error creating directory for '%s': %v", dstPath, err)
+	}
+
+	if err := os.WriteFile(dstPath, normalized, 0644); err != nil {
+		return fmt.Errorf("error writing file '%s': %v", dstPath, err)
+	}
+
+	return nil
+}
+
+// CleanDirectory normalises every file with one of the NormalizableExtensions in srcDir and writes
+// it to the same relative path below dstDir. Sub directories are processed when recursive is true.
+// Pass the same directory for srcDir and dstDir to clean files in place. The paths of the written
+// files are returned; processing stops at the first error.
+func CleanDirectory(srcDir, dstDir string, recursive bool) ([]string, error) {
+	var written []string
+
+	err := filepath.WalkDir(srcDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			if path != srcDir && !recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !hasNormalizableExtension(path) {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+
+		dstPath := filepath.Join(dstDir, relPath)
+		if err := CleanFile(path, dstPath); err != nil {
+			return err
+		}
+
+		written = append(written, dstPath)
+		return nil
+	})
+
+	return written, err
+}
+
+// unescapeDocument decodes data. A document escaped as a whole is unescaped once before decoding, and
+// its string values are then left as they are, as that single layer was the extra one.
+func unescapeDocument(data []byte) (interface{}, bool, error) {
+	content := strings.TrimSpace(string(data))
+
+	escaped := strings.HasPrefix(content, "&lt;")
+	if escaped {
+		content = unescapeXMLEntities(content)
+	}
+
+	var document interface{}
+	if _, err := plist.Unmarshal([]byte(content), &document); err != nil {
+		return nil, escaped, fmt.Errorf("error decoding plist: %v", err)
+	}
+
+	return document, escaped, nil
+}
+
+// normalizeStrings walks a decoded plist and removes one layer of escaping from every string value.
+func normalizeStrings(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return unescapeXMLEntities(v)
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = normalizeStrings(item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalizeStrings(item)
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+// unescapeXMLEntities decodes a single level of escaping: named XML and HTML entities and numeric
+// character references. Decoded text is not scanned again, so &amp;amp; becomes &amp;. Anything else,
+// including a bare &, is kept as is.
+func unescapeXMLEntities(s string) string {
+	if !strings.Contains(s, "&") {
+		return s
+	}
+
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(s, '&')
+		if start < 0 {
+			b.WriteString(s)
+			return b.String()
+		}
+		b.WriteString(s[:start])
+		s = s[start:]
+
+		end := strings.IndexByte(s, ';')
+		if end < 0 {
+			b.WriteString(s)
+			return b.String()
+		}
+
+		if decoded, ok := decodeXMLEntity(s[1:end]); ok {
+			b.WriteString(decoded)
+			s = s[end+1:]
+			continue
+		}
+
+		b.WriteByte('&')
+		s = s[1:]
+	}
+}
+
+// decodeXMLEntity decodes the name of an entity, without & and ;, if it is a named XML or HTML entity or a
+// numeric character reference of a character allowed in XML.
+func decodeXMLEntity(name string) (string, bool) {
+	if !strings.HasPrefix(name, "#") {
+		return decodeNamedEntity(name)
+	}
+
+	var code uint64
+	var err error
+	if strings.HasPrefix(name, "#x") || strings.HasPrefix(name, "#X") {
+		code, err = strconv.ParseUint(name[2:], 16, 32)
+	} else {
+		code, err = strconv.ParseUint(name[1:], 10, 32)
+	}
+	if err != nil || !isXMLChar(rune(code)) {
+		return "", false
+	}
+
+	return string(rune(code)), true
+}
+
+// decodeNamedEntity decodes a named XML or HTML entity. html.UnescapeString also decodes a legacy entity at
+// the start of a longer name (&notit; to ¬it;), which is not a single entity and is refused.
+func decodeNamedEntity(name string) (string, bool) {
+	if name == "" {
+		return "", false
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return "", false
+		}
+	}
+
+	entity := "&" + name + ";"
+	decoded := html.UnescapeString(entity)
+	if decoded == entity || (strings.HasSuffix(decoded, ";") && name != "semi") {
+		return "", false
+	}
+
+	return decoded, true
+}
+
+// isXMLChar reports whether r is allowed in an XML 1.0 document.
+func isXMLChar(r rune) bool {
+	return r == 0x09 || r == 0x0A || r == 0x0D ||
+		r >= 0x20 && r <= 0xD7FF ||
+		r >= 0xE000 && r <= 0xFFFD ||
+		r >= 0x10000 && r <= 0x10FFFF
+}
+
+// hasNormalizableExtension reports whether path has one of the NormalizableExtensions.
+func hasNormalizableExtension(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, allowed := range NormalizableExtensions {
+		if ext == allowed {
+			return true
+		}
+	}
+	return false
+}
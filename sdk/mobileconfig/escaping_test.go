@@ -0,0 +1,115 @@
+package mobileconfig
+
+import (
+	"reflect"
+	"testing"
+
+	"howett.net/plist"
+)
+
+func TestUnescapeXMLEntities(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "plain text", want: "plain text"},
+		{in: "AT&T", want: "AT&T"},
+		{in: "&amp;", want: "&"},
+		{in: "&amp;amp;", want: "&amp;"},
+		{in: "&amp;amp;amp;", want: "&amp;amp;"},
+		{in: "&lt;b&gt; &quot;x&quot; &apos;y&apos;", want: `<b> "x" 'y'`},
+		{in: "?id=1&para=2&copy=3", want: "?id=1&para=2&copy=3"},
+		{in: "&para;&copy;&nbsp;&eacute;", want: "\u00b6\u00a9\u00a0\u00e9"},
+		{in: "&amp;nbsp;&amp;eacute;", want: "&nbsp;&eacute;"},
+		{in: "&semi;&notit;&unknown;", want: ";&notit;&unknown;"},
+		{in: "&#38;&#x26;&#X3C;", want: "&&<"},
+		{in: "&#0;&#xD800;&#xZZ;", want: "&#0;&#xD800;&#xZZ;"},
+		{in: "trailing &", want: "trailing &"},
+		{in: "& amp;", want: "& amp;"},
+	}
+
+	for _, tt := range tests {
+		if got := unescapeXMLEntities(tt.in); got != tt.want {
+			t.Errorf("unescapeXMLEntities(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+// normalizeTestValue normalises a plist holding a single string and returns the decoded value.
+func normalizeTestValue(t *testing.T, document string) string {
+	t.Helper()
+
+	normalized, err := NormalizePayload([]byte(document))
+	if err != nil {
+		t.Fatalf("NormalizePayload: %v", err)
+	}
+
+	var value map[string]interface{}
+	if _, err := plist.Unmarshal(normalized, &value); err != nil {
+		t.Fatalf("normalised plist does not decode: %v", err)
+	}
+	return value["Value"].(string)
+}
+
+func TestNormalizePayload(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		want     string
+	}{
+		{
+			name:     "html entity names in a url are kept",
+			document: `<plist version="1.0"><dict><key>Value</key><string>https://example.com/?id=1&amp;para=2&amp;copy=3</string></dict></plist>`,
+			want:     "https://example.com/?id=1&para=2&copy=3",
+		},
+		{
+			name:     "value escaped twice loses one layer",
+			document: `<plist version="1.0"><dict><key>Value</key><string>Tom &amp;amp; Jerry</string></dict></plist>`,
+			want:     "Tom & Jerry",
+		},
+		{
+			name:     "html entities escaped twice are decoded",
+			document: `<plist version="1.0"><dict><key>Value</key><string>Caf&amp;eacute;&amp;nbsp;Wi-Fi</string></dict></plist>`,
+			want:     "Caf\u00e9\u00a0Wi-Fi",
+		},
+		{
+			name:     "value escaped three times loses only one layer",
+			document: `<plist version="1.0"><dict><key>Value</key><string>Tom &amp;amp;amp; Jerry</string></dict></plist>`,
+			want:     "Tom &amp; Jerry",
+		},
+		{
+			name:     "clean value is unchanged",
+			document: `<plist version="1.0"><dict><key>Value</key><string>Tom &amp; Jerry &lt;3</string></dict></plist>`,
+			want:     "Tom & Jerry <3",
+		},
+		{
+			name:     "document escaped as a whole keeps its values",
+			document: `&lt;plist version="1.0"&gt;&lt;dict&gt;&lt;key&gt;Value&lt;/key&gt;&lt;string&gt;Tom &amp;amp; Jerry&lt;/string&gt;&lt;/dict&gt;&lt;/plist&gt;`,
+			want:     "Tom & Jerry",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeTestValue(t, tt.document); got != tt.want {
+				t.Errorf("value = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizePayloadIsStableOnCleanInput(t *testing.T) {
+	clean, err := New("com.example.clean", "Clean").AddPayload(NewWiFiPayload("Tom & Jerry", WiFiEncryptionWPA2)).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	normalized, err := NormalizePayload(clean)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(decodeRaw(t, clean), decodeRaw(t, normalized)) {
+		t.Errorf("normalising a clean profile changed it:\n%s", normalized)
+	}
+}
@@ -0,0 +1,74 @@
+// mobileconfig/identifiers.go
+// Deterministic payload identifiers and UUIDs.
+// Generating UUIDs from identifiers rather than randomly means that re-building the same profile
+// produces byte identical output, which keeps diffs meaningful and avoids needless redeployments.
+
+package mobileconfig
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// uuidNamespace is the UUIDv5 namespace used for all generated payload UUIDs.
+var uuidNamespace = uuid.NewSHA1(uuid.NameSpaceURL, []byte("https://github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/mobileconfig"))
+
+// DeterministicUUID returns an upper case UUIDv5 derived from the given name. The same name
+// always yields the same UUID.
+func DeterministicUUID(name string) string {
+	return strings.ToUpper(uuid.NewSHA1(uuidNamespace, []byte(name)).String())
+}
+
+// Finalize fills in any missing PayloadType, PayloadVersion, PayloadIdentifier and PayloadUUID
+// values on the profile and its payloads. Payload identifiers are derived from the profile
+// identifier and payload type, with an index suffix when a type appears more than once.
+// UUIDs are derived from identifiers using DeterministicUUID. Values that are already set
+// are left untouched.
+func (p *Profile) Finalize() error {
+	if p.PayloadIdentifier == "" {
+		return fmt.Errorf("configuration profile PayloadIdentifier is required")
+	}
+
+	if p.PayloadType == "" {
+		p.PayloadType = ProfileTypeConfiguration
+	}
+	if p.PayloadVersion == 0 {
+		p.PayloadVersion = 1
+	}
+	if p.PayloadUUID == "" {
+		p.PayloadUUID = DeterministicUUID(p.PayloadIdentifier)
+	}
+
+	typeCounts := map[string]int{}
+	for _, payload := range p.Payloads {
+		typeCounts[payload.Common().PayloadType]++
+	}
+
+	typeIndex := map[string]int{}
+	for i, payload := range p.Payloads {
+		common := payload.Common()
+		if common.PayloadType == "" {
+			return fmt.Errorf("payload %d has no PayloadType", i)
+		}
+
+		if common.PayloadVersion == 0 {
+			common.PayloadVersion = 1
+		}
+
+		if common.PayloadIdentifier == "" {
+			common.PayloadIdentifier = fmt.Sprintf("%s.%s", p.PayloadIdentifier, common.PayloadType)
+			if typeCounts[common.PayloadType] > 1 {
+				common.PayloadIdentifier = fmt.Sprintf("%s.%d", common.PayloadIdentifier, typeIndex[common.PayloadType])
+			}
+		}
+		typeIndex[common.PayloadType]++
+
+		if common.PayloadUUID == "" {
+			common.PayloadUUID = DeterministicUUID(common.PayloadIdentifier)
+		}
+	}
+
+	return nil
+}
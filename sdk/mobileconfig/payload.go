@@ -0,0 +1,136 @@
+// mobileconfig/payload.go
+// Common payload keys, the payload type registry and the generic payload fallback.
+// reference: https://developer.apple.com/documentation/devicemanagement/commonpayloadkeys
+
+package mobileconfig
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Payload is implemented by every entry of a profile's PayloadContent array.
+type Payload interface {
+	// Common returns the common payload keys shared by all payload types.
+	Common() *PayloadCommon
+}
+
+// PayloadCommon holds the keys shared by every payload. Typed payloads embed it.
+type PayloadCommon struct {
+	PayloadType         string `plist:"PayloadType"`
+	PayloadIdentifier   string `plist:"PayloadIdentifier"`
+	PayloadUUID         string `plist:"PayloadUUID"`
+	PayloadVersion      int    `plist:"PayloadVersion"`
+	PayloadDisplayName  string `plist:"PayloadDisplayName,omitempty"`
+	PayloadDescription  string `plist:"PayloadDescription,omitempty"`
+	PayloadOrganization string `plist:"PayloadOrganization,omitempty"`
+
+	// Extra holds payload keys that are not modelled by the typed payload struct, or set to an empty value it would omit.
+	Extra map[string]interface{} `plist:"-"`
+}
+
+// Common implements Payload.
+func (c *PayloadCommon) Common() *PayloadCommon {
+	return c
+}
+
+// GenericPayload is used for payload types without a typed struct. All payload specific keys
+// are kept in Extra.
+type GenericPayload struct {
+	PayloadCommon
+}
+
+// NewGenericPayload returns a payload of the given type with the supplied payload specific keys.
+func NewGenericPayload(payloadType string, values map[string]interface{}) *GenericPayload {
+	return &GenericPayload{
+		PayloadCommon: PayloadCommon{
+			PayloadType: payloadType,
+			Extra:       values,
+		},
+	}
+}
+
+// payloadTypes maps a PayloadType to a constructor for its typed struct.
+var payloadTypes = map[string]func() Payload{
+	PayloadTypePPPC:             func() Payload { return &PPPCPayload{} },
+	PayloadTypeWiFi:             func() Payload { return &WiFiPayload{} },
+	PayloadTypeSCEP:             func() Payload { return &SCEPPayload{} },
+	PayloadTypeRestrictions:     func() Payload { return &RestrictionsPayload{} },
+	PayloadTypeNotifications:    func() Payload { return &NotificationsPayload{} },
+	PayloadTypeSystemExtensions: func() Payload { return &SystemExtensionsPayload{} },
+	PayloadTypeLoginItems:       func() Payload { return &LoginItemsPayload{} },
+}
+
+// decodePayload decodes a PayloadContent dictionary into the typed struct registered for its
+// PayloadType, falling back to GenericPayload.
+func decodePayload(raw map[string]interface{}) (Payload, error) {
+	payloadType, _ := raw["PayloadType"].(string)
+	if payloadType == "" {
+		return nil, fmt.Errorf("payload has no PayloadType")
+	}
+
+	var payload Payload = &GenericPayload{}
+	if constructor, ok := payloadTypes[payloadType]; ok {
+		payload = constructor()
+	}
+
+	if err := decodeInto(raw, payload); err != nil {
+		return nil, fmt.Errorf("failed to decode %s payload: %v", payloadType, err)
+	}
+
+	payload.Common().Extra = unknownKeys(raw, payload)
+
+	return payload, nil
+}
+
+// unmarshalWithExtra decodes a nested dictionary into v, the method-less alias of a typed struct, and
+// returns the keys which v does not model. Nested typed structs use it to keep unknown keys, see
+// PPPCServiceRule.UnmarshalPlist.
+func unmarshalWithExtra(unmarshal func(interface{}) error, v interface{}) (map[string]interface{}, error) {
+	var raw map[string]interface{}
+	if err := unmarshal(&raw); err != nil {
+		return nil, err
+	}
+	if err := unmarshal(v); err != nil {
+		return nil, err
+	}
+
+	return unknownKeys(raw, v), nil
+}
+
+// plistKeys returns the set of plist keys mapped by the exported fields of the struct v,
+// including fields promoted from embedded structs.
+func plistKeys(v interface{}) map[string]bool {
+	keys := map[string]bool{}
+
+	typ := reflect.TypeOf(v)
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	collectPlistKeys(typ, keys)
+
+	return keys
+}
+
+// collectPlistKeys walks the fields of typ and records their plist key names.
+func collectPlistKeys(typ reflect.Type, keys map[string]bool) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("plist")
+		if field.PkgPath != "" || tag == "-" {
+			continue
+		}
+
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			collectPlistKeys(field.Type, keys)
+			continue
+		}
+
+		name := strings.Split(tag, ",")[0]
+		if name == "" {
+			name = field.Name
+		}
+		keys[name] = true
+	}
+}
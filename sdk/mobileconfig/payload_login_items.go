@@ -0,0 +1,56 @@
+// mobileconfig/payload_login_items.go
+// Service management (managed login items) payload
+// reference: https://developer.apple.com/documentation/devicemanagement/servicemanagementmanagedloginitems
+
+package mobileconfig
+
+const PayloadTypeLoginItems = "com.apple.servicemanagement"
+
+// Login item rule types
+const (
+	LoginItemRuleBundleIdentifier       = "BundleIdentifier"
+	LoginItemRuleBundleIdentifierPrefix = "BundleIdentifierPrefix"
+	LoginItemRuleLabel                  = "Label"
+	LoginItemRuleLabelPrefix            = "LabelPrefix"
+	LoginItemRuleTeamIdentifier         = "TeamIdentifier"
+)
+
+// LoginItemsPayload marks background items and login items as managed on macOS.
+type LoginItemsPayload struct {
+	PayloadCommon
+
+	Rules []LoginItemRule `plist:"Rules"`
+}
+
+// LoginItemRule matches login items by one of the LoginItemRule* types.
+type LoginItemRule struct {
+	RuleType       string `plist:"RuleType"`
+	RuleValue      string `plist:"RuleValue"`
+	TeamIdentifier string `plist:"TeamIdentifier,omitempty"`
+	Comment        string `plist:"Comment,omitempty"`
+
+	// Extra holds rule keys that are not modelled by LoginItemRule, or set to an empty value it would omit.
+	Extra map[string]interface{} `plist:"-"`
+}
+
+// UnmarshalPlist implements plist.Unmarshaler, keeping unknown keys in Extra.
+func (r *LoginItemRule) UnmarshalPlist(unmarshal func(interface{}) error) error {
+	type rule LoginItemRule
+	extra, err := unmarshalWithExtra(unmarshal, (*rule)(r))
+	r.Extra = extra
+	return err
+}
+
+// MarshalPlist implements plist.Marshaler, merging Extra back in.
+func (r LoginItemRule) MarshalPlist() (interface{}, error) {
+	type rule LoginItemRule
+	return encodeToMap(rule(r), r.Extra)
+}
+
+// NewLoginItemsPayload returns a login items payload with the given rules.
+func NewLoginItemsPayload(rules ...LoginItemRule) *LoginItemsPayload {
+	return &LoginItemsPayload{
+		PayloadCommon: PayloadCommon{PayloadType: PayloadTypeLoginItems},
+		Rules:         rules,
+	}
+}
@@ -0,0 +1,61 @@
+// mobileconfig/payload_notifications.go
+// Notifications payload
+// reference: https://developer.apple.com/documentation/devicemanagement/notifications
+
+package mobileconfig
+
+const PayloadTypeNotifications = "com.apple.notificationsettings"
+
+// Notification alert types
+const (
+	NotificationAlertTypeNone   = 0
+	NotificationAlertTypeBanner = 1
+	NotificationAlertTypeModal  = 2
+)
+
+// NotificationsPayload configures notification settings per application.
+type NotificationsPayload struct {
+	PayloadCommon
+
+	NotificationSettings []NotificationSetting `plist:"NotificationSettings"`
+}
+
+// NotificationSetting holds the notification settings of a single application.
+type NotificationSetting struct {
+	BundleIdentifier         string `plist:"BundleIdentifier"`
+	NotificationsEnabled     *bool  `plist:"NotificationsEnabled,omitempty"`
+	AlertType                *int   `plist:"AlertType,omitempty"`
+	BadgesEnabled            *bool  `plist:"BadgesEnabled,omitempty"`
+	CriticalAlertEnabled     *bool  `plist:"CriticalAlertEnabled,omitempty"`
+	GroupingType             *int   `plist:"GroupingType,omitempty"`
+	PreviewType              *int   `plist:"PreviewType,omitempty"`
+	ShowInCarPlay            *bool  `plist:"ShowInCarPlay,omitempty"`
+	ShowInLockScreen         *bool  `plist:"ShowInLockScreen,omitempty"`
+	ShowInNotificationCenter *bool  `plist:"ShowInNotificationCenter,omitempty"`
+	SoundsEnabled            *bool  `plist:"SoundsEnabled,omitempty"`
+
+	// Extra holds setting keys that are not modelled by NotificationSetting, or set to an empty value it would omit.
+	Extra map[string]interface{} `plist:"-"`
+}
+
+// UnmarshalPlist implements plist.Unmarshaler, keeping unknown keys in Extra.
+func (s *NotificationSetting) UnmarshalPlist(unmarshal func(interface{}) error) error {
+	type setting NotificationSetting
+	extra, err := unmarshalWithExtra(unmarshal, (*setting)(s))
+	s.Extra = extra
+	return err
+}
+
+// MarshalPlist implements plist.Marshaler, merging Extra back in.
+func (s NotificationSetting) MarshalPlist() (interface{}, error) {
+	type setting NotificationSetting
+	return encodeToMap(setting(s), s.Extra)
+}
+
+// NewNotificationsPayload returns a notifications payload with the given settings.
+func NewNotificationsPayload(settings ...NotificationSetting) *NotificationsPayload {
+	return &NotificationsPayload{
+		PayloadCommon:        PayloadCommon{PayloadType: PayloadTypeNotifications},
+		NotificationSettings: settings,
+	}
+}
@@ -0,0 +1,90 @@
+// mobileconfig/payload_pppc.go
+// Privacy Preferences Policy Control (PPPC / TCC) payload
+// reference: https://developer.apple.com/documentation/devicemanagement/privacypreferencespolicycontrol
+
+package mobileconfig
+
+const PayloadTypePPPC = "com.apple.TCC.configuration-profile-policy"
+
+// PPPC service keys
+const (
+	PPPCServiceAccessibility         = "Accessibility"
+	PPPCServiceAddressBook           = "AddressBook"
+	PPPCServiceAppleEvents           = "AppleEvents"
+	PPPCServiceCalendar              = "Calendar"
+	PPPCServiceCamera                = "Camera"
+	PPPCServiceListenEvent           = "ListenEvent"
+	PPPCServiceMicrophone            = "Microphone"
+	PPPCServicePhotos                = "Photos"
+	PPPCServicePostEvent             = "PostEvent"
+	PPPCServiceReminders             = "Reminders"
+	PPPCServiceScreenCapture         = "ScreenCapture"
+	PPPCServiceSystemPolicyAllFiles  = "SystemPolicyAllFiles"
+	PPPCServiceSystemPolicySysAdmin  = "SystemPolicySysAdminFiles"
+	PPPCServiceSystemPolicyDocuments = "SystemPolicyDocumentsFolder"
+	PPPCServiceSystemPolicyDownloads = "SystemPolicyDownloadsFolder"
+	PPPCServiceSystemPolicyDesktop   = "SystemPolicyDesktopFolder"
+)
+
+// PPPC authorization values
+const (
+	PPPCAuthorizationAllow                  = "Allow"
+	PPPCAuthorizationDeny                   = "Deny"
+	PPPCAuthorizationAllowStandardUserToSet = "AllowStandardUserToSetSystemService"
+)
+
+// PPPCPayload grants or denies access to privacy protected services on macOS.
+type PPPCPayload struct {
+	PayloadCommon
+
+	// Services maps a service key, such as PPPCServiceSystemPolicyAllFiles, to its rules.
+	Services map[string][]PPPCServiceRule `plist:"Services"`
+}
+
+// PPPCServiceRule identifies an application or process and the access it is given to a service.
+type PPPCServiceRule struct {
+	Identifier                string `plist:"Identifier"`
+	IdentifierType            string `plist:"IdentifierType"`
+	CodeRequirement           string `plist:"CodeRequirement"`
+	Allowed                   *bool  `plist:"Allowed,omitempty"`
+	Authorization             string `plist:"Authorization,omitempty"`
+	StaticCode                *bool  `plist:"StaticCode,omitempty"`
+	Comment                   string `plist:"Comment,omitempty"`
+	AEReceiverIdentifier      string `plist:"AEReceiverIdentifier,omitempty"`
+	AEReceiverIdentifierType  string `plist:"AEReceiverIdentifierType,omitempty"`
+	AEReceiverCodeRequirement string `plist:"AEReceiverCodeRequirement,omitempty"`
+
+	// Extra holds rule keys that are not modelled by PPPCServiceRule, or set to an empty value it would omit.
+	Extra map[string]interface{} `plist:"-"`
+}
+
+// UnmarshalPlist implements plist.Unmarshaler, keeping unknown keys in Extra.
+func (r *PPPCServiceRule) UnmarshalPlist(unmarshal func(interface{}) error) error {
+	type rule PPPCServiceRule
+	extra, err := unmarshalWithExtra(unmarshal, (*rule)(r))
+	r.Extra = extra
+	return err
+}
+
+// MarshalPlist implements plist.Marshaler, merging Extra back in.
+func (r PPPCServiceRule) MarshalPlist() (interface{}, error) {
+	type rule PPPCServiceRule
+	return encodeToMap(rule(r), r.Extra)
+}
+
+// NewPPPCPayload returns an empty PPPC payload.
+func NewPPPCPayload() *PPPCPayload {
+	return &PPPCPayload{
+		PayloadCommon: PayloadCommon{PayloadType: PayloadTypePPPC},
+		Services:      map[string][]PPPCServiceRule{},
+	}
+}
+
+// AddRule appends a rule for the given service key and returns the payload to allow chaining.
+func (p *PPPCPayload) AddRule(service string, rule PPPCServiceRule) *PPPCPayload {
+	if p.Services == nil {
+		p.Services = map[string][]PPPCServiceRule{}
+	}
+	p.Services[service] = append(p.Services[service], rule)
+	return p
+}
@@ -0,0 +1,35 @@
+// mobileconfig/payload_restrictions.go
+// Restrictions payload
+// reference: https://developer.apple.com/documentation/devicemanagement/restrictions
+
+package mobileconfig
+
+const PayloadTypeRestrictions = "com.apple.applicationaccess"
+
+// RestrictionsPayload restricts device features. Only commonly used keys are typed, all other
+// restriction keys are preserved in Extra. Pointers are used so an explicit false or 0 is kept.
+type RestrictionsPayload struct {
+	PayloadCommon
+
+	AllowAirDrop                 *bool `plist:"allowAirDrop,omitempty"`
+	AllowAppInstallation         *bool `plist:"allowAppInstallation,omitempty"`
+	AllowAppRemoval              *bool `plist:"allowAppRemoval,omitempty"`
+	AllowCamera                  *bool `plist:"allowCamera,omitempty"`
+	AllowCloudDocumentSync       *bool `plist:"allowCloudDocumentSync,omitempty"`
+	AllowEraseContentAndSettings *bool `plist:"allowEraseContentAndSettings,omitempty"`
+	AllowExplicitContent         *bool `plist:"allowExplicitContent,omitempty"`
+	AllowPasswordAutoFill        *bool `plist:"allowPasswordAutoFill,omitempty"`
+	AllowPasswordSharing         *bool `plist:"allowPasswordSharing,omitempty"`
+	AllowScreenShot              *bool `plist:"allowScreenShot,omitempty"`
+	AllowUSBRestrictedMode       *bool `plist:"allowUSBRestrictedMode,omitempty"`
+	ForceDelayedSoftwareUpdates  *bool `plist:"forceDelayedSoftwareUpdates,omitempty"`
+	EnforcedSoftwareUpdateDelay  *int  `plist:"enforcedSoftwareUpdateDelay,omitempty"`
+	ForceEncryptedBackup         *bool `plist:"forceEncryptedBackup,omitempty"`
+}
+
+// NewRestrictionsPayload returns an empty restrictions payload.
+func NewRestrictionsPayload() *RestrictionsPayload {
+	return &RestrictionsPayload{
+		PayloadCommon: PayloadCommon{PayloadType: PayloadTypeRestrictions},
+	}
+}
@@ -0,0 +1,57 @@
+// mobileconfig/payload_scep.go
+// SCEP payload
+// reference: https://developer.apple.com/documentation/devicemanagement/scep
+
+package mobileconfig
+
+const PayloadTypeSCEP = "com.apple.security.scep"
+
+// SCEPPayload requests a certificate from a SCEP server.
+type SCEPPayload struct {
+	PayloadCommon
+
+	PayloadContent SCEPPayloadContent `plist:"PayloadContent"`
+}
+
+// SCEPPayloadContent holds the SCEP enrolment settings.
+type SCEPPayloadContent struct {
+	URL                string                 `plist:"URL"`
+	Name               string                 `plist:"Name,omitempty"`
+	Subject            [][][]string           `plist:"Subject,omitempty"`
+	Challenge          string                 `plist:"Challenge,omitempty"`
+	Keysize            *int                   `plist:"Keysize,omitempty"`
+	KeyType            string                 `plist:"Key Type,omitempty"`
+	KeyUsage           *int                   `plist:"Key Usage,omitempty"`
+	Retries            *int                   `plist:"Retries,omitempty"`
+	RetryDelay         *int                   `plist:"RetryDelay,omitempty"`
+	CAFingerprint      []byte                 `plist:"CAFingerprint,omitempty"`
+	SubjectAltName     map[string]interface{} `plist:"SubjectAltName,omitempty"`
+	AllowAllAppsAccess *bool                  `plist:"AllowAllAppsAccess,omitempty"`
+	KeyIsExtractable   *bool                  `plist:"KeyIsExtractable,omitempty"`
+
+	// Extra holds enrolment keys that are not modelled by SCEPPayloadContent, or set to an empty value it would omit.
+	Extra map[string]interface{} `plist:"-"`
+}
+
+// UnmarshalPlist implements plist.Unmarshaler, keeping unknown keys in Extra.
+func (c *SCEPPayloadContent) UnmarshalPlist(unmarshal func(interface{}) error) error {
+	type content SCEPPayloadContent
+	extra, err := unmarshalWithExtra(unmarshal, (*content)(c))
+	c.Extra = extra
+	return err
+}
+
+// MarshalPlist implements plist.Marshaler, merging Extra back in.
+func (c SCEPPayloadContent) MarshalPlist() (interface{}, error) {
+	type content SCEPPayloadContent
+	return encodeToMap(content(c), c.Extra)
+}
+
+// NewSCEPPayload returns a SCEP payload for the given server URL.
+func NewSCEPPayload(url string) *SCEPPayload {
+	keysize := 2048
+	return &SCEPPayload{
+		PayloadCommon:  PayloadCommon{PayloadType: PayloadTypeSCEP},
+		PayloadContent: SCEPPayloadContent{URL: url, Keysize: &keysize, KeyType: "RSA"},
+	}
+}
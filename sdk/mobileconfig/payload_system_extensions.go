@@ -0,0 +1,36 @@
+// mobileconfig/payload_system_extensions.go
+// System extensions payload
+// reference: https://developer.apple.com/documentation/devicemanagement/systemextensions
+
+package mobileconfig
+
+const PayloadTypeSystemExtensions = "com.apple.system-extension-policy"
+
+// SystemExtensionsPayload controls which system extensions may be loaded on macOS.
+// The map keys of the extension dictionaries are team identifiers.
+type SystemExtensionsPayload struct {
+	PayloadCommon
+
+	AllowUserOverrides                 *bool               `plist:"AllowUserOverrides,omitempty"`
+	AllowedTeamIdentifiers             []string            `plist:"AllowedTeamIdentifiers,omitempty"`
+	AllowedSystemExtensions            map[string][]string `plist:"AllowedSystemExtensions,omitempty"`
+	AllowedSystemExtensionTypes        map[string][]string `plist:"AllowedSystemExtensionTypes,omitempty"`
+	RemovableSystemExtensions          map[string][]string `plist:"RemovableSystemExtensions,omitempty"`
+	NonRemovableFromUISystemExtensions map[string][]string `plist:"NonRemovableFromUISystemExtensions,omitempty"`
+}
+
+// NewSystemExtensionsPayload returns an empty system extensions payload.
+func NewSystemExtensionsPayload() *SystemExtensionsPayload {
+	return &SystemExtensionsPayload{
+		PayloadCommon: PayloadCommon{PayloadType: PayloadTypeSystemExtensions},
+	}
+}
+
+// AllowExtension allows the system extension bundle identifier signed by the given team.
+func (p *SystemExtensionsPayload) AllowExtension(teamIdentifier, bundleIdentifier string) *SystemExtensionsPayload {
+	if p.AllowedSystemExtensions == nil {
+		p.AllowedSystemExtensions = map[string][]string{}
+	}
+	p.AllowedSystemExtensions[teamIdentifier] = append(p.AllowedSystemExtensions[teamIdentifier], bundleIdentifier)
+	return p
+}
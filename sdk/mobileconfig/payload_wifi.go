@@ -0,0 +1,46 @@
+// mobileconfig/payload_wifi.go
+// Wi-Fi payload
+// reference: https://developer.apple.com/documentation/devicemanagement/wifi
+
+package mobileconfig
+
+const PayloadTypeWiFi = "com.apple.wifi.managed"
+
+// Wi-Fi encryption types
+const (
+	WiFiEncryptionNone = "None"
+	WiFiEncryptionWEP  = "WEP"
+	WiFiEncryptionWPA  = "WPA"
+	WiFiEncryptionWPA2 = "WPA2"
+	WiFiEncryptionWPA3 = "WPA3"
+	WiFiEncryptionAny  = "Any"
+)
+
+// WiFiPayload configures a Wi-Fi network.
+type WiFiPayload struct {
+	PayloadCommon
+
+	SSID                               string                 `plist:"SSID_STR"`
+	HiddenNetwork                      *bool                  `plist:"HIDDEN_NETWORK,omitempty"`
+	AutoJoin                           *bool                  `plist:"AutoJoin,omitempty"`
+	EncryptionType                     string                 `plist:"EncryptionType,omitempty"`
+	Password                           string                 `plist:"Password,omitempty"`
+	IsHotspot                          *bool                  `plist:"IsHotspot,omitempty"`
+	CaptiveBypass                      *bool                  `plist:"CaptiveBypass,omitempty"`
+	DisableAssociationMACRandomization *bool                  `plist:"DisableAssociationMACRandomization,omitempty"`
+	ProxyType                          string                 `plist:"ProxyType,omitempty"`
+	ProxyServer                        string                 `plist:"ProxyServer,omitempty"`
+	ProxyServerPort                    *int                   `plist:"ProxyServerPort,omitempty"`
+	ProxyPACURL                        string                 `plist:"ProxyPACURL,omitempty"`
+	PayloadCertificateUUID             string                 `plist:"PayloadCertificateUUID,omitempty"`
+	EAPClientConfiguration             map[string]interface{} `plist:"EAPClientConfiguration,omitempty"`
+}
+
+// NewWiFiPayload returns a Wi-Fi payload for the given SSID and encryption type.
+func NewWiFiPayload(ssid, encryptionType string) *WiFiPayload {
+	return &WiFiPayload{
+		PayloadCommon:  PayloadCommon{PayloadType: PayloadTypeWiFi},
+		SSID:           ssid,
+		EncryptionType: encryptionType,
+	}
+}
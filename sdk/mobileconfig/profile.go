@@ -0,0 +1,269 @@
+// mobileconfig/profile.go
+// Typed representation of Apple configuration profiles (.mobileconfig)
+// reference: https://developer.apple.com/documentation/devicemanagement/toplevel
+// Profiles are parsed from and emitted as XML property lists. Keys which are not modelled by the
+// typed structs are kept in Extra maps so that a parse/marshal round trip does not drop settings.
+
+package mobileconfig
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"howett.net/plist"
+)
+
+const (
+	// ProfileTypeConfiguration is the top level PayloadType of a configuration profile.
+	ProfileTypeConfiguration = "Configuration"
+
+	// Profile scopes as used by macOS
+	ProfileScopeSystem = "System"
+	ProfileScopeUser   = "User"
+)
+
+// Profile represents the top level dictionary of a configuration profile.
+type Profile struct {
+	PayloadDisplayName       string `plist:"PayloadDisplayName,omitempty"`
+	PayloadDescription       string `plist:"PayloadDescription,omitempty"`
+	PayloadIdentifier        string `plist:"PayloadIdentifier"`
+	PayloadOrganization      string `plist:"PayloadOrganization,omitempty"`
+	PayloadType              string `plist:"PayloadType"`
+	PayloadUUID              string `plist:"PayloadUUID"`
+	PayloadVersion           int    `plist:"PayloadVersion"`
+	PayloadScope             string `plist:"PayloadScope,omitempty"`
+	PayloadRemovalDisallowed *bool  `plist:"PayloadRemovalDisallowed,omitempty"`
+
+	// Payloads holds the entries of PayloadContent. Known payload types are decoded into
+	// their typed structs, everything else into *GenericPayload.
+	Payloads []Payload `plist:"-"`
+
+	// Extra holds top level keys that are not modelled by Profile, or set to an empty value it would omit.
+	Extra map[string]interface{} `plist:"-"`
+}
+
+// New returns an empty configuration profile with the given identifier and display name.
+func New(identifier, displayName string) *Profile {
+	return &Profile{
+		PayloadDisplayName: displayName,
+		PayloadIdentifier:  identifier,
+		PayloadType:        ProfileTypeConfiguration,
+		PayloadVersion:     1,
+	}
+}
+
+// AddPayload appends one or more payloads to the profile and returns the profile to allow chaining.
+func (p *Profile) AddPayload(payloads ...Payload) *Profile {
+	p.Payloads = append(p.Payloads, payloads...)
+	return p
+}
+
+// PayloadsOfType returns all payloads in the profile with the given PayloadType.
+func (p *Profile) PayloadsOfType(payloadType string) []Payload {
+	var out []Payload
+	for _, payload := range p.Payloads {
+		if payload.Common().PayloadType == payloadType {
+			out = append(out, payload)
+		}
+	}
+	return out
+}
+
+// Parse decodes an XML or binary property list into a Profile.
+func Parse(data []byte) (*Profile, error) {
+	raw, err := decodeDict(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return fromMap(raw)
+}
+
+// ParseString decodes a configuration profile from a string, such as the General.Payloads
+// field returned by the Jamf Pro Classic API.
+func ParseString(data string) (*Profile, error) {
+	return Parse([]byte(data))
+}
+
+// ParseFile reads and decodes a .mobileconfig file from disk.
+func ParseFile(path string) (*Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read configuration profile file %s: %v", path, err)
+	}
+
+	return Parse(data)
+}
+
+// Marshal finalizes the profile (see Finalize) and encodes it as an indented XML property list.
+func (p *Profile) Marshal() ([]byte, error) {
+	if err := p.Finalize(); err != nil {
+		return nil, err
+	}
+
+	raw, err := p.toMap()
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	encoder := plist.NewEncoderForFormat(&buf, plist.XMLFormat)
+	encoder.Indent("\t")
+	if err := encoder.Encode(raw); err != nil {
+		return nil, fmt.Errorf("failed to marshal configuration profile plist: %v", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// MarshalString is a convenience wrapper around Marshal returning a string, suitable for the
+// General.Payloads field of Jamf Pro configuration profiles.
+func (p *Profile) MarshalString() (string, error) {
+	data, err := p.Marshal()
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// WriteFile marshals the profile and writes it to path.
+func (p *Profile) WriteFile(path string) error {
+	data, err := p.Marshal()
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write configuration profile file %s: %v", path, err)
+	}
+	return nil
+}
+
+// decodeDict decodes an XML or binary property list whose root is a dictionary.
+func decodeDict(data []byte) (map[string]interface{}, error) {
+	var raw map[string]interface{}
+	if _, err := plist.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal configuration profile plist: %v", err)
+	}
+
+	return raw, nil
+}
+
+// fromMap builds a Profile from a decoded top level plist dictionary.
+func fromMap(raw map[string]interface{}) (*Profile, error) {
+	var profile Profile
+	if err := decodeInto(raw, &profile); err != nil {
+		return nil, fmt.Errorf("failed to decode configuration profile: %v", err)
+	}
+
+	profile.Extra = unknownKeys(raw, &profile, "PayloadContent")
+
+	if content, ok := raw["PayloadContent"]; ok {
+		items, ok := content.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("configuration profile PayloadContent is a %T, expected an array", content)
+		}
+
+		for i, item := range items {
+			dict, ok := item.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("configuration profile PayloadContent[%d] is a %T, expected a dictionary", i, item)
+			}
+
+			payload, err := decodePayload(dict)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode PayloadContent[%d]: %v", i, err)
+			}
+			profile.Payloads = append(profile.Payloads, payload)
+		}
+	}
+
+	return &profile, nil
+}
+
+// toMap converts the profile into a plist dictionary, merging unknown keys back in.
+func (p *Profile) toMap() (map[string]interface{}, error) {
+	raw, err := encodeToMap(p, p.Extra)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode configuration profile: %v", err)
+	}
+
+	content := make([]interface{}, 0, len(p.Payloads))
+	for i, payload := range p.Payloads {
+		dict, err := encodeToMap(payload, payload.Common().Extra)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode PayloadContent[%d]: %v", i, err)
+		}
+		content = append(content, dict)
+	}
+	raw["PayloadContent"] = content
+
+	return raw, nil
+}
+
+// decodeInto re-encodes a plist dictionary and decodes it into the typed value v, letting the
+// plist package handle type coercion and struct tags.
+func decodeInto(raw map[string]interface{}, v interface{}) error {
+	data, err := plist.Marshal(raw, plist.BinaryFormat)
+	if err != nil {
+		return err
+	}
+
+	_, err = plist.Unmarshal(data, v)
+	return err
+}
+
+// encodeToMap encodes the typed value v into a plist dictionary and adds any extra keys that
+// are not already set by v.
+func encodeToMap(v interface{}, extra map[string]interface{}) (map[string]interface{}, error) {
+	data, err := plist.Marshal(v, plist.BinaryFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := map[string]interface{}{}
+	if _, err := plist.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	for key, value := range extra {
+		if _, exists := raw[key]; !exists {
+			raw[key] = value
+		}
+	}
+
+	return raw, nil
+}
+
+// unknownKeys returns the entries of raw whose keys are not mapped by the plist tags of v, or which v
+// does not emit again, such as an empty string dropped by omitempty, excluding any additionally ignored
+// keys. Returns nil when nothing is left over.
+func unknownKeys(raw map[string]interface{}, v interface{}, ignore ...string) map[string]interface{} {
+	known := plistKeys(v)
+	emitted, err := encodeToMap(v, nil)
+	if err != nil {
+		emitted = nil
+	}
+
+	ignored := map[string]bool{}
+	for _, key := range ignore {
+		ignored[key] = true
+	}
+
+	var extra map[string]interface{}
+	for key, value := range raw {
+		if ignored[key] {
+			continue
+		}
+		if _, ok := emitted[key]; known[key] && (ok || emitted == nil) {
+			continue
+		}
+		if extra == nil {
+			extra = map[string]interface{}{}
+		}
+		extra[key] = value
+	}
+
+	return extra
+}
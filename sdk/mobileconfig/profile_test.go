@@ -0,0 +1,113 @@
+package mobileconfig
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"howett.net/plist"
+)
+
+// decodeRaw decodes a property list into an untyped dictionary.
+func decodeRaw(t *testing.T, data []byte) map[string]interface{} {
+	t.Helper()
+
+	var raw map[string]interface{}
+	if _, err := plist.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("failed to decode plist: %v", err)
+	}
+	return raw
+}
+
+func TestParseMarshalRoundTrip(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "*.mobileconfig"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no testdata profiles found")
+	}
+
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			original, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			profile, err := Parse(original)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+
+			marshaled, err := profile.Marshal()
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+
+			want, got := decodeRaw(t, original), decodeRaw(t, marshaled)
+			if !reflect.DeepEqual(want, got) {
+				diffs, _ := DiffBytes(original, marshaled, &DiffOptions{IgnoredKeys: []string{}})
+				t.Errorf("round trip changed the profile: %v", diffs)
+			}
+		})
+	}
+}
+
+func TestParseKeepsExplicitZeroValues(t *testing.T) {
+	profile, err := ParseFile(filepath.Join("testdata", "notifications.mobileconfig"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payloads := profile.PayloadsOfType(PayloadTypeNotifications)
+	if len(payloads) != 1 {
+		t.Fatalf("got %d notifications payloads, want 1", len(payloads))
+	}
+	setting := payloads[0].(*NotificationsPayload).NotificationSettings[0]
+	if setting.AlertType == nil || *setting.AlertType != NotificationAlertTypeNone {
+		t.Errorf("AlertType = %v, want explicit 0", setting.AlertType)
+	}
+	if setting.Extra["ShowInSummary"] != true {
+		t.Errorf("Extra = %v, want ShowInSummary to be kept", setting.Extra)
+	}
+
+	restrictions := profile.PayloadsOfType(PayloadTypeRestrictions)[0].(*RestrictionsPayload)
+	if restrictions.EnforcedSoftwareUpdateDelay == nil || *restrictions.EnforcedSoftwareUpdateDelay != 0 {
+		t.Errorf("EnforcedSoftwareUpdateDelay = %v, want explicit 0", restrictions.EnforcedSoftwareUpdateDelay)
+	}
+}
+
+func TestParseKeepsNestedUnknownKeys(t *testing.T) {
+	profile, err := ParseFile(filepath.Join("testdata", "pppc.mobileconfig"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pppc := profile.PayloadsOfType(PayloadTypePPPC)[0].(*PPPCPayload)
+	rule := pppc.Services[PPPCServiceSystemPolicyAllFiles][0]
+	if rule.StaticCode == nil || *rule.StaticCode {
+		t.Errorf("StaticCode = %v, want explicit false", rule.StaticCode)
+	}
+	if rule.Extra["LegacyVendorKey"] != "kept" {
+		t.Errorf("Extra = %v, want LegacyVendorKey to be kept", rule.Extra)
+	}
+
+	// Extra set in code is emitted as well.
+	rule.Extra["AddedKey"] = "added"
+	pppc.Services[PPPCServiceSystemPolicyAllFiles][0] = rule
+
+	marshaled, err := profile.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	reparsed, err := Parse(marshaled)
+	if err != nil {
+		t.Fatal(err)
+	}
+	extra := reparsed.PayloadsOfType(PayloadTypePPPC)[0].(*PPPCPayload).Services[PPPCServiceSystemPolicyAllFiles][0].Extra
+	if extra["AddedKey"] != "added" || extra["LegacyVendorKey"] != "kept" {
+		t.Errorf("Extra after round trip = %v", extra)
+	}
+}
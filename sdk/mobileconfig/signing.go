@@ -0,0 +1,238 @@
+// mobileconfig/signing.go
+// CMS (PKCS#7) signing and verification of configuration profiles.
+// reference: https://developer.apple.com/documentation/devicemanagement/configuring_multiple_devices_using_profiles
+// Signed profiles are DER encoded SignedData structures. An enveloped (attached) signature carries the
+// profile inside the signature, a detached signature is stored separately from the profile.
+
+package mobileconfig
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"go.mozilla.org/pkcs7"
+)
+
+// Signer holds the certificate and private key used to sign profiles.
+type Signer struct {
+	Certificate   *x509.Certificate
+	PrivateKey    crypto.PrivateKey
+	Intermediates []*x509.Certificate
+}
+
+// SignerDetails describes the certificate that signed a profile.
+type SignerDetails struct {
+	Subject           string    `json:"subject"`
+	Issuer            string    `json:"issuer"`
+	SerialNumber      string    `json:"serialNumber"`
+	NotBefore         time.Time `json:"notBefore"`
+	NotAfter          time.Time `json:"notAfter"`
+	SHA256Fingerprint string    `json:"sha256Fingerprint"`
+}
+
+// NewSignerFromPEM builds a Signer from a PEM encoded certificate chain and private key. The first
+// certificate is the signing certificate, any further certificates are included as intermediates.
+// PKCS#1, PKCS#8 and SEC 1 (EC) private keys are supported.
+func NewSignerFromPEM(certPEM, keyPEM []byte) (*Signer, error) {
+	var certs []*x509.Certificate
+	for block, rest := pem.Decode(certPEM); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse signing certificate: %v", err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no PEM encoded certificate found")
+	}
+
+	key, err := parsePrivateKeyPEM(keyPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Signer{Certificate: certs[0], PrivateKey: key, Intermediates: certs[1:]}, nil
+}
+
+// NewSignerFromFiles builds a Signer from PEM encoded certificate and private key files.
+func NewSignerFromFiles(certPath, keyPath string) (*Signer, error) {
+	certPEM, err := os.ReadFile(certPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing certificate file %s: %v", certPath, err)
+	}
+
+	keyPEM, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing key file %s: %v", keyPath, err)
+	}
+
+	return NewSignerFromPEM(certPEM, keyPEM)
+}
+
+// Sign returns a DER encoded CMS signature over data. When detached is false the data is
+// enveloped in the signature, otherwise only the signature is returned.
+// The Classic API does not accept signed payloads, so the result cannot be passed to the jamfpro
+// configuration profile Create and Update methods. Keep it as the approved original, verify it with
+// Verify and upload the returned content instead; see
+// recipes/macos_configuration_profiles/CreateMacOSConfigurationProfileFromSignedProfile.
+func (s *Signer) Sign(data []byte, detached bool) ([]byte, error) {
+	if s.Certificate == nil || s.PrivateKey == nil {
+		return nil, fmt.Errorf("signer requires a certificate and a private key")
+	}
+
+	signedData, err := pkcs7.NewSignedData(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialise signed data: %v", err)
+	}
+	signedData.SetDigestAlgorithm(pkcs7.OIDDigestAlgorithmSHA256)
+
+	if err := signedData.AddSignerChain(s.Certificate, s.PrivateKey, s.Intermediates, pkcs7.SignerInfoConfig{}); err != nil {
+		return nil, fmt.Errorf("failed to sign profile: %v", err)
+	}
+
+	if detached {
+		signedData.Detach()
+	}
+
+	signature, err := signedData.Finish()
+	if err != nil {
+		return nil, fmt.Errorf("failed to finalise signed data: %v", err)
+	}
+
+	return signature, nil
+}
+
+// Sign marshals the profile and returns it enveloped in a CMS signature made by signer. The signed
+// profile cannot be uploaded through the Classic API as it is; see Signer.Sign for the supported flow.
+func (p *Profile) Sign(signer *Signer) ([]byte, error) {
+	data, err := p.Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	return signer.Sign(data, false)
+}
+
+// IsSigned reports whether data looks like a DER encoded CMS structure rather than a plist.
+func IsSigned(data []byte) bool {
+	if len(data) < 2 || data[0] != 0x30 {
+		return false
+	}
+
+	p7, err := pkcs7.Parse(data)
+	return err == nil && len(p7.Signers) > 0
+}
+
+// Verify checks an enveloped CMS signature and returns the signed profile content together
+// with the details of the signing certificate. The signer's chain must lead to one of roots, so
+// roots is required; use StripSignature to read a profile without verifying it.
+func Verify(data []byte, roots *x509.CertPool) ([]byte, *SignerDetails, error) {
+	if roots == nil {
+		return nil, nil, fmt.Errorf("verifying a profile signature requires a pool of trusted root certificates")
+	}
+
+	p7, err := pkcs7.Parse(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse signed profile: %v", err)
+	}
+
+	if len(p7.Content) == 0 {
+		return nil, nil, fmt.Errorf("signed profile has no enveloped content, use VerifyDetached")
+	}
+
+	if err := p7.VerifyWithChain(roots); err != nil {
+		return nil, nil, fmt.Errorf("failed to verify profile signature: %v", err)
+	}
+
+	return p7.Content, signerDetails(p7), nil
+}
+
+// VerifyDetached checks a detached CMS signature over content and returns the details of the
+// signing certificate. roots is required, as in Verify.
+func VerifyDetached(signature, content []byte, roots *x509.CertPool) (*SignerDetails, error) {
+	if roots == nil {
+		return nil, fmt.Errorf("verifying a profile signature requires a pool of trusted root certificates")
+	}
+
+	p7, err := pkcs7.Parse(signature)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse profile signature: %v", err)
+	}
+
+	p7.Content = content
+	if err := p7.VerifyWithChain(roots); err != nil {
+		return nil, fmt.Errorf("failed to verify profile signature: %v", err)
+	}
+
+	return signerDetails(p7), nil
+}
+
+// StripSignature returns the profile enveloped in a CMS signature and the details of the
+// certificate it claims to be signed by, without verifying the signature. Data which is not
+// signed is returned unchanged with nil details.
+func StripSignature(data []byte) ([]byte, *SignerDetails, error) {
+	if !IsSigned(data) {
+		return data, nil, nil
+	}
+
+	p7, err := pkcs7.Parse(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse signed profile: %v", err)
+	}
+
+	if len(p7.Content) == 0 {
+		return nil, nil, fmt.Errorf("signed profile has no enveloped content")
+	}
+
+	return p7.Content, signerDetails(p7), nil
+}
+
+// NewSignerDetails summarises a certificate as SignerDetails.
+func NewSignerDetails(cert *x509.Certificate) *SignerDetails {
+	fingerprint := sha256.Sum256(cert.Raw)
+
+	return &SignerDetails{
+		Subject:           cert.Subject.String(),
+		Issuer:            cert.Issuer.String(),
+		SerialNumber:      cert.SerialNumber.String(),
+		NotBefore:         cert.NotBefore,
+		NotAfter:          cert.NotAfter,
+		SHA256Fingerprint: strings.ToUpper(hex.EncodeToString(fingerprint[:])),
+	}
+}
+
+// signerDetails returns the details of the signing certificate of p7, if present.
+func signerDetails(p7 *pkcs7.PKCS7) *SignerDetails {
+	cert := p7.GetOnlySigner()
+	if cert == nil {
+		return nil
+	}
+
+	return NewSignerDetails(cert)
+}
+
+// parsePrivateKeyPEM decodes the first private key found in keyPEM.
+func parsePrivateKeyPEM(keyPEM []byte) (crypto.PrivateKey, error) {
+	for block, rest := pem.Decode(keyPEM); block != nil; block, rest = pem.Decode(rest) {
+		switch block.Type {
+		case "RSA PRIVATE KEY":
+			return x509.ParsePKCS1PrivateKey(block.Bytes)
+		case "EC PRIVATE KEY":
+			return x509.ParseECPrivateKey(block.Bytes)
+		case "PRIVATE KEY":
+			return x509.ParsePKCS8PrivateKey(block.Bytes)
+		}
+	}
+
+	return nil, fmt.Errorf("no PEM encoded private key found")
+}
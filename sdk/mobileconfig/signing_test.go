@@ -0,0 +1,205 @@
+package mobileconfig
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testPKI is a throwaway CA and a leaf certificate issued by it for signing.
+type testPKI struct {
+	roots  *x509.CertPool
+	signer *Signer
+}
+
+// newTestPKI generates a CA and a signing certificate valid for the next hour.
+func newTestPKI(t *testing.T) *testPKI {
+	t.Helper()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test Profile Signing CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leafTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "Test Profile Signer"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader, leafTemplate, ca, &leafKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(leafKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := NewSignerFromPEM(
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leafDER}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}),
+	)
+	if err != nil {
+		t.Fatalf("NewSignerFromPEM: %v", err)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+
+	return &testPKI{roots: roots, signer: signer}
+}
+
+// testSigningProfile returns a marshaled profile to sign.
+func testSigningProfile(t *testing.T) []byte {
+	t.Helper()
+
+	data, err := New("com.example.signed", "Signed").AddPayload(NewWiFiPayload("Corp", WiFiEncryptionWPA2)).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestSignAndVerifyAttached(t *testing.T) {
+	pki := newTestPKI(t)
+	content := testSigningProfile(t)
+
+	signed, err := pki.signer.Sign(content, false)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	if !IsSigned(signed) {
+		t.Fatal("IsSigned = false for a signed profile")
+	}
+	if IsSigned(content) {
+		t.Fatal("IsSigned = true for an unsigned profile")
+	}
+
+	verified, details, err := Verify(signed, pki.roots)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if !bytes.Equal(verified, content) {
+		t.Error("Verify returned different content")
+	}
+	if details == nil || !strings.Contains(details.Subject, "Test Profile Signer") {
+		t.Errorf("signer details = %+v", details)
+	}
+}
+
+func TestVerifyRejects(t *testing.T) {
+	pki := newTestPKI(t)
+	content := testSigningProfile(t)
+
+	signed, err := pki.signer.Sign(content, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tampered := bytes.Replace(signed, []byte("Corp"), []byte("Evil"), 1)
+	if bytes.Equal(tampered, signed) {
+		t.Fatal("content not found in signed data")
+	}
+
+	tests := []struct {
+		name  string
+		data  []byte
+		roots *x509.CertPool
+	}{
+		{name: "tampered content", data: tampered, roots: pki.roots},
+		{name: "untrusted root", data: signed, roots: newTestPKI(t).roots},
+		{name: "no roots", data: signed, roots: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := Verify(tt.data, tt.roots); err == nil {
+				t.Error("Verify succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestSignAndVerifyDetached(t *testing.T) {
+	pki := newTestPKI(t)
+	content := testSigningProfile(t)
+
+	signature, err := pki.signer.Sign(content, true)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	if bytes.Contains(signature, []byte("Corp")) {
+		t.Error("detached signature contains the profile")
+	}
+
+	if _, err := VerifyDetached(signature, content, pki.roots); err != nil {
+		t.Errorf("VerifyDetached: %v", err)
+	}
+	if _, err := VerifyDetached(signature, bytes.Replace(content, []byte("Corp"), []byte("Evil"), 1), pki.roots); err == nil {
+		t.Error("VerifyDetached succeeded for tampered content")
+	}
+	if _, err := VerifyDetached(signature, content, newTestPKI(t).roots); err == nil {
+		t.Error("VerifyDetached succeeded for an untrusted root")
+	}
+	if _, _, err := Verify(signature, pki.roots); err == nil {
+		t.Error("Verify succeeded for a detached signature")
+	}
+}
+
+func TestStripSignature(t *testing.T) {
+	pki := newTestPKI(t)
+	content := testSigningProfile(t)
+
+	signed, err := pki.signer.Sign(content, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stripped, details, err := StripSignature(signed)
+	if err != nil {
+		t.Fatalf("StripSignature: %v", err)
+	}
+	if !bytes.Equal(stripped, content) {
+		t.Error("StripSignature returned different content")
+	}
+	if details == nil || details.SHA256Fingerprint == "" {
+		t.Errorf("signer details = %+v", details)
+	}
+
+	unsigned, details, err := StripSignature(content)
+	if err != nil || !bytes.Equal(unsigned, content) || details != nil {
+		t.Errorf("StripSignature of unsigned data = %q, %v, %v", unsigned, details, err)
+	}
+}
@@ -0,0 +1,198 @@
+// webhooks/events.go
+// Typed Jamf Pro webhook events.
+// reference: https://developer.jamf.com/developer-guide/docs/webhooks
+// Every delivery carries a "webhook" object describing the webhook that fired and an "event" object
+// whose shape depends on the event type. Field names are identical in JSON and XML deliveries.
+
+package webhooks
+
+// Webhook event types, as configured on a Jamf Pro webhook and reported in Webhook.WebhookEvent.
+const (
+	EventComputerAdded                          = "ComputerAdded"
+	EventComputerCheckIn                        = "ComputerCheckIn"
+	EventComputerInventoryCompleted             = "ComputerInventoryCompleted"
+	EventComputerPatchPolicyCompleted           = "ComputerPatchPolicyCompleted"
+	EventComputerPolicyFinished                 = "ComputerPolicyFinished"
+	EventComputerPushCapabilityChanged          = "ComputerPushCapabilityChanged"
+	EventDeviceAddedToDEP                       = "DeviceAddedToDEP"
+	EventJSSShutdown                            = "JSSShutdown"
+	EventJSSStartup                             = "JSSStartup"
+	EventMobileDeviceCheckIn                    = "MobileDeviceCheckIn"
+	EventMobileDeviceCommandCompleted           = "MobileDeviceCommandCompleted"
+	EventMobileDeviceEnrolled                   = "MobileDeviceEnrolled"
+	EventMobileDeviceInventoryCompleted         = "MobileDeviceInventoryCompleted"
+	EventMobileDevicePushSent                   = "MobileDevicePushSent"
+	EventMobileDeviceUnEnrolled                 = "MobileDeviceUnEnrolled"
+	EventPatchSoftwareTitleUpdated              = "PatchSoftwareTitleUpdated"
+	EventPushSent                               = "PushSent"
+	EventRestAPIOperation                       = "RestAPIOperation"
+	EventSmartGroupComputerMembershipChange     = "SmartGroupComputerMembershipChange"
+	EventSmartGroupMobileDeviceMembershipChange = "SmartGroupMobileDeviceMembershipChange"
+	EventSmartGroupUserMembershipChange         = "SmartGroupUserMembershipChange"
+)
+
+// eventPayloads maps each event type to a constructor for its typed payload.
+var eventPayloads = map[string]func() interface{}{
+	EventComputerAdded:                          func() interface{} { return &Computer{} },
+	EventComputerCheckIn:                        func() interface{} { return &ComputerCheckInEvent{} },
+	EventComputerInventoryCompleted:             func() interface{} { return &Computer{} },
+	EventComputerPatchPolicyCompleted:           func() interface{} { return &ComputerPatchPolicyCompletedEvent{} },
+	EventComputerPolicyFinished:                 func() interface{} { return &ComputerPolicyFinishedEvent{} },
+	EventComputerPushCapabilityChanged:          func() interface{} { return &Computer{} },
+	EventDeviceAddedToDEP:                       func() interface{} { return &DeviceAddedToDEPEvent{} },
+	EventJSSShutdown:                            func() interface{} { return &JSSStatusEvent{} },
+	EventJSSStartup:                             func() interface{} { return &JSSStatusEvent{} },
+	EventMobileDeviceCheckIn:                    func() interface{} { return &MobileDevice{} },
+	EventMobileDeviceCommandCompleted:           func() interface{} { return &MobileDevice{} },
+	EventMobileDeviceEnrolled:                   func() interface{} { return &MobileDevice{} },
+	EventMobileDeviceInventoryCompleted:         func() interface{} { return &MobileDevice{} },
+	EventMobileDevicePushSent:                   func() interface{} { return &MobileDevice{} },
+	EventMobileDeviceUnEnrolled:                 func() interface{} { return &MobileDevice{} },
+	EventPatchSoftwareTitleUpdated:              func() interface{} { return &PatchSoftwareTitleUpdatedEvent{} },
+	EventPushSent:                               func() interface{} { return &PushSentEvent{} },
+	EventRestAPIOperation:                       func() interface{} { return &RestAPIOperationEvent{} },
+	EventSmartGroupComputerMembershipChange:     func() interface{} { return &SmartGroupMembershipChangeEvent{} },
+	EventSmartGroupMobileDeviceMembershipChange: func() interface{} { return &SmartGroupMembershipChangeEvent{} },
+	EventSmartGroupUserMembershipChange:         func() interface{} { return &SmartGroupMembershipChangeEvent{} },
+}
+
+// EventTypes returns every event type with a typed payload.
+func EventTypes() []string {
+	types := make([]string, 0, len(eventPayloads))
+	for eventType := range eventPayloads {
+		types = append(types, eventType)
+	}
+	return types
+}
+
+// Webhook describes the Jamf Pro webhook which produced a delivery.
+type Webhook struct {
+	ID             int    `json:"id" xml:"id"`
+	Name           string `json:"name" xml:"name"`
+	WebhookEvent   string `json:"webhookEvent" xml:"webhookEvent"`
+	EventTimestamp int64  `json:"eventTimestamp" xml:"eventTimestamp"`
+}
+
+// Computer is the payload of ComputerAdded, ComputerInventoryCompleted and ComputerPushCapabilityChanged,
+// and is embedded in the other computer events.
+type Computer struct {
+	UDID                string `json:"udid" xml:"udid"`
+	DeviceName          string `json:"deviceName" xml:"deviceName"`
+	Model               string `json:"model" xml:"model"`
+	MacAddress          string `json:"macAddress" xml:"macAddress"`
+	AlternateMacAddress string `json:"alternateMacAddress" xml:"alternateMacAddress"`
+	SerialNumber        string `json:"serialNumber" xml:"serialNumber"`
+	OSVersion           string `json:"osVersion" xml:"osVersion"`
+	OSBuild             string `json:"osBuild" xml:"osBuild"`
+	UserDirectoryID     string `json:"userDirectoryID" xml:"userDirectoryID"`
+	Username            string `json:"username" xml:"username"`
+	RealName            string `json:"realName" xml:"realName"`
+	EmailAddress        string `json:"emailAddress" xml:"emailAddress"`
+	Phone               string `json:"phone" xml:"phone"`
+	Position            string `json:"position" xml:"position"`
+	Department          string `json:"department" xml:"department"`
+	Building            string `json:"building" xml:"building"`
+	Room                string `json:"room" xml:"room"`
+	JSSID               int    `json:"jssID" xml:"jssID"`
+}
+
+// ComputerCheckInEvent is the payload of ComputerCheckIn.
+type ComputerCheckInEvent struct {
+	Computer Computer `json:"computer" xml:"computer"`
+	Trigger  string   `json:"trigger" xml:"trigger"`
+	Username string   `json:"username" xml:"username"`
+}
+
+// ComputerPolicyFinishedEvent is the payload of ComputerPolicyFinished.
+type ComputerPolicyFinishedEvent struct {
+	Computer   Computer `json:"computer" xml:"computer"`
+	PolicyID   int      `json:"policyId" xml:"policyId"`
+	Successful bool     `json:"successful" xml:"successful"`
+}
+
+// ComputerPatchPolicyCompletedEvent is the payload of ComputerPatchPolicyCompleted.
+type ComputerPatchPolicyCompletedEvent struct {
+	Computer        Computer `json:"computer" xml:"computer"`
+	PatchPolicyID   int      `json:"patchPolicyId" xml:"patchPolicyId"`
+	PatchPolicyName string   `json:"patchPolicyName" xml:"patchPolicyName"`
+	SoftwareTitleID int      `json:"softwareTitleId" xml:"softwareTitleId"`
+	DeployedVersion string   `json:"deployedVersion" xml:"deployedVersion"`
+	Successful      bool     `json:"successful" xml:"successful"`
+}
+
+// MobileDevice is the payload of the mobile device events.
+type MobileDevice struct {
+	UDID                string `json:"udid" xml:"udid"`
+	DeviceName          string `json:"deviceName" xml:"deviceName"`
+	Version             string `json:"version" xml:"version"`
+	Model               string `json:"model" xml:"model"`
+	ModelDisplay        string `json:"modelDisplay" xml:"modelDisplay"`
+	Product             string `json:"product" xml:"product"`
+	BluetoothMacAddress string `json:"bluetoothMacAddress" xml:"bluetoothMacAddress"`
+	WifiMacAddress      string `json:"wifiMacAddress" xml:"wifiMacAddress"`
+	IMEI                string `json:"imei" xml:"imei"`
+	ICCID               string `json:"icciID" xml:"icciID"`
+	SerialNumber        string `json:"serialNumber" xml:"serialNumber"`
+	OSVersion           string `json:"osVersion" xml:"osVersion"`
+	OSBuild             string `json:"osBuild" xml:"osBuild"`
+	UserDirectoryID     string `json:"userDirectoryID" xml:"userDirectoryID"`
+	Username            string `json:"username" xml:"username"`
+	Room                string `json:"room" xml:"room"`
+	JSSID               int    `json:"jssID" xml:"jssID"`
+}
+
+// DeviceAddedToDEPEvent is the payload of DeviceAddedToDEP.
+type DeviceAddedToDEPEvent struct {
+	AssetTag                          string `json:"assetTag" xml:"assetTag"`
+	Color                             string `json:"color" xml:"color"`
+	Description                       string `json:"description" xml:"description"`
+	DeviceAssignedDate                string `json:"deviceAssignedDate" xml:"deviceAssignedDate"`
+	DeviceEnrollmentProgramInstanceID int    `json:"deviceEnrollmentProgramInstanceId" xml:"deviceEnrollmentProgramInstanceId"`
+	DeviceFamily                      string `json:"deviceFamily" xml:"deviceFamily"`
+	Model                             string `json:"model" xml:"model"`
+	OS                                string `json:"os" xml:"os"`
+	ProfileStatus                     string `json:"profileStatus" xml:"profileStatus"`
+	SerialNumber                      string `json:"serialNumber" xml:"serialNumber"`
+}
+
+// SmartGroupMembershipChangeEvent is the payload of the smart group membership change events.
+type SmartGroupMembershipChangeEvent struct {
+	Name                   string `json:"name" xml:"name"`
+	SmartGroup             bool   `json:"smartGroup" xml:"smartGroup"`
+	JSSID                  int    `json:"jssid" xml:"jssid"`
+	GroupAddedDevicesIDs   []int  `json:"groupAddedDevicesIds" xml:"groupAddedDevicesIds"`
+	GroupRemovedDevicesIDs []int  `json:"groupRemovedDevicesIds" xml:"groupRemovedDevicesIds"`
+}
+
+// RestAPIOperationEvent is the payload of RestAPIOperation.
+type RestAPIOperationEvent struct {
+	AuthorizedUsername   string `json:"authorizedUsername" xml:"authorizedUsername"`
+	ObjectID             int    `json:"objectID" xml:"objectID"`
+	ObjectName           string `json:"objectName" xml:"objectName"`
+	ObjectTypeName       string `json:"objectTypeName" xml:"objectTypeName"`
+	OperationSuccessful  bool   `json:"operationSuccessful" xml:"operationSuccessful"`
+	RestAPIOperationType string `json:"restAPIOperationType" xml:"restAPIOperationType"`
+}
+
+// JSSStatusEvent is the payload of JSSStartup and JSSShutdown.
+type JSSStatusEvent struct {
+	HostAddress        string `json:"hostAddress" xml:"hostAddress"`
+	Institution        string `json:"institution" xml:"institution"`
+	IsClusterMaster    bool   `json:"isClusterMaster" xml:"isClusterMaster"`
+	JSSUrl             string `json:"jssUrl" xml:"jssUrl"`
+	WebApplicationPath string `json:"webApplicationPath" xml:"webApplicationPath"`
+}
+
+// PatchSoftwareTitleUpdatedEvent is the payload of PatchSoftwareTitleUpdated.
+type PatchSoftwareTitleUpdatedEvent struct {
+	JSSID         int    `json:"jssID" xml:"jssID"`
+	Name          string `json:"name" xml:"name"`
+	LatestVersion string `json:"latestVersion" xml:"latestVersion"`
+	LastUpdate    int64  `json:"lastUpdate" xml:"lastUpdate"`
+	ReportURL     string `json:"reportUrl" xml:"reportUrl"`
+}
+
+// PushSentEvent is the payload of PushSent.
+type PushSentEvent struct {
+	Type string `json:"type" xml:"type"`
+}
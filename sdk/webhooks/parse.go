@@ -0,0 +1,170 @@
+// webhooks/parse.go
+// Decoding of JSON and XML webhook deliveries into typed events.
+
+package webhooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Content types used by Jamf Pro webhook deliveries
+const (
+	ContentTypeJSON = "application/json"
+	ContentTypeXML  = "text/xml"
+)
+
+// Event is a decoded webhook delivery.
+type Event struct {
+	Webhook Webhook
+	// Payload holds a pointer to the typed payload of the event, e.g. *Computer for ComputerAdded or
+	// *SmartGroupMembershipChangeEvent for SmartGroupComputerMembershipChange. Events without a typed
+	// payload hold a map[string]interface{} for JSON deliveries and nil for XML deliveries.
+	Payload interface{}
+	// ContentType is ContentTypeJSON or ContentTypeXML for deliveries, empty for synthesised events.
+	ContentType string
+	// Body is the raw delivery, empty for synthesised events.
+	Body []byte
+}
+
+// NewEvent returns an event for payload as if it had been delivered by a webhook. It is used to feed
+// events from other sources, such as polling, through the same handlers as webhook deliveries.
+func NewEvent(eventType string, payload interface{}, timestamp time.Time) *Event {
+	return &Event{
+		Webhook: Webhook{WebhookEvent: eventType, EventTimestamp: timestamp.UnixMilli()},
+		Payload: payload,
+	}
+}
+
+// Type returns the event type, e.g. EventComputerAdded.
+func (e *Event) Type() string {
+	return e.Webhook.WebhookEvent
+}
+
+// Time returns when Jamf Pro raised the event.
+func (e *Event) Time() time.Time {
+	return time.UnixMilli(e.Webhook.EventTimestamp)
+}
+
+// jsonEnvelope is the outer structure of a JSON delivery.
+type jsonEnvelope struct {
+	Webhook Webhook         `json:"webhook"`
+	Event   json.RawMessage `json:"event"`
+}
+
+// xmlEnvelope is the outer structure of an XML delivery. The root element name is not checked.
+type xmlEnvelope struct {
+	Webhook Webhook `xml:"webhook"`
+	Event   struct {
+		Inner []byte `xml:",innerxml"`
+	} `xml:"event"`
+}
+
+// Parse decodes a webhook delivery. contentType is the Content-Type header of the delivery; when it is
+// neither JSON nor XML the format is detected from the body.
+func Parse(body []byte, contentType string) (*Event, error) {
+	switch detectFormat(body, contentType) {
+	case ContentTypeJSON:
+		return parseJSON(body)
+	case ContentTypeXML:
+		return parseXML(body)
+	default:
+		return nil, fmt.Errorf("unsupported webhook content type: %q", contentType)
+	}
+}
+
+// parseJSON decodes a JSON delivery.
+func parseJSON(body []byte) (*Event, error) {
+	var envelope jsonEnvelope
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON webhook delivery: %v", err)
+	}
+
+	event, err := newDeliveredEvent(envelope.Webhook, ContentTypeJSON, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(envelope.Event) == 0 {
+		return event, nil
+	}
+
+	if event.Payload == nil {
+		var generic map[string]interface{}
+		if err := json.Unmarshal(envelope.Event, &generic); err != nil {
+			return nil, fmt.Errorf("failed to decode %s event: %v", event.Type(), err)
+		}
+		event.Payload = generic
+		return event, nil
+	}
+
+	if err := json.Unmarshal(envelope.Event, event.Payload); err != nil {
+		return nil, fmt.Errorf("failed to decode %s event: %v", event.Type(), err)
+	}
+
+	return event, nil
+}
+
+// parseXML decodes an XML delivery.
+func parseXML(body []byte) (*Event, error) {
+	var envelope xmlEnvelope
+	if err := xml.Unmarshal(body, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to decode XML webhook delivery: %v", err)
+	}
+
+	event, err := newDeliveredEvent(envelope.Webhook, ContentTypeXML, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if event.Payload == nil {
+		return event, nil
+	}
+
+	eventXML := append(append([]byte("<event>"), envelope.Event.Inner...), []byte("</event>")...)
+	if err := xml.Unmarshal(eventXML, event.Payload); err != nil {
+		return nil, fmt.Errorf("failed to decode %s event: %v", event.Type(), err)
+	}
+
+	return event, nil
+}
+
+// newDeliveredEvent returns an event for webhook with an empty typed payload, if the event type has one.
+func newDeliveredEvent(webhook Webhook, contentType string, body []byte) (*Event, error) {
+	if webhook.WebhookEvent == "" {
+		return nil, fmt.Errorf("webhook delivery has no webhookEvent")
+	}
+
+	event := &Event{Webhook: webhook, ContentType: contentType, Body: body}
+	if newPayload, ok := eventPayloads[webhook.WebhookEvent]; ok {
+		event.Payload = newPayload()
+	}
+
+	return event, nil
+}
+
+// detectFormat returns ContentTypeJSON or ContentTypeXML for a delivery, or an empty string when the
+// format cannot be determined.
+func detectFormat(body []byte, contentType string) string {
+	contentType = strings.ToLower(contentType)
+	switch {
+	case strings.Contains(contentType, "json"):
+		return ContentTypeJSON
+	case strings.Contains(contentType, "xml"):
+		return ContentTypeXML
+	}
+
+	trimmed := bytes.TrimSpace(body)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		return ContentTypeJSON
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		return ContentTypeXML
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,180 @@
+package webhooks
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fixtureComputer is the computer of the computer fixtures; the policy fixtures only carry some fields.
+var fixtureComputer = Computer{
+	UDID:            "6A2A7B9C-1F0D-4B53-9A2E-6C1D2E3F4A5B",
+	DeviceName:      "LAB-MAC-001",
+	Model:           "MacBook Pro (14-inch, 2023)",
+	MacAddress:      "AA:BB:CC:DD:EE:01",
+	SerialNumber:    "C02ABC123XYZ",
+	OSVersion:       "14.5",
+	OSBuild:         "23F79",
+	UserDirectoryID: "-1",
+	Username:        "jappleseed",
+	RealName:        "Johnny Appleseed",
+	EmailAddress:    "jappleseed@example.com",
+	Position:        "Engineer",
+	Department:      "IT",
+	Building:        "HQ",
+	Room:            "101",
+	JSSID:           42,
+}
+
+// fixturePayloads are the typed payloads expected from the fixtures in testdata, by event type.
+var fixturePayloads = map[string]interface{}{
+	EventComputerAdded: &fixtureComputer,
+	EventComputerPolicyFinished: &ComputerPolicyFinishedEvent{
+		Computer: Computer{
+			UDID:         fixtureComputer.UDID,
+			DeviceName:   fixtureComputer.DeviceName,
+			SerialNumber: fixtureComputer.SerialNumber,
+			JSSID:        fixtureComputer.JSSID,
+		},
+		PolicyID:   17,
+		Successful: true,
+	},
+	EventJSSStartup: &JSSStatusEvent{
+		HostAddress:        "10.0.0.10",
+		Institution:        "Example Org",
+		IsClusterMaster:    true,
+		JSSUrl:             "https://example.jamfcloud.com/",
+		WebApplicationPath: "/usr/local/jss/tomcat/webapps/ROOT",
+	},
+	EventMobileDeviceEnrolled: &MobileDevice{
+		UDID:         "00008030-001A2B3C4D5E6F7A",
+		DeviceName:   "Classroom iPad 03",
+		Version:      "17.5",
+		Model:        "iPad (10th generation)",
+		SerialNumber: "DMPXYZ123456",
+		OSVersion:    "17.5",
+		OSBuild:      "21F79",
+		Username:     "student03",
+		JSSID:        103,
+	},
+	EventRestAPIOperation: &RestAPIOperationEvent{
+		AuthorizedUsername:   "automation",
+		ObjectID:             12,
+		ObjectName:           "Install Rosetta",
+		ObjectTypeName:       "Policy",
+		OperationSuccessful:  true,
+		RestAPIOperationType: "PUT",
+	},
+	EventSmartGroupComputerMembershipChange: &SmartGroupMembershipChangeEvent{
+		Name:                   "FileVault Not Enabled",
+		SmartGroup:             true,
+		JSSID:                  9,
+		GroupAddedDevicesIDs:   []int{42, 43},
+		GroupRemovedDevicesIDs: []int{7},
+	},
+}
+
+func TestParseFixtures(t *testing.T) {
+	fixtures, err := filepath.Glob(filepath.Join("testdata", "*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	formats := map[string]bool{}
+	for _, fixture := range fixtures {
+		t.Run(filepath.Base(fixture), func(t *testing.T) {
+			body, err := os.ReadFile(fixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			contentType := ContentTypeJSON
+			if strings.HasSuffix(fixture, ".xml") {
+				contentType = ContentTypeXML
+			}
+			formats[contentType] = true
+
+			event, err := Parse(body, contentType)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+
+			eventType := strings.TrimSuffix(filepath.Base(fixture), filepath.Ext(fixture))
+			if event.Type() != eventType {
+				t.Errorf("Type() = %q, want %q", event.Type(), eventType)
+			}
+			if event.ContentType != contentType {
+				t.Errorf("ContentType = %q, want %q", event.ContentType, contentType)
+			}
+			if event.Time().Before(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+				t.Errorf("Time() = %v, want the fixture timestamp", event.Time())
+			}
+
+			want, ok := fixturePayloads[eventType]
+			if !ok {
+				t.Fatalf("no expected payload for %s", eventType)
+			}
+			if !reflect.DeepEqual(event.Payload, want) {
+				t.Errorf("Payload = %+v, want %+v", event.Payload, want)
+			}
+		})
+	}
+
+	if !formats[ContentTypeJSON] || !formats[ContentTypeXML] {
+		t.Errorf("fixtures cover %v, want both JSON and XML", formats)
+	}
+}
+
+func TestParseDetectsFormatFromBody(t *testing.T) {
+	body, err := os.ReadFile(filepath.Join("testdata", "JSSStartup.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event, err := Parse(body, "")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if event.ContentType != ContentTypeXML {
+		t.Errorf("ContentType = %q, want %q", event.ContentType, ContentTypeXML)
+	}
+}
+
+func TestParseUnknownEvent(t *testing.T) {
+	body := []byte(`{"webhook":{"id":9,"webhookEvent":"SomethingNew","eventTimestamp":1717000000000},"event":{"answer":42}}`)
+
+	event, err := Parse(body, ContentTypeJSON)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	payload, ok := event.Payload.(map[string]interface{})
+	if !ok || payload["answer"] != float64(42) {
+		t.Errorf("Payload = %#v, want the generic event map", event.Payload)
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		contentType string
+	}{
+		{name: "malformed json", body: `{"webhook":`, contentType: ContentTypeJSON},
+		{name: "malformed xml", body: `<JSSEvent><webhook>`, contentType: ContentTypeXML},
+		{name: "no event type", body: `{"webhook":{"id":1},"event":{}}`, contentType: ContentTypeJSON},
+		{name: "wrong payload shape", body: `{"webhook":{"webhookEvent":"ComputerAdded"},"event":{"jssID":"not a number"}}`, contentType: ContentTypeJSON},
+		{name: "unknown format", body: `webhookEvent=ComputerAdded`, contentType: "text/plain"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse([]byte(tt.body), tt.contentType); err == nil {
+				t.Error("Parse succeeded, want an error")
+			}
+		})
+	}
+}
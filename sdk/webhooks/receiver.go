@@ -0,0 +1,169 @@
+// webhooks/receiver.go
+// http.Handler which authenticates Jamf Pro webhook deliveries and dispatches them to handlers.
+// Jamf Pro treats any 2xx response as a successful delivery. The receiver responds with 401 when
+// authentication fails, 415 when the Content-Type is neither JSON nor XML, 413 when the delivery is
+// larger than MaxBodyBytes, 400 when it cannot be decoded and 500 when a handler returns an error.
+
+package webhooks
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// DefaultMaxBodyBytes is the largest delivery accepted when ReceiverOptions.MaxBodyBytes is not set.
+const DefaultMaxBodyBytes = 1 << 20
+
+// HandlerFunc handles a single event. Returning an error makes the receiver respond with a 500 status.
+type HandlerFunc func(ctx context.Context, event *Event) error
+
+// ReceiverOptions configures the authentication expected on deliveries. When Username is set deliveries
+// must use basic authentication with Username and Password. When HeaderName is set deliveries must carry
+// the header with HeaderValue, e.g. HeaderName "Authorization" and HeaderValue "Bearer <token>". Both may
+// be set, in which case both are required. With neither set deliveries are not authenticated.
+type ReceiverOptions struct {
+	Username     string
+	Password     string
+	HeaderName   string
+	HeaderValue  string
+	MaxBodyBytes int64
+	// ErrorLog, when set, is called with errors that result in a non 2xx response.
+	ErrorLog func(err error)
+}
+
+// Receiver is an http.Handler for Jamf Pro webhook deliveries.
+type Receiver struct {
+	options  ReceiverOptions
+	mu       sync.RWMutex
+	handlers map[string][]HandlerFunc
+	fallback []HandlerFunc
+}
+
+// ErrUnauthorized is reported to ErrorLog when a delivery fails authentication.
+var ErrUnauthorized = errors.New("webhook delivery failed authentication")
+
+// NewReceiver returns a Receiver which validates deliveries using options.
+func NewReceiver(options ReceiverOptions) *Receiver {
+	if options.MaxBodyBytes <= 0 {
+		options.MaxBodyBytes = DefaultMaxBodyBytes
+	}
+
+	return &Receiver{options: options, handlers: map[string][]HandlerFunc{}}
+}
+
+// Handle registers handler for an event type, e.g. EventComputerAdded. Handlers for the same type run
+// in registration order.
+func (r *Receiver) Handle(eventType string, handler HandlerFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers[eventType] = append(r.handlers[eventType], handler)
+}
+
+// HandleAll registers handler for every event type. It runs after the handlers registered with Handle.
+func (r *Receiver) HandleAll(handler HandlerFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fallback = append(r.fallback, handler)
+}
+
+// Dispatch runs the handlers registered for the event. It stops at, and returns, the first error.
+func (r *Receiver) Dispatch(ctx context.Context, event *Event) error {
+	r.mu.RLock()
+	handlers := append(append([]HandlerFunc{}, r.handlers[event.Type()]...), r.fallback...)
+	r.mu.RUnlock()
+
+	for _, handler := range handlers {
+		if err := handler(ctx, event); err != nil {
+			return fmt.Errorf("failed to handle %s event: %v", event.Type(), err)
+		}
+	}
+
+	return nil
+}
+
+// ServeHTTP authenticates, decodes and dispatches a single delivery.
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !r.authenticate(req) {
+		r.logError(ErrUnauthorized)
+		if r.options.Username != "" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="jamf-webhooks"`)
+		}
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	// A delivery without a Content-Type is detected from its body, see Parse
+	contentType := req.Header.Get("Content-Type")
+	if contentType != "" && detectFormat(nil, contentType) == "" {
+		r.logError(fmt.Errorf("unsupported webhook content type: %q", contentType))
+		http.Error(w, http.StatusText(http.StatusUnsupportedMediaType), http.StatusUnsupportedMediaType)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, req.Body, r.options.MaxBodyBytes))
+	if err != nil {
+		r.logError(fmt.Errorf("failed to read webhook delivery: %v", err))
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	event, err := Parse(body, contentType)
+	if err != nil {
+		r.logError(err)
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	if err := r.Dispatch(req.Context(), event); err != nil {
+		r.logError(err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// authenticate reports whether req carries the configured credentials.
+func (r *Receiver) authenticate(req *http.Request) bool {
+	if r.options.Username != "" {
+		username, password, ok := req.BasicAuth()
+		if !ok || !secureEqual(username, r.options.Username) || !secureEqual(password, r.options.Password) {
+			return false
+		}
+	}
+
+	if r.options.HeaderName != "" {
+		if !secureEqual(req.Header.Get(r.options.HeaderName), r.options.HeaderValue) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (r *Receiver) logError(err error) {
+	if r.options.ErrorLog != nil {
+		r.options.ErrorLog(err)
+	}
+}
+
+// secureEqual compares two strings in constant time.
+func secureEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
@@ -0,0 +1,178 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// deliver posts body to receiver and returns the response status code.
+func deliver(t *testing.T, receiver *Receiver, body, contentType string, setAuth func(*http.Request)) int {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if setAuth != nil {
+		setAuth(req)
+	}
+
+	rec := httptest.NewRecorder()
+	receiver.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func readFixture(t *testing.T, name string) string {
+	t.Helper()
+
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
+
+func TestReceiverAuthentication(t *testing.T) {
+	body := readFixture(t, "ComputerAdded.json")
+	basic := func(username, password string) func(*http.Request) {
+		return func(req *http.Request) { req.SetBasicAuth(username, password) }
+	}
+	header := func(value string) func(*http.Request) {
+		return func(req *http.Request) { req.Header.Set("Authorization", value) }
+	}
+
+	tests := []struct {
+		name    string
+		options ReceiverOptions
+		auth    func(*http.Request)
+		want    int
+	}{
+		{name: "no authentication configured", want: http.StatusOK},
+		{name: "basic accepted", options: ReceiverOptions{Username: "jamf", Password: "secret"}, auth: basic("jamf", "secret"), want: http.StatusOK},
+		{name: "basic wrong password", options: ReceiverOptions{Username: "jamf", Password: "secret"}, auth: basic("jamf", "wrong"), want: http.StatusUnauthorized},
+		{name: "basic missing", options: ReceiverOptions{Username: "jamf", Password: "secret"}, want: http.StatusUnauthorized},
+		{name: "header accepted", options: ReceiverOptions{HeaderName: "Authorization", HeaderValue: "Bearer token"}, auth: header("Bearer token"), want: http.StatusOK},
+		{name: "header wrong value", options: ReceiverOptions{HeaderName: "Authorization", HeaderValue: "Bearer token"}, auth: header("Bearer other"), want: http.StatusUnauthorized},
+		{name: "header missing", options: ReceiverOptions{HeaderName: "Authorization", HeaderValue: "Bearer token"}, want: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logged error
+			tt.options.ErrorLog = func(err error) { logged = err }
+
+			handled := false
+			receiver := NewReceiver(tt.options)
+			receiver.HandleAll(func(ctx context.Context, event *Event) error {
+				handled = true
+				return nil
+			})
+
+			if got := deliver(t, receiver, body, ContentTypeJSON, tt.auth); got != tt.want {
+				t.Fatalf("status = %d, want %d", got, tt.want)
+			}
+			if tt.want == http.StatusUnauthorized {
+				if handled {
+					t.Error("handler ran for an unauthenticated delivery")
+				}
+				if !errors.Is(logged, ErrUnauthorized) {
+					t.Errorf("logged %v, want ErrUnauthorized", logged)
+				}
+			} else if !handled {
+				t.Error("handler did not run")
+			}
+		})
+	}
+}
+
+func TestReceiverRejectsDeliveries(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		body        string
+		contentType string
+		maxBytes    int64
+		want        int
+	}{
+		{name: "wrong method", method: http.MethodGet, want: http.StatusMethodNotAllowed},
+		{name: "wrong content type", body: readFixture(t, "ComputerAdded.json"), contentType: "application/x-www-form-urlencoded", want: http.StatusUnsupportedMediaType},
+		{name: "body too large", body: readFixture(t, "ComputerAdded.json"), contentType: ContentTypeJSON, maxBytes: 64, want: http.StatusRequestEntityTooLarge},
+		{name: "malformed body", body: `{"webhook":`, contentType: ContentTypeJSON, want: http.StatusBadRequest},
+		{name: "content type does not match body", body: readFixture(t, "ComputerAdded.json"), contentType: ContentTypeXML, want: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receiver := NewReceiver(ReceiverOptions{MaxBodyBytes: tt.maxBytes})
+			receiver.HandleAll(func(ctx context.Context, event *Event) error {
+				t.Error("handler ran for a rejected delivery")
+				return nil
+			})
+
+			method := tt.method
+			if method == "" {
+				method = http.MethodPost
+			}
+			req := httptest.NewRequest(method, "/webhooks", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+
+			rec := httptest.NewRecorder()
+			receiver.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestReceiverDispatch(t *testing.T) {
+	receiver := NewReceiver(ReceiverOptions{})
+
+	var calls []string
+	receiver.Handle(EventComputerPolicyFinished, func(ctx context.Context, event *Event) error {
+		finished := event.Payload.(*ComputerPolicyFinishedEvent)
+		if finished.PolicyID != 17 {
+			t.Errorf("PolicyID = %d, want 17", finished.PolicyID)
+		}
+		calls = append(calls, "typed")
+		return nil
+	})
+	receiver.HandleAll(func(ctx context.Context, event *Event) error {
+		calls = append(calls, "all:"+event.Type())
+		return nil
+	})
+
+	if got := deliver(t, receiver, readFixture(t, "ComputerPolicyFinished.xml"), ContentTypeXML, nil); got != http.StatusOK {
+		t.Fatalf("status = %d, want %d", got, http.StatusOK)
+	}
+
+	// An event type without a typed payload still reaches the catch-all handlers
+	unknown := `{"webhook":{"id":9,"webhookEvent":"SomethingNew","eventTimestamp":1717000000000},"event":{"answer":42}}`
+	if got := deliver(t, receiver, unknown, ContentTypeJSON, nil); got != http.StatusOK {
+		t.Fatalf("unknown event status = %d, want %d", got, http.StatusOK)
+	}
+
+	want := []string{"typed", "all:ComputerPolicyFinished", "all:SomethingNew"}
+	if strings.Join(calls, ",") != strings.Join(want, ",") {
+		t.Errorf("handler calls = %v, want %v", calls, want)
+	}
+}
+
+func TestReceiverHandlerError(t *testing.T) {
+	receiver := NewReceiver(ReceiverOptions{})
+	receiver.Handle(EventComputerAdded, func(ctx context.Context, event *Event) error {
+		return errors.New("downstream unavailable")
+	})
+
+	if got := deliver(t, receiver, readFixture(t, "ComputerAdded.json"), ContentTypeJSON, nil); got != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", got, http.StatusInternalServerError)
+	}
+}
@@ -2,58 +2,110 @@ package main
 
 import (
 	"bufio"
-	"bytes"
+	"flag"
 	"fmt"
-	"html"
 	"os"
 	"path/filepath"
 	"strings"
 
-	"howett.net/plist"
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/mobileconfig"
 )
 
-// RemoveEscapedCharacters removes escaped characters from a plist / .mobileconfig file
+// RemoveEscapedCharacters removes escaped characters from plist / .mobileconfig files.
+// Run without flags to be prompted for a single source file and destination folder, or use
+// -source and -dest to clean a file or a directory non-interactively:
+//
+//	RemoveEscapedCharacters -source ./profiles -dest ./cleaned -recursive
+//	RemoveEscapedCharacters -source ./profiles -in-place -recursive
 func main() {
+	sourcePath := flag.String("source", "", "Path to a plist file or a directory of .plist / .mobileconfig files")
+	destPath := flag.String("dest", "", "Destination folder for the cleaned files")
+	inPlace := flag.Bool("in-place", false, "Overwrite the source files instead of writing to -dest")
+	recursive := flag.Bool("recursive", false, "Process sub directories when -source is a directory")
+	flag.Parse()
+
+	if *sourcePath == "" {
+		if err := runInteractive(); err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *inPlace {
+		*destPath = *sourcePath
+		if info, err := os.Stat(*sourcePath); err == nil && !info.IsDir() {
+			*destPath = filepath.Dir(*sourcePath)
+		}
+	}
+
+	if *destPath == "" {
+		fmt.Println("Error: -dest is required unless -in-place is set")
+		os.Exit(1)
+	}
+
+	written, err := cleanPath(filepath.Clean(*sourcePath), filepath.Clean(*destPath), *recursive)
+	for _, path := range written {
+		fmt.Printf("Cleaned '%s'\n", path)
+	}
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	if len(written) == 0 {
+		fmt.Printf("Error: no plist files found in '%s'\n", *sourcePath)
+		os.Exit(1)
+	}
+
+	fmt.Printf("%d plist file(s) have been reformatted and written successfully.\n", len(written))
+}
+
+// runInteractive prompts for a single source file and destination folder
+func runInteractive() error {
 	reader := bufio.NewReader(os.Stdin)
 
 	// Prompt for and read the source plist file path
 	fmt.Print("Enter the path to the source plist file: ")
 	sourceFilePath, err := readInput(reader)
 	if err != nil {
-		fmt.Println("Error reading source file path:", err)
-		return
+		return fmt.Errorf("error reading source file path: %v", err)
 	}
 
 	// Prompt for and read the destination folder path
 	fmt.Print("Enter the destination folder path: ")
 	destFolderPath, err := readInput(reader)
 	if err != nil {
-		fmt.Println("Error reading destination folder path:", err)
-		return
+		return fmt.Errorf("error reading destination folder path: %v", err)
 	}
 
-	// Read and decode the plist file content
-	data, err := decodePlistFile(sourceFilePath)
+	written, err := cleanPath(sourceFilePath, destFolderPath, false)
 	if err != nil {
-		fmt.Println("Error:", err)
-		return
+		return err
 	}
+	if len(written) == 0 {
+		return fmt.Errorf("no plist files found in '%s'", sourceFilePath)
+	}
+
+	fmt.Printf("Plist file has been reformatted and written successfully to '%s'.\n", written[0])
+	return nil
+}
 
-	// Re-encode to XML to ensure correct formatting
-	newPlist, err := plist.MarshalIndent(data, plist.XMLFormat, "\t")
+// cleanPath cleans a single file into destFolder, or every plist in a directory when source is a directory
+func cleanPath(source, destFolder string, recursive bool) ([]string, error) {
+	info, err := os.Stat(source)
 	if err != nil {
-		fmt.Println("Error marshaling plist:", err)
-		return
+		return nil, fmt.Errorf("error reading '%s': %v", source, err)
 	}
 
-	// Construct the output file path and write the new plist content
-	outputFilePath := filepath.Join(destFolderPath, filepath.Base(sourceFilePath))
-	if err := writeFile(outputFilePath, newPlist); err != nil {
-		fmt.Println("Error writing file:", err)
-		return
+	if info.IsDir() {
+		return mobileconfig.CleanDirectory(source, destFolder, recursive)
 	}
 
-	fmt.Printf("Plist file has been reformatted and written successfully to '%s'.\n", outputFilePath)
+	outputFilePath := filepath.Join(destFolder, filepath.Base(source))
+	if err := mobileconfig.CleanFile(source, outputFilePath); err != nil {
+		return nil, err
+	}
+	return []string{outputFilePath}, nil
 }
 
 // ReadInput simplifies reading a line of text input
@@ -64,44 +116,3 @@ func readInput(reader *bufio.Reader) (string, error) {
 	}
 	return filepath.Clean(strings.TrimSpace(input)), nil
 }
-
-// DecodePlistFile handles reading and unmarshaling a plist file
-func decodePlistFile(filePath string) (interface{}, error) {
-	file, err := os.Open(filePath)
-	if err != nil {
-		return nil, fmt.Errorf("error opening file '%s': %v", filePath, err)
-	}
-	defer file.Close()
-
-	// Read the file content
-	var buf bytes.Buffer
-	if _, err := buf.ReadFrom(file); err != nil {
-		return nil, fmt.Errorf("error reading file '%s': %v", filePath, err)
-	}
-	content := buf.Bytes()
-
-	// Check if content needs unescaping
-	if bytes.Contains(content, []byte("&lt;")) {
-		content = []byte(UnescapeXML(string(content)))
-	}
-
-	decoder := plist.NewDecoder(bytes.NewReader(content))
-	var data interface{}
-	if err := decoder.Decode(&data); err != nil {
-		return nil, fmt.Errorf("error decoding plist: %v", err)
-	}
-	return data, nil
-}
-
-// UnescapeXML takes an XML string with HTML entities and returns the unescaped XML.
-func UnescapeXML(encodedXML string) string {
-	return html.UnescapeString(encodedXML)
-}
-
-// WriteFile handles the file writing process
-func writeFile(filePath string, data []byte) error {
-	if err := os.WriteFile(filePath, data, 0644); err != nil {
-		return fmt.Errorf("error writing file '%s': %v", filePath, err)
-	}
-	return nil
-}
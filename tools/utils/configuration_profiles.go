@@ -5,6 +5,7 @@ import (
 	"io"
 	"os"
 
+	"github.com/deploymenttheory/go-api-sdk-jamfpro/sdk/mobileconfig"
 	"github.com/mitchellh/mapstructure"
 	"howett.net/plist"
 )
@@ -99,17 +100,8 @@ func ComparePayloads(payloads1, payloads2 []map[string]interface{}) bool {
 	return true
 }
 
-// compareMaps compares two maps and returns true if they are equal
+// compareMaps compares two maps and returns true if they are semantically equal. Nested dictionaries and
+// arrays are compared deeply and plist types are normalised, see mobileconfig.EqualValues.
 func compareMaps(map1, map2 map[string]interface{}) bool {
-	if len(map1) != len(map2) {
-		return false
-	}
-
-	for key, val1 := range map1 {
-		if val2, ok := map2[key]; !ok || val1 != val2 {
-			return false
-		}
-	}
-
-	return true
+	return mobileconfig.EqualValues(map1, map2)
 }